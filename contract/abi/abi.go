@@ -3,67 +3,404 @@ package abi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rootwarp/vinculum/contract/internal/retry"
+	"golang.org/x/time/rate"
 )
 
 // ABI is an interface for fetching contract ABIs
 type ABI interface {
 	GetContractABI(ctx context.Context, address string) (ContractABIs, error)
+	GetSourceCode(ctx context.Context, address string) (*SourceCode, error)
+	GetContractCreation(ctx context.Context, address string) (creator string, txHash string, err error)
+	GetContractCreations(ctx context.Context, addresses []string) ([]ContractCreation, error)
 }
 
+var (
+	// ErrHostNotAllowed is returned when the API base URL points to a host outside the allowlist
+	ErrHostNotAllowed = errors.New("host not allowed")
+	// ErrContractNotVerified is returned when the explorer has no verified source, and so no ABI, for the contract
+	ErrContractNotVerified = errors.New("contract source code not verified")
+	// ErrRateLimited is returned when the explorer refuses a request for exceeding the rate limit of the API key.
+	// Unlike ErrContractNotVerified, the request may succeed later.
+	ErrRateLimited = errors.New("rate limit reached")
+)
+
 type etherscanABI struct {
-	apiBaseURL string
-	apiKey     string
+	apiBaseURL    string
+	apiKey        string
+	allowedHosts  []string
+	strict        bool
+	httpClient    *http.Client
+	timeout       time.Duration
+	retryAttempts int
+	retryDelay    time.Duration
+	clock         Clock
+	cache         Cache
+	limiter       *rate.Limiter
+	chainID       uint64
+	legacyLayout  bool
+
+	flightTimeout time.Duration
+	flightsMu     sync.Mutex
+	flights       map[string]*abiFlight
+}
+
+// defaultFlightTimeout bounds an ABI fetch shared by concurrent lookups, which outlives the contexts of its callers,
+// unless set with WithSharedFetchTimeout
+const defaultFlightTimeout = time.Minute
+
+// abiFlight is an ABI fetch in progress, shared by concurrent lookups of the same address
+type abiFlight struct {
+	done chan struct{}
+	abis ContractABIs
+	err  error
 }
 
-// GetContractABI fetches the ABI for a given contract address from the Etherscan API
+// GetContractABI fetches the ABI for a given contract address from the Etherscan API.
+// Concurrent lookups of the same address share a single request, which a caller giving up on its context
+// doesn't cancel for the others. The shared request is bounded as set by WithSharedFetchTimeout.
 func (e *etherscanABI) GetContractABI(ctx context.Context, address string) (ContractABIs, error) {
-	url := fmt.Sprintf("%s/api?module=contract&action=getabi&address=%s&apikey=%s", e.apiBaseURL, address, e.apiKey)
+	key := strings.ToLower(address)
+	if e.cache != nil {
+		if abis, ok := e.cache.Get(key); ok {
+			return abis, nil
+		}
+	}
+
+	e.flightsMu.Lock()
+	// A flight may have filled the cache and ended since it was checked
+	if e.cache != nil {
+		if abis, ok := e.cache.Get(key); ok {
+			e.flightsMu.Unlock()
+			return abis, nil
+		}
+	}
+	f, ok := e.flights[key]
+	if !ok {
+		f = &abiFlight{done: make(chan struct{})}
+		if e.flights == nil {
+			e.flights = make(map[string]*abiFlight)
+		}
+		e.flights[key] = f
+		go e.fly(context.WithoutCancel(ctx), e.flightDeadline(ctx), key, address, f)
+	}
+	e.flightsMu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.abis, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flightDeadline returns when a flight started by a lookup with ctx ends: once the flight timeout has elapsed,
+// or at the deadline of ctx when it is later
+func (e *etherscanABI) flightDeadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(e.flightTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.After(deadline) {
+		return ctxDeadline
+	}
+	return deadline
+}
+
+// fly fetches the ABI of address for the flight f, keeping the values of ctx but bounded by deadline
+func (e *etherscanABI) fly(ctx context.Context, deadline time.Time, key, address string, f *abiFlight) {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	f.abis, f.err = e.fetchContractABI(ctx, address)
+	// The cache is filled before the flight ends, so later lookups find it there
+	if f.err == nil && e.cache != nil {
+		e.cache.Set(key, f.abis)
+	}
+	e.flightsMu.Lock()
+	delete(e.flights, key)
+	e.flightsMu.Unlock()
+	close(f.done)
+}
+
+// fetchContractABI queries the explorer API for the ABI of address
+func (e *etherscanABI) fetchContractABI(ctx context.Context, address string) (ContractABIs, error) {
+	result, err := e.query(ctx, "getabi", "address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	var abiJSON string
+	if err := json.Unmarshal(result, &abiJSON); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal API response: %w", err)
+	}
+
+	var opts []ParseOption
+	if e.strict {
+		opts = append(opts, StrictParsing())
+	}
+	return ParseABI([]byte(abiJSON), opts...)
+}
+
+// GetSourceCode fetches the verified source metadata for a given contract address from the Etherscan API
+func (e *etherscanABI) GetSourceCode(ctx context.Context, address string) (*SourceCode, error) {
+	result, err := e.query(ctx, "getsourcecode", "address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []SourceCode
+	if err := json.Unmarshal(result, &sources); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal source code: %w", err)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no source code returned for %s", address)
+	}
+
+	return &sources[0], nil
+}
+
+// maxCreationAddresses is the number of addresses the getcontractcreation action accepts at once
+const maxCreationAddresses = 5
+
+// ContractCreation is the deployment of a contract
+type ContractCreation struct {
+	// Address is the address of the contract
+	Address string
+	// Creator deployed the contract, i.e. the factory for a contract deployed by another contract
+	Creator string
+	// TxHash is the hash of the transaction which created the contract
+	TxHash string
+}
+
+// contractCreation is an entry of the getcontractcreation result.
+// Explorers reporting factories separately return the sender of the transaction as the creator.
+type contractCreation struct {
+	ContractAddress string `json:"contractAddress"`
+	ContractCreator string `json:"contractCreator"`
+	ContractFactory string `json:"contractFactory"`
+	TxHash          string `json:"txHash"`
+}
+
+// GetContractCreation fetches the creator and the creation transaction of a contract from the Etherscan API.
+// For a contract deployed by a factory, the creator is the factory.
+func (e *etherscanABI) GetContractCreation(ctx context.Context, address string) (string, string, error) {
+	creations, err := e.GetContractCreations(ctx, []string{address})
+	if err != nil {
+		return "", "", err
+	}
+	return creations[0].Creator, creations[0].TxHash, nil
+}
+
+// GetContractCreations fetches the creation of several contracts like GetContractCreation, in the order of addresses.
+// The explorer is queried for up to 5 addresses at once.
+func (e *etherscanABI) GetContractCreations(ctx context.Context, addresses []string) ([]ContractCreation, error) {
+	byAddress := make(map[string]ContractCreation, len(addresses))
+	for start := 0; start < len(addresses); start += maxCreationAddresses {
+		end := min(start+maxCreationAddresses, len(addresses))
+		result, err := e.query(ctx, "getcontractcreation", "contractaddresses", strings.Join(addresses[start:end], ","))
+		if err != nil {
+			return nil, err
+		}
+
+		var entries []contractCreation
+		if err := json.Unmarshal(result, &entries); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal contract creation: %w", err)
+		}
+		for _, entry := range entries {
+			creator := entry.ContractCreator
+			if entry.ContractFactory != "" {
+				creator = entry.ContractFactory
+			}
+			byAddress[strings.ToLower(entry.ContractAddress)] = ContractCreation{
+				Address: entry.ContractAddress,
+				Creator: creator,
+				TxHash:  entry.TxHash,
+			}
+		}
+	}
+
+	creations := make([]ContractCreation, len(addresses))
+	for i, address := range addresses {
+		creation, ok := byAddress[strings.ToLower(address)]
+		if !ok {
+			return nil, fmt.Errorf("no contract creation returned for %s", address)
+		}
+		creations[i] = creation
+	}
+	return creations, nil
+}
+
+// apiEnvelope is the top-level explorer response with the result left undecoded
+type apiEnvelope struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// query calls a contract module action with a single parameter, e.g. the address, and returns the raw result field
+func (e *etherscanABI) query(ctx context.Context, action, param, value string) (json.RawMessage, error) {
+	endpoint, err := e.endpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	if !e.legacyLayout {
+		params.Set("chainid", strconv.FormatUint(e.chainID, 10))
+	}
+	params.Set("module", "contract")
+	params.Set("action", action)
+	params.Set(param, value)
+	params.Set("apikey", e.apiKey)
+	rawURL := endpoint + "?" + params.Encode()
+	if err := e.checkHost(rawURL); err != nil {
+		return nil, err
+	}
+
+	content, err := e.getWithRetry(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResp apiEnvelope
+	if err := json.Unmarshal(content, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal API response: %w", err)
+	}
+
+	if apiResp.Status != "1" || apiResp.Message != "OK" {
+		return nil, apiResp.err()
+	}
+
+	return apiResp.Result, nil
+}
+
+// endpoint returns the URL of the API: apiBaseURL/v2/api for the Etherscan V2 API, where apiBaseURL may already
+// end with /v2, or apiBaseURL/api for the per-chain layout
+func (e *etherscanABI) endpoint() (string, error) {
+	base := strings.TrimSuffix(e.apiBaseURL, "/")
+	if e.legacyLayout {
+		return base + "/api", nil
+	}
+	if e.chainID == 0 {
+		return "", fmt.Errorf("no chain ID for the Etherscan V2 API: set WithChainID, or WithLegacyLayout for a per-chain explorer")
+	}
+	return strings.TrimSuffix(base, "/v2") + "/v2/api", nil
+}
+
+// err returns the error of a failed response, recognizing failures callers may handle from the result,
+// which explorers set to a description of the error
+func (r *apiEnvelope) err() error {
+	var detail string
+	if err := json.Unmarshal(r.Result, &detail); err != nil {
+		return fmt.Errorf("API error: %s", r.Message)
+	}
+
+	lower := strings.ToLower(detail)
+	switch {
+	case strings.Contains(lower, "not verified"):
+		return fmt.Errorf("%w: %s", ErrContractNotVerified, detail)
+	case strings.Contains(lower, "rate limit"):
+		return fmt.Errorf("%w: %s", ErrRateLimited, detail)
+	}
+	return fmt.Errorf("API error: %s", r.Message)
+}
+
+// getWithRetry fetches url, retrying transient failures as configured by WithRetry
+func (e *etherscanABI) getWithRetry(ctx context.Context, url string) ([]byte, error) {
+	return retry.Do(ctx, e.retryAttempts, e.retryDelay, e.clock.After, func() ([]byte, error) {
+		return e.get(ctx, url)
+	})
+}
+
+// get fetches url and returns the response body
+func (e *etherscanABI) get(ctx context.Context, url string) ([]byte, error) {
+	if e.limiter != nil {
+		if err := e.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limit: %w", err)
+		}
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	cli := http.Client{}
-	resp, err := cli.Do(req)
+	resp, err := e.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &retry.StatusError{StatusCode: resp.StatusCode}
 	}
 
 	content, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	return content, nil
+}
 
-	var apiResp APIResponse
-	if err := json.Unmarshal(content, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal API response: %w", err)
+// checkHost verifies the host of rawURL is allowed to receive the API key
+func (e *etherscanABI) checkHost(rawURL string) error {
+	if len(e.allowedHosts) == 0 {
+		return nil
 	}
 
-	if apiResp.Status != "1" || apiResp.Message != "OK" {
-		return nil, fmt.Errorf("API error: %s", apiResp.Message)
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid API URL: %w", err)
 	}
-
-	var contractABIs ContractABIs
-	if err := json.Unmarshal([]byte(apiResp.Result), &contractABIs); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal contract ABIs: %w", err)
+	for _, host := range e.allowedHosts {
+		if strings.EqualFold(u.Hostname(), host) {
+			return nil
+		}
 	}
+	return fmt.Errorf("%w: %q", ErrHostNotAllowed, u.Hostname())
+}
 
-	return contractABIs, nil
+// EtherscanBaseURL is the base URL of the Etherscan API, which serves every chain it supports through WithChainID
+const EtherscanBaseURL = "https://api.etherscan.io"
+
+// realClock is the Clock of the time package
+type realClock struct{}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
 }
 
-// NewABIClient creates a new ABI client
-func NewABIClient(apiBaseURL, apiKey string) ABI {
-	return &etherscanABI{
-		apiBaseURL: apiBaseURL,
-		apiKey:     apiKey,
+// NewABIClient creates a new ABI client for the explorer API at apiBaseURL.
+// By default it targets the chain set by WithChainID through the Etherscan V2 API, e.g.
+// NewABIClient(EtherscanBaseURL, apiKey, WithChainID(137)) for Polygon.
+// With WithLegacyLayout, it uses the per-chain layout of explorers like api.polygonscan.com instead.
+func NewABIClient(apiBaseURL, apiKey string, opts ...Option) ABI {
+	e := &etherscanABI{
+		apiBaseURL:    apiBaseURL,
+		apiKey:        apiKey,
+		clock:         realClock{},
+		flightTimeout: defaultFlightTimeout,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if e.httpClient == nil {
+		e.httpClient = http.DefaultClient
+	}
+	if e.timeout > 0 {
+		// The configured client is copied rather than modified, as it may be shared
+		withTimeout := *e.httpClient
+		withTimeout.Timeout = e.timeout
+		e.httpClient = &withTimeout
 	}
+	return e
 }
@@ -5,12 +5,17 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"math/big"
 	"net/http"
+	"net/url"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/jarcoal/httpmock"
@@ -29,10 +34,10 @@ func TestAbi_Parse(t *testing.T) {
 	// Register mock response
 	httpmock.RegisterResponder(
 		http.MethodGet,
-		`=~^https://api\.polygonscan\.com/api\?module=contract&action=getabi&address=`,
+		`=~^https://api\.polygonscan\.com/api\?action=getabi&address=`,
 		httpmock.NewStringResponder(http.StatusOK, string(mockRespBody)))
 
-	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY")
+	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout())
 
 	// Parse the result string into ContractABI slice
 	contractABIs, err := abiClient.GetContractABI(context.Background(), "CONTRACT_ADDRESS")
@@ -89,6 +94,109 @@ func TestAbi_MethodID(t *testing.T) {
 	assert.Equal(t, "23b872dd", methodID)
 }
 
+func TestAbi_MethodIDAddressPayable(t *testing.T) {
+	transfer := ContractABI{
+		Name: "transfer",
+		Type: "function",
+		Inputs: []ABIParameter{
+			{Name: "to", Type: "address payable"},
+			{Name: "value", Type: "uint256"},
+		},
+	}
+
+	// Selectors use the plain address type
+	methodID, err := transfer.MethodID()
+	require.NoError(t, err)
+	assert.Equal(t, "a9059cbb", methodID)
+}
+
+func TestAbi_MethodIDAliases(t *testing.T) {
+	transfer := ContractABI{
+		Name: "transfer",
+		Type: "function",
+		Inputs: []ABIParameter{
+			{Name: "to", Type: "address"},
+			{Name: "value", Type: "uint"},
+		},
+	}
+
+	// uint is hashed as uint256
+	methodID, err := transfer.MethodID()
+	require.NoError(t, err)
+	assert.Equal(t, "a9059cbb", methodID)
+
+	for alias, canonical := range map[string]string{
+		"uint":      "uint256",
+		"int[]":     "int256[]",
+		"uint[2][]": "uint256[2][]",
+		"fixed":     "fixed128x18",
+		"ufixed[3]": "ufixed128x18[3]",
+		"byte":      "bytes1",
+		"uint8":     "uint8",
+		"bytes":     "bytes",
+		"uint256":   "uint256",
+	} {
+		assert.Equal(t, canonical, CanonicalType(alias), alias)
+	}
+
+	// Aliases in tuple components too
+	fn := ContractABI{
+		Name: "setFee",
+		Type: "function",
+		Inputs: []ABIParameter{{
+			Type:       "tuple[]",
+			Components: []ABIParameter{{Type: "int"}, {Type: "address payable"}},
+		}},
+	}
+	methodID, err = fn.MethodID()
+	require.NoError(t, err)
+	hash := crypto.Keccak256([]byte("setFee((int256,address)[])"))
+	assert.Equal(t, hex.EncodeToString(hash[:4]), methodID)
+}
+
+func TestAbi_MethodIDTuple(t *testing.T) {
+	// Uniswap V3 exactInputSingle((address,address,uint24,address,uint256,uint256,uint256,uint160))
+	exactInputSingle := ContractABI{
+		Name: "exactInputSingle",
+		Type: "function",
+		Inputs: []ABIParameter{{
+			Name: "params",
+			Type: "tuple",
+			Components: []ABIParameter{
+				{Name: "tokenIn", Type: "address"},
+				{Name: "tokenOut", Type: "address"},
+				{Name: "fee", Type: "uint24"},
+				{Name: "recipient", Type: "address"},
+				{Name: "deadline", Type: "uint256"},
+				{Name: "amountIn", Type: "uint256"},
+				{Name: "amountOutMinimum", Type: "uint256"},
+				{Name: "sqrtPriceLimitX96", Type: "uint160"},
+			},
+		}},
+	}
+	methodID, err := exactInputSingle.MethodID()
+	require.NoError(t, err)
+	assert.Equal(t, "414bf389", methodID)
+
+	// Arrays of tuples keep their suffix after the expanded components
+	multicall := ContractABI{
+		Name: "aggregate3",
+		Type: "function",
+		Inputs: []ABIParameter{{
+			Name: "calls",
+			Type: "tuple[]",
+			Components: []ABIParameter{
+				{Name: "target", Type: "address"},
+				{Name: "allowFailure", Type: "bool"},
+				{Name: "callData", Type: "bytes"},
+			},
+		}},
+	}
+	methodID, err = multicall.MethodID()
+	require.NoError(t, err)
+	assert.Equal(t, "82ad56cb", methodID)
+}
+
 func TestAbi_ReadContract(t *testing.T) {
 	// polygon
 	// contract address: 0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270
@@ -185,3 +293,928 @@ func TestAbi_ReadContract(t *testing.T) {
 
 	t.Logf("balanceOf eth_call response: %s", string(body))
 }
+
+func TestAbi_EventTopic(t *testing.T) {
+	d, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+
+	var apiResp APIResponse
+	err = json.Unmarshal(d, &apiResp)
+	require.NoError(t, err)
+
+	var contractABIs ContractABIs
+	err = json.Unmarshal([]byte(apiResp.Result), &contractABIs)
+	require.NoError(t, err)
+
+	transferEvent, err := contractABIs.Find("Transfer")
+	require.NoError(t, err)
+
+	// Transfer(address,address,uint256)
+	topic, err := transferEvent.EventTopic()
+	require.NoError(t, err)
+	assert.Equal(t, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", topic)
+
+	approveFunc, err := contractABIs.Find("approve")
+	require.NoError(t, err)
+
+	_, err = approveFunc.EventTopic()
+	assert.Error(t, err)
+}
+
+func TestAbi_EventTopicSignatures(t *testing.T) {
+	addr := func(name string) ABIParameter { return ABIParameter{Name: name, Type: "address", Indexed: true} }
+
+	// Indexing doesn't change the signature, only names and types of the inputs in order
+	for _, tc := range []struct {
+		event ContractABI
+		topic string
+	}{
+		{
+			event: ContractABI{Type: "event", Name: "Approval", Inputs: []ABIParameter{
+				addr("owner"), addr("spender"), {Name: "value", Type: "uint256"},
+			}},
+			topic: "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925",
+		},
+		{
+			event: ContractABI{Type: "event", Name: "TransferSingle", Inputs: []ABIParameter{
+				addr("operator"), addr("from"), addr("to"), {Name: "id", Type: "uint256"}, {Name: "value", Type: "uint256"},
+			}},
+			topic: "0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62",
+		},
+		{
+			event: ContractABI{Type: "event", Name: "TransferBatch", Inputs: []ABIParameter{
+				addr("operator"), addr("from"), addr("to"), {Name: "ids", Type: "uint256[]"}, {Name: "values", Type: "uint256[]"},
+			}},
+			topic: "0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb",
+		},
+		{
+			// Payable addresses hash as plain addresses
+			event: ContractABI{Type: "event", Name: "Transfer", Inputs: []ABIParameter{
+				{Name: "from", Type: "address payable", Indexed: true}, addr("to"), {Name: "value", Type: "uint256"},
+			}},
+			topic: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		},
+	} {
+		topic, err := tc.event.EventTopic()
+		require.NoError(t, err)
+		assert.Equal(t, tc.topic, topic, tc.event.Name)
+	}
+
+	// Functions and errors have no topic
+	for _, typ := range []string{"function", "error", ""} {
+		_, err := (&ContractABI{Type: typ, Name: "Transfer"}).EventTopic()
+		assert.Error(t, err)
+	}
+}
+
+func TestAbi_AllowedHosts(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	mockRespBody, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+
+	httpmock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusOK, string(mockRespBody)))
+
+	ctx := context.Background()
+
+	allowed := NewABIClient("https://API.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout(), WithAllowedHosts("api.polygonscan.com"))
+	_, err = allowed.GetContractABI(ctx, "CONTRACT_ADDRESS")
+	require.NoError(t, err)
+
+	denied := NewABIClient("https://attacker.example.com", "DUMMY_API_KEY", WithLegacyLayout(), WithAllowedHosts("api.polygonscan.com"))
+	_, err = denied.GetContractABI(ctx, "CONTRACT_ADDRESS")
+	require.ErrorIs(t, err, ErrHostNotAllowed)
+
+	// The key never left the process
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+
+	// No allowlist, no restriction
+	unrestricted := NewABIClient("https://attacker.example.com", "DUMMY_API_KEY", WithLegacyLayout())
+	_, err = unrestricted.GetContractABI(ctx, "CONTRACT_ADDRESS")
+	require.NoError(t, err)
+}
+
+func TestAbi_GetSourceCode(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		`=~^https://api\.polygonscan\.com/api\?action=getsourcecode&address=`,
+		httpmock.NewStringResponder(http.StatusOK, `{"status":"1","message":"OK","result":[{
+			"SourceCode":"pragma solidity ^0.4.18;","ABI":"[]","ContractName":"WMATIC",
+			"CompilerVersion":"v0.4.18+commit.9cf6e910","OptimizationUsed":"0","Runs":"200",
+			"ConstructorArguments":"","EVMVersion":"Default","Library":"","LicenseType":"None",
+			"Proxy":"0","Implementation":"","SwarmSource":""}]}`))
+
+	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout())
+
+	source, err := abiClient.GetSourceCode(context.Background(), "CONTRACT_ADDRESS")
+	require.NoError(t, err)
+	assert.Equal(t, "WMATIC", source.ContractName)
+	assert.Equal(t, "v0.4.18+commit.9cf6e910", source.CompilerVersion)
+	assert.Equal(t, "pragma solidity ^0.4.18;", source.SourceCode)
+
+	// Unverified contracts are reported as such
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		`=~^https://api\.polygonscan\.com/api\?action=getsourcecode&address=`,
+		httpmock.NewStringResponder(http.StatusOK, `{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`))
+
+	_, err = abiClient.GetSourceCode(context.Background(), "CONTRACT_ADDRESS")
+	require.ErrorIs(t, err, ErrContractNotVerified)
+}
+
+func TestAbi_StrictParsing(t *testing.T) {
+	d, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+
+	var apiResp APIResponse
+	require.NoError(t, json.Unmarshal(d, &apiResp))
+
+	contractABIs, err := ParseABI([]byte(apiResp.Result), StrictParsing())
+	require.NoError(t, err)
+	assert.Len(t, contractABIs, 16)
+
+	modern := `[{"type":"function","name":"balanceOf","stateMutability":"view",
+		"inputs":[{"name":"account","type":"address","internalType":"address"}],
+		"outputs":[{"name":"","type":"uint256","internalType":"uint256"}]}]`
+	contractABIs, err = ParseABI([]byte(modern), StrictParsing())
+	require.NoError(t, err)
+	assert.Equal(t, "address", contractABIs[0].Inputs[0].InternalType)
+
+	// The gas estimate of old Vyper ABIs is not modeled
+	withGas := `[{"type":"function","name":"balanceOf","stateMutability":"view","gas":1234,
+		"inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}]`
+	_, err = ParseABI([]byte(withGas))
+	require.NoError(t, err)
+	_, err = ParseABI([]byte(withGas), StrictParsing())
+	require.ErrorContains(t, err, "gas")
+
+	wrapped, err := json.Marshal(APIResponse{Status: "1", Message: "OK", Result: withGas})
+	require.NoError(t, err)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		`=~^https://api\.polygonscan\.com/api\?action=getabi&address=`,
+		httpmock.NewBytesResponder(http.StatusOK, wrapped))
+
+	_, err = NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout()).GetContractABI(context.Background(), "CONTRACT_ADDRESS")
+	require.NoError(t, err)
+
+	_, err = NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout(), WithStrictABI()).GetContractABI(context.Background(), "CONTRACT_ADDRESS")
+	require.ErrorContains(t, err, "gas")
+}
+
+func TestAbi_SelectorTable(t *testing.T) {
+	d, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+
+	var apiResp APIResponse
+	require.NoError(t, json.Unmarshal(d, &apiResp))
+	contractABIs, err := ParseABI([]byte(apiResp.Result))
+	require.NoError(t, err)
+
+	table, err := contractABIs.SelectorTable()
+	require.NoError(t, err)
+	// 11 functions, the fallback and the events are skipped
+	assert.Len(t, table, 11)
+	assert.Equal(t, "approve", table["095ea7b3"].Name)
+	assert.Equal(t, "transferFrom", table["23b872dd"].Name)
+	assert.Equal(t, "balanceOf", table["70a08231"].Name)
+
+	// Known colliding signatures
+	colliding := ContractABIs{
+		{Name: "collate_propagate_storage", Type: "function", Inputs: []ABIParameter{{Type: "bytes16"}}},
+		{Name: "burn", Type: "function", Inputs: []ABIParameter{{Type: "uint256"}}},
+	}
+	_, err = colliding.SelectorTable()
+	require.ErrorContains(t, err, "selector collision on 42966c68")
+}
+
+func TestAbi_Hash(t *testing.T) {
+	d, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+
+	var apiResp APIResponse
+	require.NoError(t, json.Unmarshal(d, &apiResp))
+	contractABIs, err := ParseABI([]byte(apiResp.Result))
+	require.NoError(t, err)
+
+	hash := contractABIs.Hash()
+	assert.Len(t, hash, 66)
+	assert.True(t, strings.HasPrefix(hash, "0x"))
+
+	// Reordering entries yields the same hash
+	reversed := make(ContractABIs, len(contractABIs))
+	for i, entry := range contractABIs {
+		reversed[len(contractABIs)-1-i] = entry
+	}
+	assert.Equal(t, hash, reversed.Hash())
+
+	// So does reordering the fields of the JSON
+	a, err := ParseABI([]byte(`[{"type":"function","name":"f","inputs":[{"name":"x","type":"uint256"}],"outputs":[],"stateMutability":"view"}]`))
+	require.NoError(t, err)
+	b, err := ParseABI([]byte(`[{"stateMutability":"view","outputs":[],"inputs":[{"type":"uint256","name":"x"}],"name":"f","type":"function"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, a.Hash(), b.Hash())
+
+	// Any change, e.g. after an upgrade, changes the hash
+	changed := append(ContractABIs{}, contractABIs...)
+	changed[0].StateMutability = "payable"
+	assert.NotEqual(t, hash, changed.Hash())
+	assert.NotEqual(t, hash, contractABIs[1:].Hash())
+}
+
+func TestAbi_GetContractCreation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var queried []string
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		`=~^https://api\.polygonscan\.com/api\?action=getcontractcreation&apikey=DUMMY_API_KEY&contractaddresses=`,
+		func(req *http.Request) (*http.Response, error) {
+			addresses := strings.Split(req.URL.Query().Get("contractaddresses"), ",")
+			queried = append(queried, req.URL.Query().Get("contractaddresses"))
+
+			entries := make([]string, 0, len(addresses))
+			for _, address := range addresses {
+				switch strings.ToLower(address) {
+				case "0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270":
+					entries = append(entries, `{"contractAddress":"0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270",
+						"contractCreator":"0x5c3f2fbaa2e0ea6ab0fec1f4f5fe3c7bcd68c68c",
+						"txHash":"0xd9de55d4b5fe3bb4ab3a6aab7e0d8d3a8a7a3e0ac6b7f9a9a1f4a8d0f2d1c0b1"}`)
+				case "0x6e7a5fafcec6bb1e78bae2a1f0b612012bf14827":
+					// Deployed by a factory
+					entries = append(entries, `{"contractAddress":"0x6e7a5fafcec6bb1e78bae2a1f0b612012bf14827",
+						"contractCreator":"0x1f98407aab862cddef78ed252d6f557aa5b0f00d",
+						"contractFactory":"0x5757371414417b8c6caad45baef941abc7d3ab32",
+						"txHash":"0x5e2b4d5f2f0c1d3a7f3f7a2b9a8c1d0e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c"}`)
+				}
+			}
+			return httpmock.NewStringResponse(http.StatusOK, `{"status":"1","message":"OK","result":[`+strings.Join(entries, ",")+`]}`), nil
+		})
+
+	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout())
+	ctx := context.Background()
+
+	creator, txHash, err := abiClient.GetContractCreation(ctx, "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270")
+	require.NoError(t, err)
+	assert.Equal(t, "0x5c3f2fbaa2e0ea6ab0fec1f4f5fe3c7bcd68c68c", creator)
+	assert.Equal(t, "0xd9de55d4b5fe3bb4ab3a6aab7e0d8d3a8a7a3e0ac6b7f9a9a1f4a8d0f2d1c0b1", txHash)
+
+	creator, _, err = abiClient.GetContractCreation(ctx, "0x6e7a5fafcec6bb1e78bae2a1f0b612012bf14827")
+	require.NoError(t, err)
+	assert.Equal(t, "0x5757371414417b8c6caad45baef941abc7d3ab32", creator)
+
+	// Batches are split at the explorer limit and keep the order of the addresses
+	queried = nil
+	addresses := []string{
+		"0x6e7a5fafcec6bb1e78bae2a1f0b612012bf14827",
+		"0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270",
+		"0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270",
+		"0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270",
+		"0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270",
+		"0x6e7a5fafcec6bb1e78bae2a1f0b612012bf14827",
+	}
+	creations, err := abiClient.GetContractCreations(ctx, addresses)
+	require.NoError(t, err)
+	require.Len(t, creations, len(addresses))
+	assert.Len(t, queried, 2)
+	for i, creation := range creations {
+		assert.Equal(t, addresses[i], creation.Address)
+	}
+	assert.Equal(t, "0x5757371414417b8c6caad45baef941abc7d3ab32", creations[5].Creator)
+
+	_, _, err = abiClient.GetContractCreation(ctx, "0x0000000000000000000000000000000000000001")
+	assert.ErrorContains(t, err, "no contract creation")
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestAbi_HTTPClient(t *testing.T) {
+	mockRespBody, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+
+	var requested []string
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requested = append(requested, req.URL.Query().Get("action"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(mockRespBody)),
+			Request:    req,
+		}, nil
+	})
+
+	ctx := context.Background()
+	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout(), WithHTTPClient(&http.Client{Transport: rt}))
+	abis, err := abiClient.GetContractABI(ctx, "CONTRACT_ADDRESS")
+	require.NoError(t, err)
+	assert.NotEmpty(t, abis)
+	assert.Equal(t, []string{"getabi"}, requested)
+
+	// A hung explorer is abandoned after the timeout
+	hung := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+	httpClient := &http.Client{Transport: hung}
+	abiClient = NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout(), WithHTTPClient(httpClient), WithTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	_, err = abiClient.GetContractABI(ctx, "CONTRACT_ADDRESS")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Zero(t, httpClient.Timeout)
+}
+
+// instantClock is a Clock recording the waits it is asked for, which all elapse at once
+type instantClock struct {
+	waits []time.Duration
+}
+
+func (c *instantClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+func TestAbi_Retry(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	mockRespBody, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+
+	// The explorer rate limits twice before answering
+	statuses := []int{http.StatusTooManyRequests, http.StatusBadGateway}
+	httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+		if len(statuses) > 0 {
+			status := statuses[0]
+			statuses = statuses[1:]
+			return httpmock.NewStringResponse(status, ""), nil
+		}
+		return httpmock.NewBytesResponse(http.StatusOK, mockRespBody), nil
+	})
+
+	ctx := context.Background()
+	clock := &instantClock{}
+	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout(), WithRetry(3, time.Hour), WithClock(clock))
+	abis, err := abiClient.GetContractABI(ctx, "CONTRACT_ADDRESS")
+	require.NoError(t, err)
+	assert.NotEmpty(t, abis)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+
+	// Retries wait on the clock, an hour and then two doubled with jitter
+	require.Len(t, clock.waits, 2)
+	assert.GreaterOrEqual(t, clock.waits[0], 30*time.Minute)
+	assert.GreaterOrEqual(t, clock.waits[1], time.Hour)
+
+	// Without retries the first failure is returned
+	httpmock.ZeroCallCounters()
+	statuses = []int{http.StatusServiceUnavailable}
+	_, err = NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout()).GetContractABI(ctx, "CONTRACT_ADDRESS")
+	assert.ErrorContains(t, err, "unexpected status code: 503")
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+
+	// Other failures aren't retried
+	httpmock.ZeroCallCounters()
+	statuses = []int{http.StatusForbidden}
+	_, err = abiClient.GetContractABI(ctx, "CONTRACT_ADDRESS")
+	assert.ErrorContains(t, err, "unexpected status code: 403")
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestAbi_ParseSignature(t *testing.T) {
+	balanceOf, err := ParseSignature("balanceOf(address) returns (uint256)")
+	require.NoError(t, err)
+	assert.Equal(t, &ContractABI{
+		Type:    "function",
+		Name:    "balanceOf",
+		Inputs:  []ABIParameter{{Type: "address"}},
+		Outputs: []ABIParameter{{Type: "uint256"}},
+	}, balanceOf)
+	methodID, err := balanceOf.MethodID()
+	require.NoError(t, err)
+	assert.Equal(t, "70a08231", methodID)
+
+	// No inputs and several outputs
+	getReserves, err := ParseSignature("getReserves() returns (uint112,uint112,uint32)")
+	require.NoError(t, err)
+	assert.Equal(t, "getReserves", getReserves.Name)
+	assert.Empty(t, getReserves.Inputs)
+	assert.Equal(t, []ABIParameter{{Type: "uint112"}, {Type: "uint112"}, {Type: "uint32"}}, getReserves.Outputs)
+	methodID, err = getReserves.MethodID()
+	require.NoError(t, err)
+	assert.Equal(t, "0902f1ac", methodID)
+
+	// Named parameters, modifiers and data locations as written in Solidity
+	allowance, err := ParseSignature("function allowance(address owner, address spender) external view returns (uint256 remaining)")
+	require.NoError(t, err)
+	assert.Equal(t, []ABIParameter{{Name: "owner", Type: "address"}, {Name: "spender", Type: "address"}}, allowance.Inputs)
+	assert.Equal(t, []ABIParameter{{Name: "remaining", Type: "uint256"}}, allowance.Outputs)
+	assert.Equal(t, "view", allowance.StateMutability)
+	assert.True(t, allowance.Constant)
+
+	name, err := ParseSignature("name()returns(string memory)")
+	require.NoError(t, err)
+	assert.Equal(t, []ABIParameter{{Type: "string"}}, name.Outputs)
+
+	// Without returns there are no outputs
+	transfer, err := ParseSignature("transfer(address payable to, uint256 amount)")
+	require.NoError(t, err)
+	assert.Equal(t, []ABIParameter{{Name: "to", Type: "address"}, {Name: "amount", Type: "uint256"}}, transfer.Inputs)
+	assert.Empty(t, transfer.Outputs)
+
+	// Tuples and arrays
+	aggregate, err := ParseSignature("aggregate3((address target, bool allowFailure, bytes callData)[] calls) payable returns (tuple(bool,bytes)[])")
+	require.NoError(t, err)
+	assert.Equal(t, []ABIParameter{{
+		Name: "calls",
+		Type: "tuple[]",
+		Components: []ABIParameter{
+			{Name: "target", Type: "address"},
+			{Name: "allowFailure", Type: "bool"},
+			{Name: "callData", Type: "bytes"},
+		},
+	}}, aggregate.Inputs)
+	assert.Equal(t, []ABIParameter{{Type: "tuple[]", Components: []ABIParameter{{Type: "bool"}, {Type: "bytes"}}}}, aggregate.Outputs)
+	assert.Equal(t, "payable", aggregate.StateMutability)
+	methodID, err = aggregate.MethodID()
+	require.NoError(t, err)
+	assert.Equal(t, "82ad56cb", methodID)
+
+	for _, sig := range []string{
+		"",
+		"balanceOf",
+		"balanceOf(address",
+		"balanceOf(address,)",
+		"balance Of(address)",
+		"balanceOf(Address)",
+		"balanceOf(address) returns uint256",
+		"balanceOf(address) returns (uint256) view",
+		"balanceOf(address) internal returns (uint256)",
+		"balanceOf(address a b)",
+	} {
+		_, err := ParseSignature(sig)
+		assert.Error(t, err, sig)
+	}
+}
+
+func TestAbi_Cache(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	mockRespBody, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+	failing := false
+	httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+		if failing {
+			return httpmock.NewStringResponse(http.StatusOK, `{"status":"0","message":"NOTOK","result":"Max rate limit reached"}`), nil
+		}
+		return httpmock.NewBytesResponse(http.StatusOK, mockRespBody), nil
+	})
+
+	ctx := context.Background()
+	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout(), WithCache(NewLRUCache(16)))
+	abis, err := abiClient.GetContractABI(ctx, "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270")
+	require.NoError(t, err)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+
+	// Addresses are cached regardless of their case
+	cached, err := abiClient.GetContractABI(ctx, "0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270")
+	require.NoError(t, err)
+	assert.Equal(t, abis, cached)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+
+	// Failures aren't cached
+	failing = true
+	_, err = abiClient.GetContractABI(ctx, "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174")
+	require.Error(t, err)
+	failing = false
+	_, err = abiClient.GetContractABI(ctx, "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174")
+	require.NoError(t, err)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+
+	// Concurrent lookups of an uncached address share a single request
+	httpmock.ZeroCallCounters()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			abis, err := abiClient.GetContractABI(ctx, "0x7ceB23fD6bC0adD59E62ac25578270cFf1b9f619")
+			assert.NoError(t, err)
+			assert.NotEmpty(t, abis)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+
+	// Without a cache every call queries the explorer
+	httpmock.ZeroCallCounters()
+	uncached := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout())
+	for i := 0; i < 2; i++ {
+		_, err = uncached.GetContractABI(ctx, "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestAbi_LRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	a := ContractABIs{{Type: "function", Name: "a"}}
+	b := ContractABIs{{Type: "function", Name: "b"}}
+	c := ContractABIs{{Type: "function", Name: "c"}}
+
+	cache.Set("0xa", a)
+	cache.Set("0xb", b)
+	got, ok := cache.Get("0xa")
+	require.True(t, ok)
+	assert.Equal(t, a, got)
+
+	// 0xb is the least recently used
+	cache.Set("0xc", c)
+	_, ok = cache.Get("0xb")
+	assert.False(t, ok)
+	got, ok = cache.Get("0xa")
+	require.True(t, ok)
+	assert.Equal(t, a, got)
+	got, ok = cache.Get("0xc")
+	require.True(t, ok)
+	assert.Equal(t, c, got)
+
+	// Setting a cached address replaces its ABI without evicting
+	cache.Set("0xa", b)
+	got, ok = cache.Get("0xa")
+	require.True(t, ok)
+	assert.Equal(t, b, got)
+	_, ok = cache.Get("0xc")
+	assert.True(t, ok)
+}
+
+func TestAbi_RateLimit(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	mockRespBody, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+	var mu sync.Mutex
+	var sent []time.Time
+	httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		sent = append(sent, time.Now())
+		mu.Unlock()
+		return httpmock.NewBytesResponse(http.StatusOK, mockRespBody), nil
+	})
+
+	// 20 requests per second one at a time: 5 concurrent calls span at least 4 intervals of 50ms
+	ctx := context.Background()
+	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout(), WithRateLimit(20, 1))
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Distinct addresses, as concurrent lookups of the same one share a request
+			_, err := abiClient.GetContractABI(ctx, fmt.Sprintf("0x%040x", i))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	require.Len(t, sent, 5)
+	sort.Slice(sent, func(i, j int) bool { return sent[i].Before(sent[j]) })
+	assert.GreaterOrEqual(t, sent[4].Sub(sent[0]), 180*time.Millisecond)
+	for i := 1; i < len(sent); i++ {
+		assert.GreaterOrEqual(t, sent[i].Sub(sent[i-1]), 40*time.Millisecond)
+	}
+
+	// Waiting for a turn ends with the context
+	slow := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout(), WithRateLimit(0.1, 1))
+	_, err = slow.GetContractABI(ctx, "CONTRACT_ADDRESS")
+	require.NoError(t, err)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = slow.GetSourceCode(timeoutCtx, "CONTRACT_ADDRESS")
+	assert.ErrorContains(t, err, "rate limit")
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Len(t, sent, 6)
+}
+
+func TestAbi_ChainID(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	mockRespBody, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+	var requested *url.URL
+	httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+		requested = req.URL
+		return httpmock.NewBytesResponse(http.StatusOK, mockRespBody), nil
+	})
+
+	const addr = "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270"
+	ctx := context.Background()
+
+	// The V2 API serves every chain from a single host
+	_, err = NewABIClient(EtherscanBaseURL, "DUMMY_API_KEY", WithChainID(137)).GetContractABI(ctx, addr)
+	require.NoError(t, err)
+	assert.Equal(t, "api.etherscan.io", requested.Host)
+	assert.Equal(t, "/v2/api", requested.Path)
+	assert.Equal(t, url.Values{
+		"chainid": {"137"},
+		"module":  {"contract"},
+		"action":  {"getabi"},
+		"address": {addr},
+		"apikey":  {"DUMMY_API_KEY"},
+	}, requested.Query())
+
+	// WithLegacyLayout keeps the per-chain layout, without a chain ID
+	_, err = NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout()).GetContractABI(ctx, addr)
+	require.NoError(t, err)
+	assert.Equal(t, "api.polygonscan.com", requested.Host)
+	assert.Equal(t, "/api", requested.Path)
+	assert.Equal(t, url.Values{
+		"module":  {"contract"},
+		"action":  {"getabi"},
+		"address": {addr},
+		"apikey":  {"DUMMY_API_KEY"},
+	}, requested.Query())
+
+	// Every action uses the configured layout
+	abiClient := NewABIClient(EtherscanBaseURL, "DUMMY_API_KEY", WithChainID(1), WithAllowedHosts("api.etherscan.io"))
+	_, _ = abiClient.GetSourceCode(ctx, addr)
+	assert.Equal(t, "/v2/api", requested.Path)
+	assert.Equal(t, "1", requested.Query().Get("chainid"))
+	assert.Equal(t, "getsourcecode", requested.Query().Get("action"))
+
+	// The base URL may already hold the /v2 path, or end with a slash
+	for _, base := range []string{"https://api.etherscan.io/v2", "https://api.etherscan.io/v2/", "https://api.etherscan.io/"} {
+		_, err = NewABIClient(base, "DUMMY_API_KEY", WithChainID(137)).GetContractABI(ctx, addr)
+		require.NoError(t, err, base)
+		assert.Equal(t, "/v2/api", requested.Path, base)
+	}
+
+	// Values are escaped rather than spliced into the query
+	_, err = NewABIClient(EtherscanBaseURL, "KEY&chainid=1", WithChainID(137)).GetContractABI(ctx, addr+"&action=getsourcecode")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"137"}, requested.Query()["chainid"])
+	assert.Equal(t, []string{"getabi"}, requested.Query()["action"])
+	assert.Equal(t, "KEY&chainid=1", requested.Query().Get("apikey"))
+	assert.Equal(t, addr+"&action=getsourcecode", requested.Query().Get("address"))
+
+	// The V2 API needs a chain ID, which is checked before sending anything
+	httpmock.ZeroCallCounters()
+	_, err = NewABIClient(EtherscanBaseURL, "DUMMY_API_KEY").GetContractABI(ctx, addr)
+	assert.ErrorContains(t, err, "no chain ID")
+	assert.Zero(t, httpmock.GetTotalCallCount())
+}
+
+func TestAbi_APIErrors(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	ctx := context.Background()
+	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout())
+	for _, tc := range []struct {
+		fixture string
+		err     error
+		message string
+	}{
+		{"fixtures/resp_not_verified.json", ErrContractNotVerified, "contract source code not verified: Contract source code not verified"},
+		{"fixtures/resp_rate_limited.json", ErrRateLimited, "rate limit reached: Max rate limit reached"},
+	} {
+		mockRespBody, err := os.ReadFile(tc.fixture)
+		require.NoError(t, err)
+		httpmock.RegisterNoResponder(httpmock.NewBytesResponder(http.StatusOK, mockRespBody))
+
+		_, err = abiClient.GetContractABI(ctx, "CONTRACT_ADDRESS")
+		require.ErrorIs(t, err, tc.err, tc.fixture)
+		assert.EqualError(t, err, tc.message)
+	}
+
+	// Other failures are reported with the message
+	httpmock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusOK, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`))
+	_, err := abiClient.GetContractABI(ctx, "CONTRACT_ADDRESS")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrContractNotVerified)
+	assert.NotErrorIs(t, err, ErrRateLimited)
+	assert.EqualError(t, err, "API error: NOTOK")
+}
+
+func TestAbi_ParseFormats(t *testing.T) {
+	var parsed []ContractABIs
+	for _, fixture := range []string{
+		"fixtures/wmatic_abi.json",
+		"fixtures/resp_get_contract_abi.json",
+		"fixtures/wmatic_artifact.json",
+	} {
+		d, err := os.ReadFile(fixture)
+		require.NoError(t, err)
+
+		contractABIs, err := ParseABI(d)
+		require.NoError(t, err, fixture)
+		transfer, err := contractABIs.Find("transfer")
+		require.NoError(t, err, fixture)
+		methodID, err := transfer.MethodID()
+		require.NoError(t, err)
+		assert.Equal(t, "a9059cbb", methodID)
+		parsed = append(parsed, contractABIs)
+	}
+
+	// Every format yields the same ABI
+	assert.Equal(t, parsed[0], parsed[1])
+	assert.Equal(t, parsed[0], parsed[2])
+
+	// Failed explorer responses report their error
+	d, err := os.ReadFile("fixtures/resp_not_verified.json")
+	require.NoError(t, err)
+	_, err = ParseABI(d)
+	assert.ErrorIs(t, err, ErrContractNotVerified)
+
+	// Strict parsing applies to the wrapped ABI
+	_, err = ParseABI([]byte(`{"abi":[{"type":"function","name":"f","inputs":[],"outputs":[],"gas":1}]}`), StrictParsing())
+	assert.Error(t, err)
+
+	for _, invalid := range []string{`{}`, `{"abi":`, `{"status":"1","message":"OK","result":"not json"}`, `"abi"`} {
+		_, err = ParseABI([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestAbi_FindOverloads(t *testing.T) {
+	contractABIs, err := ParseABI([]byte(`[
+		{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+		{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]},
+		{"type":"function","name":"ownerOf","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"address"}]}
+	]`))
+	require.NoError(t, err)
+
+	// An overloaded name can't be resolved by name alone
+	_, err = contractABIs.Find("safeTransferFrom")
+	assert.ErrorIs(t, err, ErrAmbiguousName)
+
+	ownerOf, err := contractABIs.Find("ownerOf")
+	require.NoError(t, err)
+	assert.Equal(t, "ownerOf", ownerOf.Name)
+
+	tests := []struct {
+		signature string
+		methodID  string
+		inputs    int
+	}{
+		{"safeTransferFrom(address,address,uint256)", "42842e0e", 3},
+		{"safeTransferFrom(address,address,uint256,bytes)", "b88d4fde", 4},
+		{"safeTransferFrom(address from, address to, uint tokenId)", "42842e0e", 3},
+	}
+
+	for _, test := range tests {
+		entry, err := contractABIs.FindBySignature(test.signature)
+		require.NoError(t, err, test.signature)
+		assert.Len(t, entry.Inputs, test.inputs, test.signature)
+		methodID, err := entry.MethodID()
+		require.NoError(t, err)
+		assert.Equal(t, test.methodID, methodID, test.signature)
+	}
+
+	_, err = contractABIs.FindBySignature("safeTransferFrom(address,uint256)")
+	assert.ErrorContains(t, err, "not found")
+	_, err = contractABIs.FindBySignature("safeTransferFrom(")
+	assert.Error(t, err)
+}
+
+// joinNotifier reports when a lookup starts waiting on its context
+type joinNotifier struct {
+	context.Context
+	once   sync.Once
+	joined chan struct{}
+}
+
+func (c *joinNotifier) Done() <-chan struct{} {
+	c.once.Do(func() { close(c.joined) })
+	return c.Context.Done()
+}
+
+func TestAbi_CacheCancelledLeader(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	mockRespBody, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+	started, release := make(chan struct{}), make(chan struct{})
+	httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-release
+		return httpmock.NewBytesResponse(http.StatusOK, mockRespBody), nil
+	})
+
+	const address = "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270"
+	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout())
+
+	// The first lookup starts the request, then gives up
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		_, err := abiClient.GetContractABI(ctx, address)
+		leaderErr <- err
+	}()
+	<-started
+
+	// A second lookup joins the request in flight
+	waiter := &joinNotifier{Context: context.Background(), joined: make(chan struct{})}
+	type result struct {
+		abis ContractABIs
+		err  error
+	}
+	waiterResult := make(chan result)
+	go func() {
+		abis, err := abiClient.GetContractABI(waiter, address)
+		waiterResult <- result{abis, err}
+	}()
+	<-waiter.joined
+
+	cancel()
+	assert.ErrorIs(t, <-leaderErr, context.Canceled)
+
+	// The request goes on for the lookup still waiting
+	close(release)
+	res := <-waiterResult
+	require.NoError(t, res.err)
+	assert.NotEmpty(t, res.abis)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+// refilledCache is a Cache which misses the first lookup, as if another lookup filled it right after
+type refilledCache struct {
+	mu     sync.Mutex
+	abis   ContractABIs
+	missed bool
+}
+
+func (c *refilledCache) Get(address string) (ContractABIs, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.missed {
+		c.missed = true
+		return nil, false
+	}
+	return c.abis, true
+}
+
+func (c *refilledCache) Set(address string, abis ContractABIs) {}
+
+func TestAbi_CacheRefilled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusOK, `{"status":"1","message":"OK","result":"[]"}`))
+
+	// A flight filling the cache between the first check and the flight lock isn't fetched again
+	cached := ContractABIs{{Type: "function", Name: "decimals"}}
+	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout(), WithCache(&refilledCache{abis: cached}))
+	abis, err := abiClient.GetContractABI(context.Background(), "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270")
+	require.NoError(t, err)
+	assert.Equal(t, cached, abis)
+	assert.Zero(t, httpmock.GetTotalCallCount())
+}
+
+func TestAbi_SharedFetchTimeout(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	mockRespBody, err := os.ReadFile("fixtures/resp_get_contract_abi.json")
+	require.NoError(t, err)
+	// The explorer answers after 100ms, unless the request gives up first
+	httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(100 * time.Millisecond):
+			return httpmock.NewBytesResponse(http.StatusOK, mockRespBody), nil
+		}
+	})
+
+	const address = "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270"
+	abiClient := NewABIClient("https://api.polygonscan.com", "DUMMY_API_KEY", WithLegacyLayout(), WithSharedFetchTimeout(20*time.Millisecond))
+
+	// Without a deadline, the shared fetch is bounded by its own timeout
+	_, err = abiClient.GetContractABI(context.Background(), address)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// A later deadline of the lookup starting it extends it
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	abis, err := abiClient.GetContractABI(ctx, address)
+	require.NoError(t, err)
+	assert.NotEmpty(t, abis)
+}
@@ -0,0 +1,68 @@
+package abi
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache stores the ABIs fetched by GetContractABI, keyed by lowercase contract address. See WithCache.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the ABI cached for address, if any
+	Get(address string) (ContractABIs, bool)
+	// Set caches the ABI of address
+	Set(address string, abis ContractABIs)
+}
+
+// lruCache is a Cache holding a bounded number of ABIs, evicting the least recently used
+type lruCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of *lruEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	address string
+	abis    ContractABIs
+}
+
+// NewLRUCache creates an in-memory Cache holding the ABIs of up to size contracts,
+// evicting the least recently used one when full. A size below 1 holds a single ABI.
+func NewLRUCache(size int) Cache {
+	return &lruCache{
+		size:    max(size, 1),
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *lruCache) Get(address string) (ContractABIs, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[address]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).abis, true
+}
+
+func (c *lruCache) Set(address string, abis ContractABIs) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[address]; ok {
+		elem.Value.(*lruEntry).abis = abis
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[address] = c.order.PushFront(&lruEntry{address: address, abis: abis})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).address)
+	}
+}
@@ -0,0 +1,133 @@
+package abi
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Option configures an ABI client
+type Option func(*etherscanABI)
+
+// WithAllowedHosts restricts the hosts the API key may be sent to.
+// Requests to any other host fail with ErrHostNotAllowed before anything is sent.
+// Without this option every host is allowed.
+func WithAllowedHosts(hosts ...string) Option {
+	return func(e *etherscanABI) {
+		e.allowedHosts = append(e.allowedHosts, hosts...)
+	}
+}
+
+// WithStrictABI makes GetContractABI fail on ABI JSON fields that ContractABI doesn't model,
+// instead of silently ignoring them
+func WithStrictABI() Option {
+	return func(e *etherscanABI) {
+		e.strict = true
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to reach the explorer API instead of http.DefaultClient,
+// e.g. to configure connection pooling or a proxy
+func WithHTTPClient(client *http.Client) Option {
+	return func(e *etherscanABI) {
+		e.httpClient = client
+	}
+}
+
+// WithTimeout bounds each request to the explorer API, including reading the response, to d.
+// It applies on top of WithHTTPClient, keeping the other settings of that client. By default requests are only
+// bounded by the context passed to the client methods.
+func WithTimeout(d time.Duration) Option {
+	return func(e *etherscanABI) {
+		e.timeout = d
+	}
+}
+
+// WithRetry makes up to maxAttempts requests to the explorer API while they fail with a transient error:
+// a network error, or an HTTP status 429, 502, 503 or 504. Retries wait baseDelay, doubled after every attempt,
+// with jitter, and stop once the context passed to the client methods is done.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(e *etherscanABI) {
+		e.retryAttempts = maxAttempts
+		e.retryDelay = baseDelay
+	}
+}
+
+// Clock waits for durations to elapse. A contract.Clock is one.
+type Clock interface {
+	// After returns a channel receiving the current time once d has elapsed
+	After(d time.Duration) <-chan time.Time
+}
+
+// WithClock sets the clock timing the waits between retries instead of the system clock,
+// so tests can control time instead of sleeping
+func WithClock(clock Clock) Option {
+	return func(e *etherscanABI) {
+		if clock != nil {
+			e.clock = clock
+		}
+	}
+}
+
+// WithRateLimit spaces out requests to the explorer API to rps per second on average, allowing bursts of up to burst requests,
+// e.g. WithRateLimit(5, 1) for the free tier of Etherscan. Every request counts, including retries and those of
+// concurrent calls, which wait for their turn until the context passed to the client methods is done.
+// By default requests are not limited.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(e *etherscanABI) {
+		e.limiter = rate.NewLimiter(rate.Limit(rps), max(burst, 1))
+	}
+}
+
+// WithChainID sets the chain the Etherscan V2 API is queried for, e.g. 137 for Polygon.
+// Requests go to apiBaseURL/v2/api with the chainid parameter, apiBaseURL being the root of the API
+// with or without its /v2 path, e.g. EtherscanBaseURL or "https://api.etherscan.io/v2".
+// Without WithLegacyLayout a chain ID is required, and requests fail before being sent without one.
+func WithChainID(chainID uint64) Option {
+	return func(e *etherscanABI) {
+		e.chainID = chainID
+	}
+}
+
+// WithLegacyLayout uses the per-chain layout of explorers which predate the Etherscan V2 API, like api.polygonscan.com:
+// requests go to apiBaseURL/api without a chain ID, and WithChainID is ignored
+func WithLegacyLayout() Option {
+	return func(e *etherscanABI) {
+		e.legacyLayout = true
+	}
+}
+
+// WithCache makes GetContractABI look up ABIs in cache before querying the explorer API, and store the ABIs it fetches there,
+// e.g. an in-memory cache from NewLRUCache. Failed lookups are not cached. Cached ABIs are shared between callers,
+// which must not modify them.
+func WithCache(cache Cache) Option {
+	return func(e *etherscanABI) {
+		e.cache = cache
+	}
+}
+
+// WithSharedFetchTimeout bounds the request GetContractABI shares between concurrent lookups of an address to d,
+// or to the deadline of the lookup starting it when that is later. The request outlives the contexts of its callers,
+// so it has a bound of its own, one minute by default.
+func WithSharedFetchTimeout(d time.Duration) Option {
+	return func(e *etherscanABI) {
+		if d > 0 {
+			e.flightTimeout = d
+		}
+	}
+}
+
+// ParseOption configures ParseABI
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	strict bool
+}
+
+// StrictParsing rejects ABI JSON fields that ContractABI doesn't model, instead of silently ignoring them
+func StrictParsing() ParseOption {
+	return func(o *parseOptions) {
+		o.strict = true
+	}
+}
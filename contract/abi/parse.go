@@ -0,0 +1,236 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// abiWrapper is a JSON object holding an ABI: either an explorer response, whose result is the ABI as a JSON string,
+// or a compiler artifact, like those of Hardhat and Foundry, whose abi field is the ABI
+type abiWrapper struct {
+	apiEnvelope
+	ABI json.RawMessage `json:"abi"`
+}
+
+// ParseABI parses a JSON ABI array, as emitted by solc. It also accepts the array wrapped in an explorer
+// response like those of GetContractABI, or in a compiler artifact with an abi field, and detects which it is given.
+// By default fields ContractABI doesn't model are ignored, see StrictParsing.
+func ParseABI(data []byte, opts ...ParseOption) (ContractABIs, error) {
+	o := &parseOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		unwrapped, err := unwrapABI(data)
+		if err != nil {
+			return nil, err
+		}
+		data = unwrapped
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if o.strict {
+		dec.DisallowUnknownFields()
+	}
+
+	var contractABIs ContractABIs
+	if err := dec.Decode(&contractABIs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal contract ABIs: %w", err)
+	}
+	return contractABIs, nil
+}
+
+// unwrapABI returns the ABI array held by a JSON object
+func unwrapABI(data []byte) ([]byte, error) {
+	var wrapper abiWrapper
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal contract ABIs: %w", err)
+	}
+
+	switch {
+	case len(wrapper.ABI) > 0:
+		return wrapper.ABI, nil
+	case len(wrapper.Result) > 0:
+		if wrapper.Status != "1" {
+			return nil, wrapper.err()
+		}
+		var abiJSON string
+		if err := json.Unmarshal(wrapper.Result, &abiJSON); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal API response: %w", err)
+		}
+		return []byte(abiJSON), nil
+	}
+	return nil, fmt.Errorf("failed to unmarshal contract ABIs: object has neither an abi nor a result field")
+}
+
+// ParseSignature parses a human-readable function signature into a function entry,
+// e.g. "balanceOf(address owner) returns (uint256)" or "getReserves() returns (uint112,uint112,uint32)".
+// Parameters may be named or not, and tuples are written as their parenthesized components, e.g. "(address,uint256)[]".
+// A leading "function", visibility and data locations are accepted, and a view, pure or payable modifier
+// sets the state mutability.
+func ParseSignature(sig string) (*ContractABI, error) {
+	s := strings.TrimSpace(sig)
+	if rest, ok := strings.CutPrefix(s, "function "); ok {
+		s = strings.TrimSpace(rest)
+	}
+
+	open := strings.IndexByte(s, '(')
+	if open < 0 {
+		return nil, fmt.Errorf("invalid signature %q: missing parameter list", sig)
+	}
+	name := strings.TrimSpace(s[:open])
+	if !identifierPattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid signature %q: invalid function name %q", sig, name)
+	}
+	end, err := closingParen(s, open)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %q: %w", sig, err)
+	}
+	inputs, err := parseParameters(s[open+1 : end])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %q: %w", sig, err)
+	}
+
+	entry := &ContractABI{Type: "function", Name: name, Inputs: inputs, Outputs: []ABIParameter{}}
+	modifiers, returns, hasReturns := strings.Cut(s[end+1:], "returns")
+	for _, modifier := range strings.Fields(modifiers) {
+		switch modifier {
+		case "view", "pure", "payable", "nonpayable":
+			entry.StateMutability = modifier
+		case "external", "public":
+		default:
+			return nil, fmt.Errorf("invalid signature %q: unexpected %q", sig, modifier)
+		}
+	}
+	entry.Constant = entry.StateMutability == "view" || entry.StateMutability == "pure"
+	entry.Payable = entry.StateMutability == "payable"
+
+	if hasReturns {
+		returns = strings.TrimSpace(returns)
+		if !strings.HasPrefix(returns, "(") {
+			return nil, fmt.Errorf("invalid signature %q: missing return parameter list", sig)
+		}
+		end, err := closingParen(returns, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature %q: %w", sig, err)
+		}
+		if trailing := strings.TrimSpace(returns[end+1:]); trailing != "" {
+			return nil, fmt.Errorf("invalid signature %q: unexpected %q", sig, trailing)
+		}
+		if entry.Outputs, err = parseParameters(returns[1:end]); err != nil {
+			return nil, fmt.Errorf("invalid signature %q: %w", sig, err)
+		}
+	}
+	return entry, nil
+}
+
+var (
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	typePattern       = regexp.MustCompile(`^[a-z][a-z0-9]*(\[[0-9]*\])*$`)
+	arraySuffix       = regexp.MustCompile(`^(\[[0-9]*\])*`)
+)
+
+// closingParen returns the index of the parenthesis closing the one opened at s[open]
+func closingParen(s string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unbalanced parentheses")
+}
+
+// parseParameters parses a comma-separated parameter list, without its enclosing parentheses
+func parseParameters(list string) ([]ABIParameter, error) {
+	params := []ABIParameter{}
+	if strings.TrimSpace(list) == "" {
+		return params, nil
+	}
+
+	depth, start := 0, 0
+	for i := 0; i <= len(list); i++ {
+		if i < len(list) {
+			switch list[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if list[i] != ',' || depth != 0 {
+				continue
+			}
+		}
+		param, err := parseParameter(list[start:i])
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+		start = i + 1
+	}
+	return params, nil
+}
+
+// parseParameter parses a parameter of a signature: a type, optionally followed by a data location and a name
+func parseParameter(s string) (ABIParameter, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ABIParameter{}, fmt.Errorf("empty parameter")
+	}
+
+	var param ABIParameter
+	var rest string
+	if body, ok := strings.CutPrefix(s, "tuple"); ok && strings.HasPrefix(body, "(") {
+		s = body
+	}
+	if strings.HasPrefix(s, "(") {
+		end, err := closingParen(s, 0)
+		if err != nil {
+			return ABIParameter{}, err
+		}
+		if param.Components, err = parseParameters(s[1:end]); err != nil {
+			return ABIParameter{}, err
+		}
+		suffix := arraySuffix.FindString(s[end+1:])
+		param.Type = "tuple" + suffix
+		rest = s[end+1+len(suffix):]
+	} else {
+		param.Type, rest, _ = strings.Cut(s, " ")
+		if !typePattern.MatchString(param.Type) {
+			return ABIParameter{}, fmt.Errorf("invalid type %q", param.Type)
+		}
+	}
+
+	fields := strings.Fields(rest)
+	if param.Type == "address" && len(fields) > 0 && fields[0] == "payable" {
+		fields = fields[1:]
+	}
+	if len(fields) > 0 {
+		switch fields[0] {
+		case "memory", "calldata", "storage":
+			fields = fields[1:]
+		}
+	}
+	switch len(fields) {
+	case 0:
+	case 1:
+		if !identifierPattern.MatchString(fields[0]) {
+			return ABIParameter{}, fmt.Errorf("invalid parameter name %q", fields[0])
+		}
+		param.Name = fields[0]
+	default:
+		return ABIParameter{}, fmt.Errorf("invalid parameter %q", s)
+	}
+	return param, nil
+}
@@ -0,0 +1,72 @@
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SourceCode represents a single entry of the explorer's getsourcecode result
+type SourceCode struct {
+	SourceCode           string `json:"SourceCode"`
+	ABI                  string `json:"ABI"`
+	ContractName         string `json:"ContractName"`
+	CompilerVersion      string `json:"CompilerVersion"`
+	OptimizationUsed     string `json:"OptimizationUsed"`
+	Runs                 string `json:"Runs"`
+	ConstructorArguments string `json:"ConstructorArguments"`
+	EVMVersion           string `json:"EVMVersion"`
+	Library              string `json:"Library"`
+	LicenseType          string `json:"LicenseType"`
+	Proxy                string `json:"Proxy"`
+	Implementation       string `json:"Implementation"`
+	SwarmSource          string `json:"SwarmSource"`
+}
+
+// StorageLayout is the solc storageLayout output of a contract.
+// Explorers return the verified source and compiler settings rather than the layout itself,
+// so it is produced by compiling that source with the storageLayout output selection
+// or taken from the project's build artifacts.
+type StorageLayout struct {
+	Storage []StorageEntry         `json:"storage"`
+	Types   map[string]StorageType `json:"types"`
+}
+
+// StorageEntry describes where a state variable lives
+type StorageEntry struct {
+	Label    string `json:"label"`
+	Contract string `json:"contract"`
+	Slot     string `json:"slot"`   // Decimal slot number
+	Offset   int    `json:"offset"` // Byte offset from the right of the slot
+	Type     string `json:"type"`   // Key into StorageLayout.Types
+}
+
+// StorageType describes how a storage type is encoded
+type StorageType struct {
+	Encoding      string `json:"encoding"` // inplace, mapping, dynamic_array or bytes
+	Label         string `json:"label"`
+	NumberOfBytes string `json:"numberOfBytes"`
+}
+
+// ParseStorageLayout parses a solc storageLayout JSON document
+func ParseStorageLayout(data []byte) (*StorageLayout, error) {
+	var layout StorageLayout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal storage layout: %w", err)
+	}
+	return &layout, nil
+}
+
+// Find returns the storage entry and its type for the variable with the given label
+func (l *StorageLayout) Find(label string) (StorageEntry, StorageType, error) {
+	for _, entry := range l.Storage {
+		if entry.Label != label {
+			continue
+		}
+		typ, ok := l.Types[entry.Type]
+		if !ok {
+			return StorageEntry{}, StorageType{}, fmt.Errorf("unknown storage type %q for %s", entry.Type, label)
+		}
+		return entry, typ, nil
+	}
+	return StorageEntry{}, StorageType{}, fmt.Errorf("storage variable %s not found", label)
+}
@@ -1,8 +1,12 @@
 package abi
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/crypto"
@@ -16,6 +20,10 @@ In the context of Ethereum’s Application Binary Interface (ABI), the stateMuta
 	•	payable: Indicates that the function can modify the blockchain state and is capable of receiving Ether. This is essential for functions intended to handle Ether transfers.
 */
 
+// Keccak256 is the hash function used for method IDs, event topics and address checksums.
+// It defaults to go-ethereum's implementation and may be replaced by any Keccak-256 implementation.
+var Keccak256 func(data ...[]byte) []byte = crypto.Keccak256
+
 // APIResponse represents the top-level response from the API
 type APIResponse struct {
 	Status  string `json:"status"`
@@ -44,31 +52,189 @@ func (c *ContractABI) MethodID() (string, error) {
 		return "", fmt.Errorf("cannot get method ID for non-function type: %s", c.Type)
 	}
 
+	hash := Keccak256([]byte(c.signature()))
+	return hex.EncodeToString(hash[:4]), nil
+}
+
+// ErrorSelector returns the first 4 bytes of the Keccak256 hash of the error signature as a hex string,
+// which prefixes the revert data of the custom error.
+// Returns an error if the ABI entry is not an error.
+func (c *ContractABI) ErrorSelector() (string, error) {
+	if c.Type != "error" {
+		return "", fmt.Errorf("cannot get error selector for non-error type: %s", c.Type)
+	}
+
+	hash := Keccak256([]byte(c.signature()))
+	return hex.EncodeToString(hash[:4]), nil
+}
+
+// EventTopic returns the full Keccak256 hash of the event signature as a 0x-prefixed hex string,
+// which is the topic0 of logs emitted for the event.
+// Returns an error if the ABI entry is not an event.
+func (c *ContractABI) EventTopic() (string, error) {
+	if c.Type != "event" {
+		return "", fmt.Errorf("cannot get event topic for non-event type: %s", c.Type)
+	}
+
+	hash := Keccak256([]byte(c.signature()))
+	return "0x" + hex.EncodeToString(hash), nil
+}
+
+// signature returns the canonical signature name(type1,type2,...)
+func (c *ContractABI) signature() string {
 	var inputTypes []string
 	for _, input := range c.Inputs {
-		inputTypes = append(inputTypes, input.Type)
+		inputTypes = append(inputTypes, canonicalParamType(input))
 	}
-	signature := fmt.Sprintf("%s(%s)", c.Name, strings.Join(inputTypes, ","))
+	return fmt.Sprintf("%s(%s)", c.Name, strings.Join(inputTypes, ","))
+}
 
-	hash := crypto.Keccak256([]byte(signature))
-	return hex.EncodeToString(hash[:4]), nil
+// CanonicalType returns the form of typ used in signatures and encoding.
+// Older compilers annotate payable addresses as "address payable", which encodes as a plain address,
+// and hand-written ABIs may use aliases like "uint", which must be expanded to "uint256" in signatures.
+func CanonicalType(typ string) string {
+	typ = strings.ReplaceAll(strings.TrimSpace(typ), "address payable", "address")
+
+	// Aliases apply to the base type, whatever the array dimensions
+	base, dims := typ, ""
+	if i := strings.IndexByte(typ, '['); i >= 0 {
+		base, dims = typ[:i], typ[i:]
+	}
+	if canonical, ok := typeAliases[base]; ok {
+		return canonical + dims
+	}
+	return typ
+}
+
+// typeAliases maps the Solidity type aliases to the types they stand for
+var typeAliases = map[string]string{
+	"uint":   "uint256",
+	"int":    "int256",
+	"fixed":  "fixed128x18",
+	"ufixed": "ufixed128x18",
+	"byte":   "bytes1",
+}
+
+// canonicalParamType returns the canonical type of param, with tuples expanded to their components,
+// e.g. "(address,uint256)[]" for a tuple[] of an address and an amount
+func canonicalParamType(param ABIParameter) string {
+	typ := CanonicalType(param.Type)
+	if !strings.HasPrefix(typ, "tuple") {
+		return typ
+	}
+
+	components := make([]string, len(param.Components))
+	for i, component := range param.Components {
+		components[i] = canonicalParamType(component)
+	}
+	return "(" + strings.Join(components, ",") + ")" + strings.TrimPrefix(typ, "tuple")
 }
 
 // ABIParameter represents an input or output parameter in the ABI
 type ABIParameter struct {
-	Name    string `json:"name"`
-	Type    string `json:"type"`
-	Indexed bool   `json:"indexed,omitempty"` // Only used for event parameters
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	InternalType string `json:"internalType,omitempty"` // Solidity type, e.g. "contract IERC20", emitted by newer compilers
+	Indexed      bool   `json:"indexed,omitempty"`      // Only used for event parameters
+
+	// Components are the fields of tuple types, including arrays of tuples
+	Components []ABIParameter `json:"components,omitempty"`
 }
 
 type ContractABIs []ContractABI
 
-// Find returns the first ContractABI with the given name, or nil if not found
+// ErrAmbiguousName is returned by Find when several entries share the name, like overloaded functions
+var ErrAmbiguousName = errors.New("ambiguous ABI entry name")
+
+// Find returns the ContractABI with the given name, or an error if not found.
+// When entries of different signatures share the name, e.g. overloads of safeTransferFrom,
+// it fails with ErrAmbiguousName; use FindBySignature to pick one.
 func (l ContractABIs) Find(name string) (*ContractABI, error) {
+	var found *ContractABI
 	for i := range l {
-		if l[i].Name == name {
+		if l[i].Name != name {
+			continue
+		}
+		if found == nil {
+			found = &l[i]
+			continue
+		}
+		if found.Type != l[i].Type || found.signature() != l[i].signature() {
+			return nil, fmt.Errorf("%w: %q matches %s %s and %s %s",
+				ErrAmbiguousName, name, found.Type, found.signature(), l[i].Type, l[i].signature())
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("contract ABI with name %q not found", name)
+	}
+	return found, nil
+}
+
+// FindBySignature returns the first ContractABI with the given signature, e.g. "safeTransferFrom(address,address,uint256)".
+// The signature may also be written like ParseSignature accepts it, with parameter names, spaces and type aliases.
+func (l ContractABIs) FindBySignature(sig string) (*ContractABI, error) {
+	parsed, err := ParseSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	canonical := parsed.signature()
+
+	for i := range l {
+		if l[i].signature() == canonical {
 			return &l[i], nil
 		}
 	}
-	return nil, fmt.Errorf("contract ABI with name %q not found", name)
+	return nil, fmt.Errorf("contract ABI with signature %q not found", canonical)
+}
+
+// SelectorTable maps the 4-byte selector hex of every function, as returned by MethodID, to its entry.
+// Non-function entries are skipped. Two functions with different signatures sharing a selector are reported as an error.
+func (l ContractABIs) SelectorTable() (map[string]ContractABI, error) {
+	table := make(map[string]ContractABI)
+	for _, entry := range l {
+		if entry.Type != "function" {
+			continue
+		}
+
+		selector, err := entry.MethodID()
+		if err != nil {
+			return nil, err
+		}
+		if existing, ok := table[selector]; ok && existing.signature() != entry.signature() {
+			return nil, fmt.Errorf("selector collision on %s between %s and %s", selector, existing.signature(), entry.signature())
+		}
+		table[selector] = entry
+	}
+	return table, nil
+}
+
+// Hash returns a Keccak256 hash of the ABI as a 0x-prefixed hex string, to cheaply detect changes between fetches.
+// Entries are serialized with their modeled fields and sorted by type and signature, so the hash doesn't depend on
+// the order of entries or of JSON fields in the source.
+func (l ContractABIs) Hash() string {
+	type keyedEntry struct {
+		key  string
+		json []byte
+	}
+	entries := make([]keyedEntry, len(l))
+	for i := range l {
+		// ContractABI only holds strings, bools and slices of them, which always marshal
+		data, _ := json.Marshal(l[i])
+		entries[i] = keyedEntry{key: l[i].Type + " " + l[i].signature(), json: data}
+	}
+
+	// Entries sharing a signature, like overloads differing only in outputs, are ordered by content
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].key != entries[j].key {
+			return entries[i].key < entries[j].key
+		}
+		return bytes.Compare(entries[i].json, entries[j].json) < 0
+	})
+
+	serialized := make([][]byte, len(entries))
+	for i, entry := range entries {
+		serialized[i] = entry.json
+	}
+	canonical := append(append([]byte("["), bytes.Join(serialized, []byte(","))...), ']')
+	return "0x" + hex.EncodeToString(Keccak256(canonical))
 }
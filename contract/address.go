@@ -0,0 +1,106 @@
+package contract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// ValidateAddress checks addr is 20 bytes of hex with an optional 0x prefix.
+// Mixed-case addresses must also carry a valid EIP-55 checksum, while all lowercase or
+// all uppercase addresses are accepted as unchecksummed.
+func ValidateAddress(addr string) error {
+	h := strings.TrimPrefix(addr, "0x")
+	if len(h) != 40 {
+		return fmt.Errorf("invalid address %q: expected 40 hex characters, got %d", addr, len(h))
+	}
+	if _, err := hex.DecodeString(h); err != nil {
+		return fmt.Errorf("invalid address %q: not hex", addr)
+	}
+
+	if h == strings.ToLower(h) || h == strings.ToUpper(h) {
+		return nil
+	}
+	if checksummed := checksum(h); checksummed[2:] != h {
+		return fmt.Errorf("invalid address %q: bad EIP-55 checksum, expected %s", addr, checksummed)
+	}
+	return nil
+}
+
+// ToChecksum returns addr in its EIP-55 mixed-case checksummed form
+func ToChecksum(addr string) (string, error) {
+	h := strings.TrimPrefix(addr, "0x")
+	if len(h) != 40 {
+		return "", fmt.Errorf("invalid address %q: expected 40 hex characters, got %d", addr, len(h))
+	}
+	if _, err := hex.DecodeString(h); err != nil {
+		return "", fmt.Errorf("invalid address %q: not hex", addr)
+	}
+	return checksum(h), nil
+}
+
+// normalizeAddress validates addr and returns it in lowercase with the 0x prefix
+func normalizeAddress(addr string) (string, error) {
+	if err := ValidateAddress(addr); err != nil {
+		return "", err
+	}
+	return "0x" + strings.ToLower(strings.TrimPrefix(addr, "0x")), nil
+}
+
+// checksumAddresses returns value decoded from param with every address in EIP-55 checksummed form,
+// including those in arrays and tuples
+func checksumAddresses(param abi.ABIParameter, value interface{}) interface{} {
+	if elem, _, ok := arrayElem(param); ok {
+		elements, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		checksummed := make([]interface{}, len(elements))
+		for i, element := range elements {
+			checksummed[i] = checksumAddresses(elem, element)
+		}
+		return checksummed
+	}
+
+	switch canonicalType(param.Type) {
+	case "address":
+		if addr, ok := value.(string); ok {
+			if checksummed, err := ToChecksum(addr); err == nil {
+				return checksummed
+			}
+		}
+	case "tuple":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		checksummed := make(map[string]interface{}, len(fields))
+		for k, v := range fields {
+			checksummed[k] = v
+		}
+		for i, key := range fieldKeys(param.Components) {
+			if v, ok := fields[key]; ok {
+				checksummed[key] = checksumAddresses(param.Components[i], v)
+			}
+		}
+		return checksummed
+	}
+	return value
+}
+
+// checksum applies EIP-55 to 40 hex characters: a letter is uppercased when the
+// matching nibble of the Keccak-256 hash of the lowercase address is 8 or more.
+func checksum(h string) string {
+	lower := strings.ToLower(h)
+	hash := hex.EncodeToString(abi.Keccak256([]byte(lower)))
+
+	result := []byte(lower)
+	for i, ch := range result {
+		if ch >= 'a' && hash[i] >= '8' {
+			result[i] = ch - 'a' + 'A'
+		}
+	}
+	return "0x" + string(result)
+}
@@ -0,0 +1,201 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test vectors from EIP-55
+var eip55Vectors = []string{
+	"0x52908400098527886E0F7030069857D2E4169EE7",
+	"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+	"0xde709f2102306220921060314715629080e2fb77",
+	"0x27b1fdb04752bbc536007a920d24acb045561c26",
+	"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func TestAddress_Checksum(t *testing.T) {
+	for _, vector := range eip55Vectors {
+		require.NoError(t, ValidateAddress(vector), vector)
+	}
+
+	for _, vector := range eip55Vectors[4:] {
+		checksummed, err := ToChecksum(strings.ToLower(vector))
+		require.NoError(t, err)
+		assert.Equal(t, vector, checksummed)
+
+		// Without the 0x prefix
+		checksummed, err = ToChecksum(strings.ToLower(vector[2:]))
+		require.NoError(t, err)
+		assert.Equal(t, vector, checksummed)
+	}
+
+	// Flipping the case of a single letter breaks the checksum
+	err := ValidateAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	assert.ErrorContains(t, err, "checksum")
+
+	assert.Error(t, ValidateAddress("hello"))
+	assert.Error(t, ValidateAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA"))
+	assert.Error(t, ValidateAddress("0xzaAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+
+	_, err = ToChecksum("0x1234")
+	assert.Error(t, err)
+}
+
+func TestAddress_Keccak256Override(t *testing.T) {
+	original := abi.Keccak256
+	defer func() { abi.Keccak256 = original }()
+
+	calls := 0
+	abi.Keccak256 = func(data ...[]byte) []byte {
+		calls++
+		return original(data...)
+	}
+
+	checksummed, err := ToChecksum(strings.ToLower(eip55Vectors[4]))
+	require.NoError(t, err)
+	assert.Equal(t, eip55Vectors[4], checksummed)
+	assert.Equal(t, 1, calls)
+
+	// Selectors go through the same implementation
+	methodID, err := erc20BalanceOfABI.MethodID()
+	require.NoError(t, err)
+	assert.Equal(t, "70a08231", methodID)
+	assert.Equal(t, 2, calls)
+}
+
+func TestAddress_Inputs(t *testing.T) {
+	cli := &contractClient{}
+	balanceOf := abi.ContractABI{
+		Type:    "function",
+		Name:    "balanceOf",
+		Inputs:  []abi.ABIParameter{{Name: "owner", Type: "address"}},
+		Outputs: []abi.ABIParameter{{Type: "uint256"}},
+	}
+
+	// Checksummed, lowercase and unprefixed addresses are accepted
+	for _, addr := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	} {
+		args := map[string]interface{}{"owner": addr}
+		require.NoError(t, cli.validateInputs(balanceOf, args), addr)
+		data, err := cli.encodeData(balanceOf, args)
+		require.NoError(t, err)
+		assert.Equal(t, "0x70a08231"+"0000000000000000000000005aaeb6053f3e94c9b9a09f33669435e7ef1beaed", data)
+	}
+
+	// Wrong lengths, non hex and bad checksums are rejected, also in arrays
+	for _, addr := range []string{
+		"hello",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed00",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+	} {
+		err := cli.validateInputs(balanceOf, map[string]interface{}{"owner": addr})
+		assert.ErrorContains(t, err, `input "owner"`, addr)
+		_, err = cli.encodeData(balanceOf, map[string]interface{}{"owner": addr})
+		assert.Error(t, err, addr)
+	}
+	balanceOfBatch := abi.ContractABI{
+		Type: "function",
+		Name: "balanceOfBatch",
+		Inputs: []abi.ABIParameter{
+			{Name: "accounts", Type: "address[]"},
+			{Name: "ids", Type: "uint256[]"},
+		},
+	}
+	err := cli.validateInputs(balanceOfBatch, map[string]interface{}{
+		"accounts": []string{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"},
+		"ids":      []interface{}{big.NewInt(1)},
+	})
+	assert.ErrorContains(t, err, "checksum")
+}
+
+func TestAddress_ChecksummedOutputs(t *testing.T) {
+	cli := &contractClient{}
+
+	// Addresses in tuples and arrays are checksummed too
+	owners := abi.ContractABI{
+		Type: "function",
+		Name: "owners",
+		Outputs: []abi.ABIParameter{{
+			Type: "tuple",
+			Components: []abi.ABIParameter{
+				{Name: "admin", Type: "address"},
+				{Name: "signers", Type: "address[]"},
+			},
+		}},
+	}
+	data, err := EncodeReturn(owners.Outputs, []interface{}{map[string]interface{}{
+		"admin":   "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359",
+		"signers": []interface{}{"0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb", "0xd1220a0cf47c7b9be7a2e6ba89f429762e7b9adb"},
+	}})
+	require.NoError(t, err)
+	ret, err := cli.parseResponse(data, owners)
+	require.NoError(t, err)
+	assert.Equal(t, "map[admin:0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359 "+
+		"signers:[0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB 0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb]]", ret)
+}
+
+func TestAddress_ContractAddress(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var rpcReq rpcRequest
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			return httpmock.NewStringResponse(http.StatusOK,
+				`{"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000000000000000012"}`), nil
+		})
+
+	contractABIs := loadFixtureABIs(t)
+	decimals, err := contractABIs.Find("decimals")
+	require.NoError(t, err)
+
+	// The address is sent in lowercase with the 0x prefix, however it was given
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+	for _, addr := range []string{
+		"0d500b1d8e8ef31e21c99d1db9a6444d3adf1270",
+		"0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270",
+		"0x0D500B1D8E8EF31E21C99D1DB9A6444D3ADF1270",
+	} {
+		_, err := cli.ReadContract(ctx, addr, *decimals, map[string]interface{}{})
+		require.NoError(t, err, addr)
+		assert.Equal(t, testWMATICAddr, rpcReq.Params[0].(map[string]interface{})["to"], addr)
+	}
+
+	// Malformed addresses fail before any call
+	httpmock.ZeroCallCounters()
+	tests := []struct {
+		addr     string
+		expected string
+	}{
+		{"0x0d500b1d8e8ef31e21c99d1db9a6444d3adf12", "expected 40 hex characters, got 38"},
+		{"0x0d500b1d8e8ef31e21c99d1db9a6444d3adf127000", "expected 40 hex characters, got 42"},
+		{"", "expected 40 hex characters, got 0"},
+		{"0x0d500b1d8e8ef31e21c99d1db9a6444d3adf127g", "not hex"},
+		{"0x0D500b1d8e8ef31e21c99d1db9a6444d3adf1270", "bad EIP-55 checksum"},
+	}
+	for _, test := range tests {
+		_, err := cli.ReadContract(ctx, test.addr, *decimals, map[string]interface{}{})
+		assert.ErrorContains(t, err, "invalid contract address", test.addr)
+		assert.ErrorContains(t, err, test.expected, test.addr)
+	}
+	assert.Zero(t, httpmock.GetTotalCallCount())
+}
@@ -0,0 +1,59 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// ErrNoABIProvider is returned by ReadAuto when the client has no ABI provider, see WithABIProvider
+var ErrNoABIProvider = errors.New("no ABI provider configured")
+
+// ReadAuto reads the function funcName of the contract at addr in one step, with args given in the order of its inputs.
+// The ABI of the contract is fetched from the provider set by WithABIProvider and cached for the life of the client.
+// Overloaded functions are picked like Contract.Read does, by the number of args or by a signature given as funcName.
+// Errors say which stage failed: fetching the ABI, finding the function, or the read itself.
+func (c *contractClient) ReadAuto(ctx context.Context, addr, funcName string, args ...interface{}) ([]interface{}, error) {
+	abis, err := c.contractABIs(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ABI of %s: %w", addr, err)
+	}
+
+	values, err := NewContract(addr, abis, c).Read(ctx, funcName, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s of %s: %w", funcName, addr, err)
+	}
+	return values, nil
+}
+
+// contractABIs returns the ABI of the contract at addr, fetching it from the provider on first use.
+// Failures are not cached, so a later call tries again.
+func (c *contractClient) contractABIs(ctx context.Context, addr string) (abi.ContractABIs, error) {
+	if c.abiProvider == nil {
+		return nil, ErrNoABIProvider
+	}
+
+	key := strings.ToLower(addr)
+	c.abiCacheMu.Lock()
+	abis, ok := c.abiCache[key]
+	c.abiCacheMu.Unlock()
+	if ok {
+		return abis, nil
+	}
+
+	abis, err := c.abiProvider.GetContractABI(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c.abiCacheMu.Lock()
+	if c.abiCache == nil {
+		c.abiCache = make(map[string]abi.ContractABIs)
+	}
+	c.abiCache[key] = abis
+	c.abiCacheMu.Unlock()
+	return abis, nil
+}
@@ -0,0 +1,72 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeABIProvider serves a fixed ABI and counts the fetches
+type fakeABIProvider struct {
+	abis    abi.ContractABIs
+	err     error
+	fetches int
+}
+
+func (p *fakeABIProvider) GetContractABI(ctx context.Context, address string) (abi.ContractABIs, error) {
+	p.fetches++
+	return p.abis, p.err
+}
+
+func (p *fakeABIProvider) GetSourceCode(ctx context.Context, address string) (*abi.SourceCode, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *fakeABIProvider) GetContractCreation(ctx context.Context, address string) (string, string, error) {
+	return "", "", errors.New("not implemented")
+}
+
+func (p *fakeABIProvider) GetContractCreations(ctx context.Context, addresses []string) ([]abi.ContractCreation, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestAuto_ReadAuto(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerEthCallResponder(t, map[string]string{"313ce567": erc20Results["313ce567"]})
+
+	provider := &fakeABIProvider{abis: loadFixtureABIs(t)}
+	cli := NewClient(testRPCURL, WithABIProvider(provider))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		values, err := cli.ReadAuto(ctx, testWMATICAddr, "decimals")
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{big.NewInt(6)}, values)
+	}
+	// The ABI is fetched once per contract
+	assert.Equal(t, 1, provider.fetches)
+
+	_, err := cli.ReadAuto(ctx, testWMATICAddr, "mint")
+	assert.ErrorContains(t, err, "failed to read mint of "+testWMATICAddr)
+	assert.ErrorContains(t, err, "not found")
+
+	// Failures to get the ABI are attributed, and not cached
+	failing := &fakeABIProvider{err: errors.New("contract source code not verified")}
+	cli = NewClient(testRPCURL, WithABIProvider(failing))
+	for i := 0; i < 2; i++ {
+		_, err = cli.ReadAuto(ctx, testWMATICAddr, "decimals")
+		assert.ErrorContains(t, err, "failed to get ABI of "+testWMATICAddr+": contract source code not verified")
+	}
+	assert.Equal(t, 2, failing.fetches)
+
+	_, err = NewClient(testRPCURL).ReadAuto(ctx, testWMATICAddr, "decimals")
+	assert.ErrorIs(t, err, ErrNoABIProvider)
+}
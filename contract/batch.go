@@ -0,0 +1,182 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// Call is a single contract function read in a batch
+type Call struct {
+	// Address is the contract to call. It is required by Multicall, and overrides the addr of ReadBatch when set.
+	Address string
+	ABI     abi.ContractABI
+	Args    map[string]interface{}
+}
+
+// BatchResult holds the outcome of a single call in a batch.
+// Err is set when that call failed to encode, returned an RPC error or failed to decode;
+// it does not affect the other calls in the batch.
+type BatchResult struct {
+	// Result is the output of a function with a single output, formatted like ReadContract.
+	// It is empty for functions with several outputs, which are only available in Outputs.
+	Result string
+	// Outputs are the decoded outputs, like ReadContractResults returns them
+	Outputs []CallResult
+	Err     error
+}
+
+// target returns the contract the call is made to: its Address, or addr when unset
+func (c Call) target(addr string) string {
+	if c.Address != "" {
+		return c.Address
+	}
+	return addr
+}
+
+// ReadBatch reads several functions of the contract at addr using JSON-RPC batch requests.
+// Results are returned in the same order as calls.
+//
+// Calls are sent in a single request unless WithMaxBatchSize is set. When ctx has a deadline,
+// later sub-batches are shrunk to what is likely to complete in the remaining time. If ctx ends
+// before every call is sent, the results of the completed calls are returned along with the error,
+// which is also set on each unsent call.
+func (c *contractClient) ReadBatch(ctx context.Context, addr string, calls []Call) ([]BatchResult, error) {
+	results := make([]BatchResult, len(calls))
+	if len(calls) == 0 {
+		return results, nil
+	}
+
+	// Calls which fail to encode are reported individually and left out of the request
+	reqs := make([]rpcRequest, 0, len(calls))
+	for i, call := range calls {
+		req, err := c.newEthCallRequest(i, call.target(addr), call.ABI, call.Args, newCallOptions(nil))
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		reqs = append(reqs, req)
+	}
+
+	var perCall time.Duration
+	for sent := 0; sent < len(reqs); {
+		size := c.batchSize(ctx, len(reqs)-sent, perCall)
+		chunk := reqs[sent : sent+size]
+
+		start := time.Now()
+		err := ctx.Err()
+		if err == nil {
+			err = c.sendBatch(ctx, chunk, calls, results)
+		}
+		if err != nil {
+			// The request is bound to ctx, so a cancellation aborts it in flight.
+			// Calls may target different contracts, so each unsent call names its own.
+			ctxErr := ctx.Err()
+			for _, req := range reqs[sent:] {
+				results[req.ID].Err = err
+				if ctxErr != nil {
+					results[req.ID].Err = fmt.Errorf("batch of %d calls cancelled with %d calls unsent: call to %s: %w",
+						len(reqs), len(reqs)-sent, calls[req.ID].target(addr), ctxErr)
+				}
+			}
+			return results, results[reqs[sent].ID].Err
+		}
+
+		perCall = time.Since(start) / time.Duration(size)
+		sent += size
+	}
+
+	return results, nil
+}
+
+// batchSize returns how many of the remaining calls to send in the next sub-batch
+func (c *contractClient) batchSize(ctx context.Context, remaining int, perCall time.Duration) int {
+	size := remaining
+	if c.maxBatchSize > 0 && size > c.maxBatchSize {
+		size = c.maxBatchSize
+	}
+
+	// Once the latency of a call is known, only send what fits before the deadline
+	deadline, ok := ctx.Deadline()
+	if !ok || perCall <= 0 {
+		return size
+	}
+	fit := int(time.Until(deadline) / perCall)
+	if fit < 1 {
+		fit = 1
+	}
+	if size > fit {
+		size = fit
+	}
+	return size
+}
+
+// sendBatch sends reqs as one JSON-RPC batch and stores the decoded responses into results
+func (c *contractClient) sendBatch(ctx context.Context, reqs []rpcRequest, calls []Call, results []BatchResult) error {
+	payload, err := json.Marshal(reqs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch: %w", err)
+	}
+
+	body, err := c.post(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	var resps []rpcResponse
+	if err := json.Unmarshal(body, &resps); err != nil {
+		return fmt.Errorf("failed to unmarshal batch response %s: %w", snippet(body), err)
+	}
+
+	// Responses may arrive in any order, so match them to calls by id
+	pending := make(map[int]bool, len(reqs))
+	for _, req := range reqs {
+		pending[req.ID] = true
+	}
+	for _, resp := range resps {
+		if !pending[resp.ID] {
+			return fmt.Errorf("unexpected response id %d in batch", resp.ID)
+		}
+		delete(pending, resp.ID)
+
+		if resp.Error != nil {
+			results[resp.ID].Err = c.revertError(resp.Error, nil)
+			continue
+		}
+
+		result, err := resp.hexResult()
+		if err != nil {
+			results[resp.ID].Err = err
+			continue
+		}
+		results[resp.ID] = c.batchResult(result, calls[resp.ID].ABI)
+	}
+
+	for id := range pending {
+		results[id].Err = fmt.Errorf("no response for call %d in batch", id)
+	}
+
+	return nil
+}
+
+// batchResult decodes the 0x-prefixed eth_call result resp of a call to fn in a batch
+func (c *contractClient) batchResult(resp string, fn abi.ContractABI) BatchResult {
+	data, err := decodeData(resp)
+	if err != nil {
+		return BatchResult{Err: fmt.Errorf("failed to decode response data: %w", err)}
+	}
+	values, err := c.decoder().decodeValues(fn.Outputs, data)
+	if err != nil {
+		return BatchResult{Err: err}
+	}
+
+	var result BatchResult
+	if len(fn.Outputs) == 1 {
+		result.Result = formatValue(checksumAddresses(fn.Outputs[0], values[0]))
+	}
+	result.Outputs = callResults(flattenTuple(fn.Outputs, values))
+	return result
+}
@@ -0,0 +1,290 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRPCURL = "https://rpc.example.com"
+
+func TestContract_ReadBatch(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var batch []rpcRequest
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &batch))
+
+			// Out of order on purpose, with an error for the balanceOf call
+			return httpmock.NewStringResponse(http.StatusOK, `[
+				{"jsonrpc":"2.0","id":2,"result":"0x0000000000000000000000000000000000000000000000000000000000000012"},
+				{"jsonrpc":"2.0","id":0,"result":"0x0000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000d57726170706564204d6174696300000000000000000000000000000000000000"},
+				{"jsonrpc":"2.0","id":3,"error":{"code":-32000,"message":"execution reverted"}},
+				{"jsonrpc":"2.0","id":1,"result":"0x00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000006574d415449430000000000000000000000000000000000000000000000000000"}
+			]`), nil
+		})
+
+	contractABIs := loadFixtureABIs(t)
+	find := func(name string) Call {
+		entry, err := contractABIs.Find(name)
+		require.NoError(t, err)
+		return Call{ABI: *entry}
+	}
+
+	balanceOf := find("balanceOf")
+	balanceOf.Args = map[string]interface{}{"": "0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214"}
+
+	badBalanceOf := find("balanceOf")
+	badBalanceOf.Args = map[string]interface{}{"": big.NewInt(1)}
+
+	calls := []Call{find("name"), find("symbol"), find("decimals"), balanceOf, badBalanceOf}
+
+	cli := NewClient(testRPCURL)
+	results, err := cli.ReadBatch(context.Background(), "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", calls)
+	require.NoError(t, err)
+	require.Len(t, results, len(calls))
+
+	// A single HTTP request carries every encodable call
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	assert.Len(t, batch, 4)
+
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "Wrapped Matic", results[0].Result)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, "WMATIC", results[1].Result)
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, "18", results[2].Result)
+	assert.ErrorContains(t, results[3].Err, "execution reverted")
+	assert.ErrorContains(t, results[4].Err, "invalid type")
+}
+
+func TestContract_ReadBatchCancel(t *testing.T) {
+	// The server never answers, it only returns once the client goes away or the test ends
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	totalSupply := abi.ContractABI{
+		Name:    "totalSupply",
+		Type:    "function",
+		Outputs: []abi.ABIParameter{{Type: "uint256"}},
+	}
+	calls := []Call{{ABI: totalSupply}, {ABI: totalSupply}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	cli := NewClient(srv.URL)
+	start := time.Now()
+	_, err := cli.ReadBatch(ctx, "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", calls)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "batch of 2 calls")
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	// An already cancelled context never reaches the server
+	_, err = cli.ReadBatch(ctx, "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", calls)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "call to 0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270")
+
+	// Calls to their own contracts, as the Multicall fallback makes, name the contract of each unsent call
+	usdc := "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"
+	results, err := cli.ReadBatch(ctx, "", []Call{{Address: usdc, ABI: totalSupply}, {ABI: totalSupply, Address: testWMATICAddr}})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "call to "+usdc)
+	assert.ErrorContains(t, results[1].Err, "call to "+testWMATICAddr)
+}
+
+// batchRecorder records the size of each batch request received.
+// httpmock may still run a responder after the request context ended, hence the lock.
+type batchRecorder struct {
+	mu    sync.Mutex
+	sizes []int
+}
+
+func (r *batchRecorder) record(size int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sizes = append(r.sizes, size)
+}
+
+func (r *batchRecorder) batchSizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.sizes...)
+}
+
+// echoBatchResponder answers every call of a batch with result after waiting delay
+func echoBatchResponder(t *testing.T, delay time.Duration, result string, recorder *batchRecorder) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		var batch []rpcRequest
+		require.NoError(t, json.Unmarshal(body, &batch))
+		recorder.record(len(batch))
+
+		time.Sleep(delay)
+
+		resps := make([]map[string]interface{}, len(batch))
+		for i, r := range batch {
+			resps[i] = map[string]interface{}{"jsonrpc": "2.0", "id": r.ID, "result": result}
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, resps)
+	}
+}
+
+func TestContract_ReadBatchMaxSize(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var recorder batchRecorder
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		echoBatchResponder(t, 0, erc20Results["18160ddd"], &recorder))
+
+	calls := make([]Call, 5)
+	for i := range calls {
+		calls[i] = Call{ABI: erc20TotalSupplyABI}
+	}
+
+	cli := NewClient(testRPCURL, WithMaxBatchSize(2))
+	results, err := cli.ReadBatch(context.Background(), testTokenAddr, calls)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 2, 1}, recorder.batchSizes())
+
+	for _, result := range results {
+		require.NoError(t, result.Err)
+		assert.Equal(t, "1180591620717411303424", result.Result)
+	}
+}
+
+func TestContract_ReadBatchDeadline(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var recorder batchRecorder
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		echoBatchResponder(t, 40*time.Millisecond, erc20Results["18160ddd"], &recorder))
+
+	calls := make([]Call, 20)
+	for i := range calls {
+		calls[i] = Call{ABI: erc20TotalSupplyABI}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	cli := NewClient(testRPCURL, WithMaxBatchSize(4))
+	results, err := cli.ReadBatch(ctx, testTokenAddr, calls)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Len(t, results, len(calls))
+
+	// The first sub-batch completes, later ones shrink or are never sent
+	batchSizes := recorder.batchSizes()
+	assert.Equal(t, 4, batchSizes[0])
+	sent := 0
+	for _, size := range batchSizes {
+		sent += size
+	}
+	assert.Less(t, sent, len(calls))
+
+	for i, result := range results {
+		if i < 4 {
+			require.NoError(t, result.Err)
+			assert.Equal(t, "1180591620717411303424", result.Result)
+		}
+	}
+	assert.ErrorIs(t, results[len(results)-1].Err, context.DeadlineExceeded)
+}
+
+func TestContract_ReadBatchOutputs(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	getReserves := abi.ContractABI{
+		Type: "function",
+		Name: "getReserves",
+		Outputs: []abi.ABIParameter{
+			{Name: "reserve0", Type: "uint112"},
+			{Name: "reserve1", Type: "uint112"},
+			{Name: "blockTimestampLast", Type: "uint32"},
+		},
+	}
+	reserves, err := EncodeReturn(getReserves.Outputs, []interface{}{big.NewInt(1000), big.NewInt(2000), big.NewInt(1700000000)})
+	require.NoError(t, err)
+
+	var batch []rpcRequest
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&batch))
+			return httpmock.NewStringResponse(http.StatusOK, `[
+				{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted"}},
+				{"jsonrpc":"2.0","id":2,"result":"`+reserves+`"},
+				{"jsonrpc":"2.0","id":0,"result":"0x00000000000000000000000000000000000000000000000000000000000f4240"}
+			]`), nil
+		})
+
+	balanceOf, err := loadFixtureABIs(t).Find("balanceOf")
+	require.NoError(t, err)
+	holder := map[string]interface{}{"": "0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214"}
+	calls := []Call{
+		{Address: "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174", ABI: *balanceOf, Args: holder},
+		{Address: "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", ABI: *balanceOf, Args: holder},
+		{Address: "0x6e7a5FAFcec6BB1e78bAE2A1F0B612012BF14827", ABI: getReserves},
+	}
+
+	cli := NewClient(testRPCURL)
+	results, err := cli.ReadBatch(context.Background(), "", calls)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	// Every call has its own id and target
+	require.Len(t, batch, 3)
+	for i, req := range batch {
+		assert.Equal(t, i, req.ID)
+		assert.Equal(t, strings.ToLower(calls[i].Address), req.Params[0].(map[string]interface{})["to"])
+	}
+
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "1000000", results[0].Result)
+	require.Len(t, results[0].Outputs, 1)
+	balance, err := results[0].Outputs[0].BigInt()
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000000), balance)
+
+	// A failed call leaves the others decoded
+	assert.ErrorIs(t, results[1].Err, ErrExecutionReverted)
+	assert.Empty(t, results[1].Outputs)
+
+	// Several outputs are only available typed
+	require.NoError(t, results[2].Err)
+	assert.Empty(t, results[2].Result)
+	require.Len(t, results[2].Outputs, 3)
+	assert.Equal(t, "reserve1", results[2].Outputs[1].Param.Name)
+	reserve1, err := results[2].Outputs[1].BigInt()
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2000), reserve1)
+	_, err = results[2].Outputs[2].Address()
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+}
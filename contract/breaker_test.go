@@ -0,0 +1,103 @@
+package contract
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFallbackRPCURL = "https://fallback.example.com"
+
+func TestBreaker_Failover(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	chainIDResponse := `{"jsonrpc":"2.0","id":1,"result":"0x89"}`
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL, httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+	httpmock.RegisterResponder(http.MethodPost, testFallbackRPCURL, httpmock.NewStringResponder(http.StatusOK, chainIDResponse))
+
+	var mu sync.Mutex
+	var notices []Notice
+	observer := func(n Notice) {
+		mu.Lock()
+		defer mu.Unlock()
+		notices = append(notices, n)
+	}
+
+	cooldown := 50 * time.Millisecond
+	cli := NewClient(testRPCURL,
+		WithFallbackURLs(testFallbackRPCURL),
+		WithCircuitBreaker(2, cooldown),
+		WithObserver(observer))
+
+	ctx := context.Background()
+	primaryCalls := func() int {
+		return httpmock.GetCallCountInfo()["POST "+testRPCURL]
+	}
+
+	// Failures of the primary are routed to the fallback until the breaker opens
+	for i := 0; i < 4; i++ {
+		chainID, err := cli.ChainID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(137), chainID.Int64())
+	}
+	assert.Equal(t, 2, primaryCalls())
+
+	// After the cooldown a single trial goes to the primary, which fails again
+	time.Sleep(cooldown)
+	_, err := cli.ChainID(ctx)
+	require.NoError(t, err)
+	_, err = cli.ChainID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, primaryCalls())
+
+	// The primary recovers, registering the responder again resets its call count
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL, httpmock.NewStringResponder(http.StatusOK, chainIDResponse))
+	time.Sleep(cooldown)
+	_, err = cli.ChainID(ctx)
+	require.NoError(t, err)
+	_, err = cli.ChainID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, primaryCalls())
+
+	mu.Lock()
+	defer mu.Unlock()
+	var states []BreakerState
+	for _, n := range notices {
+		assert.Equal(t, NoticeBreakerStateChange, n.Kind)
+		assert.Equal(t, testRPCURL, n.Endpoint)
+		states = append(states, n.Breaker)
+	}
+	assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}, states)
+}
+
+func TestBreaker_AllOpen(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL, httpmock.NewStringResponder(http.StatusBadGateway, ""))
+
+	cli := NewClient(testRPCURL, WithCircuitBreaker(3, time.Minute))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cli.ChainID(ctx)
+			assert.ErrorContains(t, err, "unexpected status code: 502")
+		}()
+	}
+	wg.Wait()
+
+	_, err := cli.ChainID(ctx)
+	require.ErrorIs(t, err, ErrNoEndpointAvailable)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
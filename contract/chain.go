@@ -0,0 +1,129 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ChainID returns the chain id of the connected node via eth_chainId
+func (c *contractClient) ChainID(ctx context.Context) (*big.Int, error) {
+	if c.cacheChainID {
+		c.chainIDMu.Lock()
+		defer c.chainIDMu.Unlock()
+
+		if c.chainID != nil {
+			return new(big.Int).Set(c.chainID), nil
+		}
+	}
+
+	result, err := c.call(ctx, "eth_chainId")
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := decodeQuantity(result)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cacheChainID {
+		c.chainID = new(big.Int).Set(chainID)
+	}
+	return chainID, nil
+}
+
+// BlockNumber returns the number of the most recent block via eth_blockNumber
+func (c *contractClient) BlockNumber(ctx context.Context) (*big.Int, error) {
+	result, err := c.call(ctx, "eth_blockNumber")
+	if err != nil {
+		return nil, err
+	}
+	return decodeQuantity(result)
+}
+
+// blockHeader holds the fields of a block returned by eth_getBlockByNumber that the client uses
+type blockHeader struct {
+	Number    string `json:"number"`
+	Timestamp string `json:"timestamp"`
+}
+
+// AverageBlockTime returns the average interval between blocks over the last sampleBlocks blocks,
+// e.g. to choose the poll interval of WaitForReceipt. It only reads the timestamps of the latest block
+// and of the block sampleBlocks before it, so irregular block times, like skipped slots or several blocks
+// sharing a timestamp on chains producing more than a block per second, average out over the span.
+// The result is cached per sample size for the lifetime of the client.
+func (c *contractClient) AverageBlockTime(ctx context.Context, sampleBlocks int) (time.Duration, error) {
+	if sampleBlocks <= 0 {
+		return 0, fmt.Errorf("invalid number of sample blocks: %d", sampleBlocks)
+	}
+
+	c.blockTimeMu.Lock()
+	defer c.blockTimeMu.Unlock()
+
+	if blockTime, ok := c.blockTimes[sampleBlocks]; ok {
+		return blockTime, nil
+	}
+
+	latest, latestTime, err := c.blockTimestamp(ctx, "latest")
+	if err != nil {
+		return 0, err
+	}
+
+	// A young chain has fewer blocks than requested
+	span := big.NewInt(int64(sampleBlocks))
+	if latest.Cmp(span) < 0 {
+		span.Set(latest)
+	}
+	if span.Sign() == 0 {
+		return 0, fmt.Errorf("not enough blocks to average block time")
+	}
+
+	_, firstTime, err := c.blockTimestamp(ctx, blockParam(new(big.Int).Sub(latest, span)))
+	if err != nil {
+		return 0, err
+	}
+
+	elapsed := latestTime - firstTime
+	if elapsed <= 0 {
+		return 0, fmt.Errorf("block timestamps don't advance over the last %s blocks", span)
+	}
+	blockTime := time.Duration(elapsed) * time.Second / time.Duration(span.Int64())
+
+	if c.blockTimes == nil {
+		c.blockTimes = make(map[int]time.Duration)
+	}
+	c.blockTimes[sampleBlocks] = blockTime
+	return blockTime, nil
+}
+
+// blockTimestamp returns the number and the Unix timestamp of block via eth_getBlockByNumber
+func (c *contractClient) blockTimestamp(ctx context.Context, block string) (*big.Int, int64, error) {
+	result, err := c.callJSON(ctx, "eth_getBlockByNumber", block, false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var header *blockHeader
+	if err := json.Unmarshal(result, &header); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal block %s: %w", block, err)
+	}
+	if header == nil {
+		return nil, 0, fmt.Errorf("block %s not found", block)
+	}
+
+	number, err := decodeQuantity(header.Number)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid number of block %s: %w", block, err)
+	}
+	timestamp, err := decodeQuantity(header.Timestamp)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid timestamp of block %s: %w", block, err)
+	}
+	if !timestamp.IsInt64() {
+		return nil, 0, fmt.Errorf("timestamp of block %s out of range: %s", block, timestamp)
+	}
+	return number, timestamp.Int64(), nil
+}
@@ -0,0 +1,105 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContract_ChainID(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x89"}`))
+
+	ctx := context.Background()
+
+	cli := NewClient(testRPCURL)
+	for i := 0; i < 2; i++ {
+		chainID, err := cli.ChainID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(137), chainID.Int64())
+	}
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+
+	httpmock.ZeroCallCounters()
+
+	cached := NewClient(testRPCURL, WithChainIDCache())
+	for i := 0; i < 2; i++ {
+		chainID, err := cached.ChainID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(137), chainID.Int64())
+
+		// Callers must not be able to alter the cached value
+		chainID.SetInt64(1)
+	}
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestContract_AverageBlockTime(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// Blocks 0x10 to 0x64, two seconds apart on average with skipped slots in between
+	blocks := map[string]string{
+		"latest": `{"number":"0x64","timestamp":"0x6553f100"}`,
+		"0x5a":   `{"number":"0x5a","timestamp":"0x6553f0ec"}`,
+		"0x0":    `{"number":"0x0","timestamp":"0x6553f038"}`,
+	}
+	var requested []string
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			var rpcReq rpcRequest
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			require.Equal(t, "eth_getBlockByNumber", rpcReq.Method)
+
+			block := rpcReq.Params[0].(string)
+			requested = append(requested, block)
+			result, ok := blocks[block]
+			if !ok {
+				result = "null"
+			}
+			return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":`+result+`}`), nil
+		})
+
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+
+	for i := 0; i < 2; i++ {
+		blockTime, err := cli.AverageBlockTime(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2*time.Second, blockTime)
+	}
+	assert.Equal(t, []string{"latest", "0x5a"}, requested)
+
+	// Sampling more blocks than the chain has stops at genesis
+	blockTime, err := cli.AverageBlockTime(ctx, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, blockTime)
+
+	_, err = cli.AverageBlockTime(ctx, 0)
+	assert.Error(t, err)
+
+	// Several blocks per second give a sub-second average
+	blocks["latest"] = `{"number":"0x64","timestamp":"0x6553f100"}`
+	blocks["0x5a"] = `{"number":"0x5a","timestamp":"0x6553f0fd"}`
+	fast := NewClient(testRPCURL)
+	blockTime, err = fast.AverageBlockTime(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 300*time.Millisecond, blockTime)
+
+	// Timestamps which don't advance can't be averaged
+	blocks["0x5a"] = `{"number":"0x5a","timestamp":"0x6553f100"}`
+	_, err = NewClient(testRPCURL).AverageBlockTime(ctx, 10)
+	assert.ErrorContains(t, err, "don't advance")
+
+	_, err = NewClient(testRPCURL).AverageBlockTime(ctx, 5)
+	assert.ErrorContains(t, err, "not found")
+}
@@ -1,81 +1,269 @@
 package contract
 
 import (
-	"bytes"
 	"context"
+	"crypto/ecdsa"
 	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"math/big"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/rootwarp/vinculum/contract/abi"
 )
 
+// ErrEmptyResult is returned by reads made WithErrorOnEmpty when a collection they return is empty
+var ErrEmptyResult = errors.New("empty result")
+
+// ErrValidationFailed is returned when a validator set by WithValidator rejects the result of a read
+var ErrValidationFailed = errors.New("result validation failed")
+
 // ContractClient is an interface a contract
 type ContractClient interface {
-	ReadContract(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}) (string, error)
+	ReadContract(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) (string, error)
+	ReadContractValues(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) ([]interface{}, error)
+	ReadContractMap(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) (map[string]interface{}, error)
+	ReadContractDescribed(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) (interface{}, []abi.ABIParameter, error)
+	ReadContractLazy(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) (*LazyResult, error)
+	ReadContractResults(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) ([]CallResult, error)
+	ReadAuto(ctx context.Context, addr, funcName string, args ...interface{}) ([]interface{}, error)
+	ReadBatch(ctx context.Context, addr string, calls []Call) ([]BatchResult, error)
+	Multicall(ctx context.Context, calls []Call) ([]BatchResult, error)
+	GetLogs(ctx context.Context, addr string, event abi.ContractABI, fromBlock, toBlock *big.Int, opts ...CallOption) ([]RawLog, error)
+	GetLogsMulti(ctx context.Context, addrs []string, event abi.ContractABI, fromBlock, toBlock *big.Int, opts ...CallOption) ([]DecodedLog, error)
+	ResolveName(ctx context.Context, name string) (string, error)
+	GetStorageAt(ctx context.Context, addr, slot string) (string, error)
+	ReadStorageVar(ctx context.Context, addr string, layout *abi.StorageLayout, varName string) (interface{}, error)
+	GetProxyImplementation(ctx context.Context, addr string) (string, error)
+	GetProxyAdmin(ctx context.Context, addr string) (string, error)
+	GetBeacon(ctx context.Context, addr string) (string, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (*big.Int, error)
+	AverageBlockTime(ctx context.Context, sampleBlocks int) (time.Duration, error)
+	DetectTokenStandard(ctx context.Context, addr string) (TokenStandard, error)
+	EstimateGas(ctx context.Context, from, to string, abi abi.ContractABI, args map[string]interface{}) (uint64, error)
+	SignTransaction(key *ecdsa.PrivateKey, addr string, abi abi.ContractABI, args map[string]interface{}, params TxParams) (string, error)
+	SendTransaction(ctx context.Context, key *ecdsa.PrivateKey, addr string, abi abi.ContractABI, args map[string]interface{}, params TxParams) (string, error)
+	SendRawTransaction(ctx context.Context, signedTxHex string) (string, error)
+	GetTransactionReceipt(ctx context.Context, txHash string) (*Receipt, error)
+	WaitForReceipt(ctx context.Context, txHash string, pollInterval time.Duration, opts ...WaitOption) (*Receipt, error)
 }
 
 type contractClient struct {
-	rpcURL string
+	rpcURL       string
+	utf8Mode     UTF8Mode
+	strictBool   bool
+	maxDepth     int
+	maxBatchSize int
+
+	methodDiagnostics bool
+	multicallAddr     string
+
+	maxConcurrency int
+
+	httpClient       *http.Client
+	timeout          time.Duration
+	roundTripper     http.RoundTripper
+	transport        transport
+	fallbackURLs     []string
+	retryAttempts    int
+	retryDelay       time.Duration
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	observer         Observer
+	clock            Clock
+
+	cacheChainID bool
+	chainIDMu    sync.Mutex
+	chainID      *big.Int
+
+	blockTimeMu sync.Mutex
+	blockTimes  map[int]time.Duration
+
+	ensURL     string
+	ensEnabled bool
+	ens        *contractClient
+
+	abiProvider abi.ABI
+	abiCacheMu  sync.Mutex
+	abiCache    map[string]abi.ContractABIs
 }
 
-func (c *contractClient) ReadContract(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}) (string, error) {
-	if err := c.validateInputs(abi, args); err != nil {
+// ReadContract reads a function with a single output and returns it formatted as a string.
+// Addresses, including those in arrays and tuples, are returned in EIP-55 checksummed form.
+func (c *contractClient) ReadContract(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) (string, error) {
+	abi, resultData, err := c.readContract(ctx, addr, abi, args, opts)
+	if err != nil {
 		return "", err
 	}
+	return c.parseResponse(resultData, abi)
+}
+
+// ReadContractValues reads the contract like ReadContract and returns every output as a typed value:
+// *big.Int for integers, bool, string for addresses and strings, []byte for bytes,
+// []interface{} for arrays and map[string]interface{} for tuples.
+// A function returning a single tuple, i.e. a struct, returns the components of the tuple in order,
+// the same as a function returning them as multiple outputs.
+func (c *contractClient) ReadContractValues(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) ([]interface{}, error) {
+	_, values, err := c.readValues(ctx, addr, abi, args, opts)
+	return values, err
+}
 
-	data, err := c.encodeData(abi, args)
+// ReadContractMap reads the contract like ReadContractValues and returns the outputs keyed by name.
+// Unnamed outputs are keyed by their index, e.g. "0", and duplicate names get their index appended, e.g. "amount_2".
+// A single tuple output is keyed by its components, the same as multiple outputs.
+func (c *contractClient) ReadContractMap(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) (map[string]interface{}, error) {
+	abi, values, err := c.readValues(ctx, addr, abi, args, opts)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Build the call data with method ID and encoded arguments
-	callData := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"method":  "eth_call",
-		"params": []interface{}{
-			map[string]string{
-				"to":   addr,
-				"data": data,
-			},
-			"latest",
-		},
-		"id": 1,
+	keys := fieldKeys(abi.Outputs)
+	result := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		result[keys[i]] = value
 	}
+	return result, nil
+}
 
-	jsonData, err := json.Marshal(callData)
+// ReadContractDescribed reads the contract and returns the decoded result along with the outputs describing it,
+// so generic callers can render any read without knowing its types.
+// A single output is returned as its value, and several outputs as a map like ReadContractMap.
+// The schema lists the outputs the value was decoded with, including nested tuple components.
+func (c *contractClient) ReadContractDescribed(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) (interface{}, []abi.ABIParameter, error) {
+	abi, values, err := c.readValues(ctx, addr, abi, args, opts)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal call data: %w", err)
+		return nil, nil, err
+	}
+
+	schema := cloneParameters(abi.Outputs)
+	if len(values) == 1 {
+		return values[0], schema, nil
 	}
 
-	resp, err := http.Post(c.rpcURL, "application/json", bytes.NewBuffer(jsonData))
+	keys := fieldKeys(abi.Outputs)
+	result := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		result[keys[i]] = value
+	}
+	return result, schema, nil
+}
+
+// readValues reads the contract and decodes every output.
+// It also returns the ABI the outputs were decoded with.
+func (c *contractClient) readValues(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts []CallOption) (abi.ContractABI, []interface{}, error) {
+	abi, resultData, err := c.readContract(ctx, addr, abi, args, opts)
 	if err != nil {
-		return "", fmt.Errorf("failed to make RPC call: %w", err)
+		return abi, nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	data, err := decodeData(resultData)
+	if err != nil {
+		return abi, nil, fmt.Errorf("failed to decode response data: %w", err)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	values, err := c.decoder().decodeValues(abi.Outputs, data)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return abi, nil, err
 	}
 
-	var result struct {
-		Result string `json:"result"`
+	abi.Outputs, values = flattenTuple(abi.Outputs, values)
+
+	callOpts := newCallOptions(opts)
+	if callOpts.errorOnEmpty {
+		if err := checkEmptyArrays(abi.Outputs, values); err != nil {
+			return abi, nil, err
+		}
 	}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+
+	if keys := callOpts.timestampOutputs; len(keys) > 0 {
+		if err := convertTimestamps(abi.Outputs, values, keys); err != nil {
+			return abi, nil, err
+		}
 	}
 
-	resultData := strings.TrimPrefix(result.Result, "0x")
-	return c.parseResponse(resultData, abi)
+	if callOpts.jsonNumbers {
+		for i, value := range values {
+			values[i] = toJSONNumbers(value)
+		}
+	}
+	if callOpts.uint256Outputs {
+		for i, value := range values {
+			values[i] = toUint256s(abi.Outputs[i], value)
+		}
+	}
+
+	for _, validate := range callOpts.validators {
+		if err := validate(values); err != nil {
+			return abi, nil, fmt.Errorf("%w: %s: %w", ErrValidationFailed, abi.Name, err)
+		}
+	}
+	return abi, values, nil
+}
+
+// checkEmptyArrays returns ErrEmptyResult for the first array output without elements
+func checkEmptyArrays(params []abi.ABIParameter, values []interface{}) error {
+	keys := fieldKeys(params)
+	for i, param := range params {
+		if _, _, ok := arrayElem(param); !ok {
+			continue
+		}
+		if elements, ok := values[i].([]interface{}); ok && len(elements) == 0 {
+			return fmt.Errorf("%w: output %s has no elements", ErrEmptyResult, keys[i])
+		}
+	}
+	return nil
+}
+
+// readContract issues the eth_call of a read.
+// It returns the 0x-prefixed hex result along with the ABI its outputs must be decoded with.
+func (c *contractClient) readContract(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts []CallOption) (abi.ContractABI, string, error) {
+	callOpts := newCallOptions(opts)
+	if callOpts.outputTypes != nil {
+		// abi is a copy, so replacing its outputs doesn't affect the caller
+		abi.Outputs = outputParameters(callOpts.outputTypes)
+	}
+
+	if c.ensEnabled {
+		var err error
+		if addr, args, err = c.resolveNames(ctx, addr, abi, args); err != nil {
+			return abi, "", err
+		}
+	}
+	callData, err := c.newEthCallRequest(1, addr, abi, args, callOpts)
+	if err != nil {
+		return abi, "", err
+	}
+
+	result, err := c.send(ctx, callData)
+	if err != nil {
+		if c.methodDiagnostics && isBareRevert(err) {
+			if diag := c.diagnoseRead(ctx, addr, abi); diag != nil {
+				return abi, "", fmt.Errorf("%w: %w", diag, normalizeRevert(err))
+			}
+		}
+		hint := isBareRevert(err)
+		err = c.revertError(err, callOpts.errorABIs)
+		if hint {
+			err = mutabilityHint(err, abi)
+		}
+		if len(callOpts.stateOverrides) > 0 {
+			err = stateOverrideHint(err)
+		}
+		return abi, "", err
+	}
+
+	// Calling a function the contract doesn't have may also succeed with nothing returned
+	if c.methodDiagnostics && result == "0x" && len(abi.Outputs) > 0 {
+		if diag := c.diagnoseRead(ctx, addr, abi); diag != nil {
+			return abi, "", fmt.Errorf("%w: empty result", diag)
+		}
+	}
+
+	return abi, result, nil
 }
 
 func (c *contractClient) validateInputs(abi abi.ContractABI, args map[string]interface{}) error {
@@ -83,6 +271,9 @@ func (c *contractClient) validateInputs(abi abi.ContractABI, args map[string]int
 	if len(args) != len(abi.Inputs) {
 		return fmt.Errorf("argument count mismatch: expected %d, got %d", len(abi.Inputs), len(args))
 	}
+	if err := checkDepth(abi.Inputs, c.maxDepth); err != nil {
+		return err
+	}
 
 	// Verify each provided argument matches the expected type
 	for _, input := range abi.Inputs {
@@ -91,15 +282,43 @@ func (c *contractClient) validateInputs(abi abi.ContractABI, args map[string]int
 			return fmt.Errorf("missing argument for input %q", input.Name)
 		}
 
+		// Arrays hold a slice of their element type, e.g. []*big.Int for uint256[], checked element by element
+		if _, _, ok := arrayElem(input); ok {
+			if _, err := encodeValue(input, arg); err != nil {
+				return fmt.Errorf("invalid value for input %q: %w", input.Name, err)
+			}
+			continue
+		}
+
+		// Integers of every width hold a *big.Int or a Uint256, which must fit the declared width
+		if signed, bits, ok := parseIntType(canonicalType(input.Type)); ok {
+			var value *big.Int
+			switch v := arg.(type) {
+			case *big.Int:
+				value = v
+			case Uint256:
+				value = v.Big()
+			default:
+				return fmt.Errorf("invalid type for input %q: expected *big.Int, got %T", input.Name, arg)
+			}
+			if value == nil {
+				return fmt.Errorf("invalid value for input %q: nil *big.Int", input.Name)
+			}
+			if _, err := encodeInt(value, signed, bits); err != nil {
+				return fmt.Errorf("invalid value for input %q: %w", input.Name, err)
+			}
+			continue
+		}
+
 		// Check if argument type matches the ABI input type
-		switch input.Type {
+		switch canonicalType(input.Type) {
 		case "address":
-			if _, ok := arg.(string); !ok {
+			addr, ok := arg.(string)
+			if !ok {
 				return fmt.Errorf("invalid type for input %q: expected address string, got %T", input.Name, arg)
 			}
-		case "uint256":
-			if _, ok := arg.(*big.Int); !ok {
-				return fmt.Errorf("invalid type for input %q: expected *big.Int, got %T", input.Name, arg)
+			if err := ValidateAddress(addr); err != nil {
+				return fmt.Errorf("invalid value for input %q: %w", input.Name, err)
 			}
 		case "bool":
 			if _, ok := arg.(bool); !ok {
@@ -109,8 +328,33 @@ func (c *contractClient) validateInputs(abi abi.ContractABI, args map[string]int
 			if _, ok := arg.(string); !ok {
 				return fmt.Errorf("invalid type for input %q: expected string, got %T", input.Name, arg)
 			}
+		case "bytes":
+			if _, err := toBytes(arg); err != nil {
+				return fmt.Errorf("invalid value for input %q: %w", input.Name, err)
+			}
+		case "tuple":
+			// Each component is checked against its own type, static or dynamic
+			if _, err := encodeValue(input, arg); err != nil {
+				return fmt.Errorf("invalid value for input %q: %w", input.Name, err)
+			}
 		default:
-			return fmt.Errorf("unsupported input type: %s", input.Type)
+			if codec, ok := lookupType(input.Type); ok {
+				if _, err := encodeCustom(codec, input.Type, arg); err != nil {
+					return fmt.Errorf("invalid value for input %q: %w", input.Name, err)
+				}
+				continue
+			}
+			size, ok := parseFixedBytesType(input.Type)
+			if !ok {
+				return fmt.Errorf("unsupported input type: %s", input.Type)
+			}
+			b, err := toBytes(arg)
+			if err != nil {
+				return fmt.Errorf("invalid value for input %q: %w", input.Name, err)
+			}
+			if len(b) > size {
+				return fmt.Errorf("invalid value for input %q: %d bytes do not fit in %s", input.Name, len(b), input.Type)
+			}
 		}
 	}
 
@@ -122,117 +366,175 @@ func (c *contractClient) encodeData(abi abi.ContractABI, args map[string]interfa
 	if err != nil {
 		return "", fmt.Errorf("failed to get method ID: %w", err)
 	}
+	selector, err := hex.DecodeString(methodID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get method ID: %w", err)
+	}
 
-	data := "0x" + methodID
+	values := make([]interface{}, len(abi.Inputs))
+	for i, input := range abi.Inputs {
+		values[i] = args[input.Name]
+	}
 
-	// Encode each argument according to its type and append to data
-	for _, input := range abi.Inputs {
-		arg := args[input.Name]
-		var encoded string
+	// Static arguments are placed in the head and dynamic ones in the tail after every head,
+	// each referenced by its offset from the start of the arguments
+	encoded, err := encodeValues(abi.Inputs, values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode inputs of %s: %w", abi.Name, err)
+	}
 
-		switch input.Type {
-		case "address":
-			// Remove "0x" prefix if present and pad address to 32 bytes
-			addr := strings.TrimPrefix(arg.(string), "0x")
-			encoded = fmt.Sprintf("%064s", addr)
-		case "uint256":
-			// Convert big.Int to hex string and pad to 32 bytes
-			bigInt := arg.(*big.Int)
-			encoded = fmt.Sprintf("%064s", bigInt.Text(16))
-		case "bool":
-			// Encode bool as 0 or 1 padded to 32 bytes
-			if arg.(bool) {
-				encoded = fmt.Sprintf("%064s", "1")
-			} else {
-				encoded = fmt.Sprintf("%064s", "0")
-			}
-		case "string":
-			// For dynamic types like string:
-			// 1. Get the string bytes
-			str := []byte(arg.(string))
-			// 2. Calculate offset position (32 bytes per previous static argument)
-			offset := big.NewInt(int64(32 * len(abi.Inputs)))
-			// 3. Add length of the string
-			length := big.NewInt(int64(len(str)))
-			// 4. Encode offset and length as padded hex
-			encoded = fmt.Sprintf("%064s%064s", offset.Text(16), length.Text(16))
-			// 5. Add the actual string data padded to nearest 32 bytes
-			paddedLen := (len(str) + 31) / 32 * 32
-			paddedStr := make([]byte, paddedLen)
-			copy(paddedStr, str)
-			encoded += hex.EncodeToString(paddedStr)
-		default:
-			return "", fmt.Errorf("unsupported type for encoding: %s", input.Type)
-		}
+	// Calldata is assembled as bytes and hex encoded once at the end
+	data := make([]byte, 0, len(selector)+len(encoded))
+	data = append(data, selector...)
+	data = append(data, encoded...)
+	return "0x" + hex.EncodeToString(data), nil
+}
 
-		data += encoded
+// toBytes normalizes a bytes argument given as []byte or as a hex string with or without 0x prefix
+func toBytes(arg interface{}) ([]byte, error) {
+	switch v := arg.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		h := strings.TrimPrefix(strings.TrimPrefix(v, "0x"), "0X")
+		if len(h)%2 != 0 {
+			return nil, fmt.Errorf("malformed hex %q: odd length", v)
+		}
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return nil, fmt.Errorf("malformed hex %q: %w", v, err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("expected []byte or hex string, got %T", arg)
 	}
-
-	return data, nil
 }
 
+// parseResponse decodes the single output of the 0x-prefixed eth_call result resp and formats it as a string
 func (c *contractClient) parseResponse(resp string, abi abi.ContractABI) (string, error) {
 	// FIXME: For now, we only handle single output parameter
 	if len(abi.Outputs) != 1 {
-		return "", fmt.Errorf("multiple outputs not yet supported")
-	}
-
-	output := abi.Outputs[0]
-	switch output.Type {
-	case "uint256":
-		// Convert hex string to big.Int
-		value := new(big.Int)
-		value.SetString(resp, 16)
-		return value.String(), nil
-	case "string":
-		// String data starts with offset (32 bytes), then length (32 bytes), then the actual string data
-		// Skip first 64 bytes (offset + length) and decode the rest as UTF-8
-		if len(resp) < 128 {
-			return "", fmt.Errorf("invalid string data length")
-		}
-		strLen := new(big.Int)
-		strLen.SetString(resp[64:128], 16)
-		strData := resp[128 : 128+strLen.Int64()*2] // *2 because hex encoding
-		bytes, err := hex.DecodeString(strData)
-		if err != nil {
-			return "", fmt.Errorf("failed to decode string data: %w", err)
-		}
-		return string(bytes), nil
-	case "bool":
-		// Bool is encoded as uint256 where 0 = false, 1 = true
-		if len(resp) < 64 {
-			return "", fmt.Errorf("invalid bool data length")
-		}
-		if resp[63] == '1' {
-			return "true", nil
+		return "", fmt.Errorf("multiple outputs not yet supported, use ReadContractValues")
+	}
+
+	data, err := decodeData(resp)
+	if err != nil {
+		if len(resp) > 2+4*wordSize {
+			resp = resp[:2+4*wordSize] + "..."
 		}
+		return "", &DecodeError{Type: abi.Outputs[0].Type, Data: resp, Err: fmt.Errorf("invalid response data: %w", err)}
+	}
 
-		return "false", nil
-	case "address":
-		// Address is encoded as uint160
-		if len(resp) < 64 {
-			return "", fmt.Errorf("invalid address data length")
+	values, err := c.decoder().decodeValues(abi.Outputs, data)
+	if err != nil {
+		return "", err
+	}
+	return formatValue(checksumAddresses(abi.Outputs[0], values[0])), nil
+}
+
+// flattenTuple presents the fields of a single struct output like multiple outputs would
+func flattenTuple(outputs []abi.ABIParameter, values []interface{}) ([]abi.ABIParameter, []interface{}) {
+	if len(outputs) != 1 || outputs[0].Type != "tuple" {
+		return outputs, values
+	}
+
+	components := outputs[0].Components
+	fields := values[0].(map[string]interface{})
+	flattened := make([]interface{}, len(components))
+	for i, key := range fieldKeys(components) {
+		flattened[i] = fields[key]
+	}
+	return components, flattened
+}
+
+// decoder returns an ABI decoder configured with the client options
+func (c *contractClient) decoder() *decoder {
+	return &decoder{
+		utf8Mode:   c.utf8Mode,
+		strictBool: c.strictBool,
+		maxDepth:   c.maxDepth,
+	}
+}
+
+// NewClient creates a new contract client.
+// Endpoints with a ws:// or wss:// URL are reached over a persistent WebSocket connection instead of HTTP,
+// to which WithTimeout applies but not WithHTTPClient and WithTransport.
+func NewClient(rpcURL string, opts ...Option) ContractClient {
+	c := &contractClient{
+		rpcURL: rpcURL,
+		clock:  realClock{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	httpClient := c.newHTTPClient()
+	urls := append([]string{rpcURL}, c.fallbackURLs...)
+	endpoints := make([]*endpoint, len(urls))
+	for i, url := range urls {
+		endpoints[i] = &endpoint{
+			url:       url,
+			transport: &httpTransport{url: url, client: httpClient},
 		}
-		return "0x" + resp[24:64], nil
-	case "uint8":
-		// uint8 is right-padded to 32 bytes
-		if len(resp) < 64 {
-			return "", fmt.Errorf("invalid uint8 data length")
+		if isWebSocketURL(url) {
+			endpoints[i].transport = newWSTransport(url, c.timeout)
 		}
-		value := new(big.Int)
-		value.SetString(resp, 16)
-		if !value.IsUint64() || value.Uint64() > 255 {
-			return "", fmt.Errorf("uint8 overflow")
+		if c.retryAttempts > 1 {
+			endpoints[i].transport = &retryTransport{
+				next:        endpoints[i].transport,
+				maxAttempts: c.retryAttempts,
+				baseDelay:   c.retryDelay,
+				clock:       c.clock,
+			}
 		}
-		return value.String(), nil
-	default:
-		return "", fmt.Errorf("unsupported output type: %s", output.Type)
+		if c.breakerThreshold > 0 {
+			endpoints[i].breaker = &circuitBreaker{
+				threshold: c.breakerThreshold,
+				cooldown:  c.breakerCooldown,
+				clock:     c.clock,
+				onChange:  c.notifyBreaker(url),
+			}
+		}
+	}
+	c.transport = &failoverTransport{endpoints: endpoints}
+	if c.maxConcurrency > 0 {
+		c.transport = newLimitedTransport(c.maxConcurrency, c.transport)
 	}
+
+	// Names resolve on the client itself unless WithENS points to another endpoint
+	if c.ensEnabled && c.ensURL != "" && c.ensURL != rpcURL {
+		c.ens = NewClient(c.ensURL, WithHTTPClient(c.httpClient), WithTimeout(c.timeout), WithTransport(c.roundTripper)).(*contractClient)
+	}
+
+	return c
 }
 
-// NewClient creates a new contract client
-func NewClient(rpcURL string) ContractClient {
-	return &contractClient{
-		rpcURL: rpcURL,
+// newHTTPClient returns the HTTP client configured by WithHTTPClient, WithTimeout and WithTransport
+func (c *contractClient) newHTTPClient() *http.Client {
+	client := c.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if c.roundTripper == nil && c.timeout <= 0 {
+		return client
+	}
+
+	// The configured client is copied rather than modified, as it may be shared
+	configured := *client
+	if c.roundTripper != nil {
+		configured.Transport = c.roundTripper
+	}
+	if c.timeout > 0 {
+		configured.Timeout = c.timeout
+	}
+	return &configured
+}
+
+// notifyBreaker returns a function reporting breaker transitions of the endpoint at url to the observer
+func (c *contractClient) notifyBreaker(url string) func(BreakerState) {
+	return func(state BreakerState) {
+		if c.observer != nil {
+			c.observer(Notice{Kind: NoticeBreakerStateChange, Endpoint: url, Breaker: state})
+		}
 	}
 }
@@ -2,27 +2,85 @@ package contract
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math/big"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/rootwarp/vinculum/contract/abi"
 	"github.com/stretchr/testify/require"
 )
 
-func TestContract_Read(t *testing.T) {
-	// TODO: Mock test
+func loadFixtureABIs(t *testing.T) abi.ContractABIs {
+	t.Helper()
+
 	d, err := os.ReadFile("./abi/fixtures/resp_get_contract_abi.json")
 	require.NoError(t, err)
 
-	var apiResp abi.APIResponse
-	err = json.Unmarshal(d, &apiResp)
+	contractABIs, err := abi.ParseABI(d)
 	require.NoError(t, err)
 
-	var contractABIs abi.ContractABIs
-	err = json.Unmarshal([]byte(apiResp.Result), &contractABIs)
-	require.NoError(t, err)
+	return contractABIs
+}
+
+// registerEthCallResponder answers eth_call requests with the result registered for their method ID
+// and counts the calls made per method ID.
+// httpmock must be activated by the caller.
+func registerEthCallResponder(t *testing.T, results map[string]string) map[string]int {
+	t.Helper()
+
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			var rpcReq struct {
+				ID     int               `json:"id"`
+				Params []json.RawMessage `json:"params"`
+			}
+			if err := json.Unmarshal(body, &rpcReq); err != nil {
+				return nil, err
+			}
+
+			var callObj struct {
+				Data string `json:"data"`
+			}
+			if err := json.Unmarshal(rpcReq.Params[0], &callObj); err != nil {
+				return nil, err
+			}
+			methodID := callObj.Data[2:10]
+
+			mu.Lock()
+			calls[methodID]++
+			mu.Unlock()
+
+			result, ok := results[methodID]
+			if !ok {
+				return httpmock.NewStringResponse(http.StatusOK,
+					fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"error":{"code":-32000,"message":"execution reverted"}}`, rpcReq.ID)), nil
+			}
+			return httpmock.NewStringResponse(http.StatusOK,
+				fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":"%s"}`, rpcReq.ID, result)), nil
+		})
+
+	return calls
+}
+
+func TestContract_Read(t *testing.T) {
+	// TODO: Mock test
+	contractABIs := loadFixtureABIs(t)
 
 	totalSupply, err := contractABIs.Find("totalSupply")
 	require.NoError(t, err)
@@ -35,3 +93,765 @@ func TestContract_Read(t *testing.T) {
 
 	fmt.Println(ret, err)
 }
+
+func TestContract_ParseStringUTF8(t *testing.T) {
+	stringABI := abi.ContractABI{
+		Name:    "name",
+		Type:    "function",
+		Outputs: []abi.ABIParameter{{Type: "string"}},
+	}
+
+	// "ab" followed by an invalid 0xff byte
+	resp := "0x0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"6162ff0000000000000000000000000000000000000000000000000000000000"
+
+	lenient := &contractClient{}
+	ret, err := lenient.parseResponse(resp, stringABI)
+	require.NoError(t, err)
+	require.Equal(t, "ab\uFFFD", ret)
+
+	strict := &contractClient{utf8Mode: UTF8Strict}
+	_, err = strict.parseResponse(resp, stringABI)
+	require.Error(t, err)
+
+	// Valid UTF-8 passes through unchanged in strict mode
+	valid := "0x0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"f09f988000000000000000000000000000000000000000000000000000000000"
+	ret, err = strict.parseResponse(valid, stringABI)
+	require.NoError(t, err)
+	require.Equal(t, "\U0001F600", ret)
+}
+
+func TestContract_ParseEmptyString(t *testing.T) {
+	// An empty string or bytes is only its offset and a zero length, 64 bytes in all
+	resp := "0x0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000000"
+
+	cli := &contractClient{}
+	for _, typ := range []string{"string", "bytes"} {
+		fn := abi.ContractABI{
+			Name:    "name",
+			Type:    "function",
+			Outputs: []abi.ABIParameter{{Type: typ}},
+		}
+		ret, err := cli.parseResponse(resp, fn)
+		require.NoError(t, err, typ)
+		require.Empty(t, strings.TrimPrefix(ret, "0x"), typ)
+	}
+
+	data, err := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000040" +
+		"0000000000000000000000000000000000000000000000000000000000000060" +
+		"0000000000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(t, err)
+	values, err := (&decoder{}).decodeValues([]abi.ABIParameter{{Type: "string"}, {Type: "bytes"}}, data)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"", []byte{}}, values)
+
+	// A missing length word is still an error
+	_, err = cli.parseResponse("0x0000000000000000000000000000000000000000000000000000000000000020", abi.ContractABI{
+		Outputs: []abi.ABIParameter{{Type: "string"}},
+	})
+	require.Error(t, err)
+}
+
+func TestContract_EncodeBytesInput(t *testing.T) {
+	verifyABI := abi.ContractABI{
+		Name:   "verify",
+		Type:   "function",
+		Inputs: []abi.ABIParameter{{Name: "proof", Type: "bytes"}},
+	}
+
+	cli := &contractClient{}
+	expected := ""
+	for _, proof := range []interface{}{
+		[]byte{0xde, 0xad, 0xbe, 0xef},
+		"0xdeadbeef",
+		"DEADBEEF",
+	} {
+		args := map[string]interface{}{"proof": proof}
+		require.NoError(t, cli.validateInputs(verifyABI, args))
+
+		data, err := cli.encodeData(verifyABI, args)
+		require.NoError(t, err)
+		if expected == "" {
+			expected = data
+		}
+		require.Equal(t, expected, data)
+	}
+
+	methodID, err := verifyABI.MethodID()
+	require.NoError(t, err)
+	require.Equal(t, "0x"+methodID+
+		"0000000000000000000000000000000000000000000000000000000000000020"+
+		"0000000000000000000000000000000000000000000000000000000000000004"+
+		"deadbeef00000000000000000000000000000000000000000000000000000000", expected)
+
+	for _, malformed := range []interface{}{"0xabc", "0xzz", 42} {
+		err := cli.validateInputs(verifyABI, map[string]interface{}{"proof": malformed})
+		require.Error(t, err)
+	}
+}
+
+func TestContract_ReadOutputTypes(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x000000000000000000000000807a96288a1a408dbc13de2b1d087d10356395d2"}`))
+
+	// The ABI wrongly declares a uint256 return
+	ownerABI := abi.ContractABI{
+		Name:    "owner",
+		Type:    "function",
+		Outputs: []abi.ABIParameter{{Type: "uint256"}},
+	}
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	contractAddr := "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270"
+
+	ret, err := cli.ReadContract(ctx, contractAddr, ownerABI, map[string]interface{}{})
+	require.NoError(t, err)
+	require.Equal(t, "733484590217426408439878350902206990279579506130", ret)
+
+	ret, err = cli.ReadContract(ctx, contractAddr, ownerABI, map[string]interface{}{}, WithOutputTypes("address"))
+	require.NoError(t, err)
+	require.Equal(t, "0x807a96288A1A408dBC13DE2b1d087d10356395d2", ret)
+
+	// The caller's ABI is left untouched
+	require.Equal(t, "uint256", ownerABI.Outputs[0].Type)
+}
+
+func TestContract_ReadValuesGetReserves(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	getReserves := abi.ContractABI{
+		Name: "getReserves",
+		Type: "function",
+		Outputs: []abi.ABIParameter{
+			{Name: "_reserve0", Type: "uint112"},
+			{Name: "_reserve1", Type: "uint112"},
+			{Name: "_blockTimestampLast", Type: "uint32"},
+		},
+	}
+	methodID, err := getReserves.MethodID()
+	require.NoError(t, err)
+	require.Equal(t, "0902f1ac", methodID)
+
+	// Each output occupies a full word despite the narrower declared widths
+	results := map[string]string{
+		methodID: "0x" +
+			"0000000000000000000000000000000000000000000001b1ae4d6e2ef5000000" +
+			"0000000000000000000000000000000000000000000000000000746a52880000" +
+			"000000000000000000000000000000000000000000000000000000006553f100",
+	}
+	registerEthCallResponder(t, results)
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	pairAddr := "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"
+
+	values, err := cli.ReadContractValues(ctx, pairAddr, getReserves, map[string]interface{}{})
+	require.NoError(t, err)
+	require.Len(t, values, 3)
+
+	reserve0, _ := new(big.Int).SetString("8000000000000000000000", 10)
+	require.Equal(t, reserve0, values[0])
+	require.Equal(t, big.NewInt(128000000000000), values[1])
+	require.Equal(t, big.NewInt(1700000000), values[2])
+
+	// A reserve wider than 112 bits is rejected
+	results[methodID] = "0x" +
+		"0000000000000000000000000000000000010000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000746a52880000" +
+		"000000000000000000000000000000000000000000000000000000006553f100"
+	_, err = cli.ReadContractValues(ctx, pairAddr, getReserves, map[string]interface{}{})
+	require.ErrorContains(t, err, "uint112")
+
+	// So is a timestamp wider than 32 bits
+	results[methodID] = "0x" +
+		"0000000000000000000000000000000000000000000001b1ae4d6e2ef5000000" +
+		"0000000000000000000000000000000000000000000000000000746a52880000" +
+		"0000000000000000000000000000000000000000000000000000000106553f10"
+	_, err = cli.ReadContractValues(ctx, pairAddr, getReserves, map[string]interface{}{})
+	require.ErrorContains(t, err, "uint32")
+
+	// Truncated returndata
+	results[methodID] = "0x" +
+		"0000000000000000000000000000000000000000000001b1ae4d6e2ef5000000" +
+		"0000000000000000000000000000000000000000000000000000746a52880000"
+	_, err = cli.ReadContractValues(ctx, pairAddr, getReserves, map[string]interface{}{})
+	require.Error(t, err)
+}
+
+func TestContract_ReadMap(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	positionABI := abi.ContractABI{
+		Name: "position",
+		Type: "function",
+		Outputs: []abi.ABIParameter{
+			{Name: "owner", Type: "address"},
+			{Name: "amount", Type: "uint256"},
+			{Type: "bool"},
+			{Name: "amount", Type: "uint256"},
+		},
+	}
+	methodID, err := positionABI.MethodID()
+	require.NoError(t, err)
+
+	ret, err := EncodeReturn(positionABI.Outputs, []interface{}{
+		"0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		big.NewInt(10),
+		true,
+		big.NewInt(20),
+	})
+	require.NoError(t, err)
+	registerEthCallResponder(t, map[string]string{methodID: ret})
+
+	cli := NewClient(testRPCURL)
+	result, err := cli.ReadContractMap(context.Background(), testTokenAddr, positionABI, map[string]interface{}{})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"owner":    "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		"amount":   big.NewInt(10),
+		"2":        true,
+		"amount_3": big.NewInt(20),
+	}, result)
+}
+
+func TestContract_ReadTimestamps(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	lockABI := abi.ContractABI{
+		Name: "lock",
+		Type: "function",
+		Outputs: []abi.ABIParameter{
+			{Name: "owner", Type: "address"},
+			{Name: "lockedUntil", Type: "uint256"},
+			{Type: "uint64"},
+			{Name: "amount", Type: "uint256"},
+		},
+	}
+	methodID, err := lockABI.MethodID()
+	require.NoError(t, err)
+
+	ret, err := EncodeReturn(lockABI.Outputs, []interface{}{
+		"0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		big.NewInt(1735689600),
+		big.NewInt(1704067200),
+		new(big.Int).Lsh(big.NewInt(1), 100),
+	})
+	require.NoError(t, err)
+	registerEthCallResponder(t, map[string]string{methodID: ret})
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	args := map[string]interface{}{}
+
+	result, err := cli.ReadContractMap(ctx, testTokenAddr, lockABI, args, WithTimestampOutputs("lockedUntil", "2"))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), result["lockedUntil"])
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), result["2"])
+	require.Equal(t, new(big.Int).Lsh(big.NewInt(1), 100), result["amount"])
+
+	// Numeric by default
+	values, err := cli.ReadContractValues(ctx, testTokenAddr, lockABI, args)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1735689600), values[1])
+
+	_, err = cli.ReadContractValues(ctx, testTokenAddr, lockABI, args, WithTimestampOutputs("amount"))
+	require.ErrorContains(t, err, "out of range")
+
+	_, err = cli.ReadContractValues(ctx, testTokenAddr, lockABI, args, WithTimestampOutputs("owner"))
+	require.ErrorContains(t, err, "expected an integer")
+
+	_, err = cli.ReadContractValues(ctx, testTokenAddr, lockABI, args, WithTimestampOutputs("unlockAt"))
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestContract_ReadTupleOutputs(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	components := []abi.ABIParameter{
+		{Name: "owner", Type: "address"},
+		{Name: "label", Type: "string"},
+		{Name: "amount", Type: "uint256"},
+	}
+	// getPosition() returns (Position memory), a single tuple output
+	structABI := abi.ContractABI{
+		Name:    "getPosition",
+		Type:    "function",
+		Outputs: []abi.ABIParameter{{Name: "", Type: "tuple", Components: components}},
+	}
+	// position() returns (address owner, string label, uint256 amount)
+	multiABI := abi.ContractABI{
+		Name:    "position",
+		Type:    "function",
+		Outputs: components,
+	}
+
+	fields := []interface{}{"0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214", "vault", big.NewInt(42)}
+	structRet, err := EncodeReturn(structABI.Outputs, []interface{}{fields})
+	require.NoError(t, err)
+	multiRet, err := EncodeReturn(multiABI.Outputs, fields)
+	require.NoError(t, err)
+	// A struct with a dynamic field is encoded behind an offset, unlike the equivalent outputs
+	require.NotEqual(t, structRet, multiRet)
+
+	structID, err := structABI.MethodID()
+	require.NoError(t, err)
+	multiID, err := multiABI.MethodID()
+	require.NoError(t, err)
+	registerEthCallResponder(t, map[string]string{structID: structRet, multiID: multiRet})
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	args := map[string]interface{}{}
+
+	for _, fn := range []abi.ContractABI{structABI, multiABI} {
+		values, err := cli.ReadContractValues(ctx, testTokenAddr, fn, args)
+		require.NoError(t, err, fn.Name)
+		require.Equal(t, fields, values, fn.Name)
+
+		result, err := cli.ReadContractMap(ctx, testTokenAddr, fn, args)
+		require.NoError(t, err, fn.Name)
+		require.Equal(t, map[string]interface{}{
+			"owner":  "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+			"label":  "vault",
+			"amount": big.NewInt(42),
+		}, result, fn.Name)
+	}
+
+	// A tuple next to other outputs decodes as a nested map
+	nested := []abi.ABIParameter{
+		{Name: "position", Type: "tuple", Components: components},
+		{Name: "active", Type: "bool"},
+	}
+	ret, err := EncodeReturn(nested, []interface{}{fields, true})
+	require.NoError(t, err)
+	data, err := hex.DecodeString(strings.TrimPrefix(ret, "0x"))
+	require.NoError(t, err)
+
+	values, err := (&decoder{}).decodeValues(nested, data)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"owner":  "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+			"label":  "vault",
+			"amount": big.NewInt(42),
+		},
+		true,
+	}, values)
+}
+
+func TestContract_ReadStaticTuple(t *testing.T) {
+	cli := &contractClient{}
+
+	// getAccount() returns (Account memory) with two uint256 fields, inlined without an offset
+	account := abi.ABIParameter{
+		Type: "tuple",
+		Components: []abi.ABIParameter{
+			{Name: "collateral", Type: "uint256"},
+			{Name: "debt", Type: "uint256"},
+		},
+	}
+	getAccount := abi.ContractABI{Name: "getAccount", Type: "function", Outputs: []abi.ABIParameter{account}}
+	resp := "0x" +
+		"00000000000000000000000000000000000000000000000000000000000003e8" +
+		"00000000000000000000000000000000000000000000000000000000000001f4"
+
+	ret, err := cli.parseResponse(resp, getAccount)
+	require.NoError(t, err)
+	require.Equal(t, "map[collateral:1000 debt:500]", ret)
+
+	// Followed by another output, the tuple is decoded as a nested map
+	withHealth := abi.ContractABI{
+		Name:    "getAccountHealth",
+		Type:    "function",
+		Outputs: []abi.ABIParameter{account, {Name: "healthy", Type: "bool"}},
+	}
+	data, err := hex.DecodeString(resp[2:] + "0000000000000000000000000000000000000000000000000000000000000001")
+	require.NoError(t, err)
+	values, err := cli.decoder().decodeValues(withHealth.Outputs, data)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"collateral": big.NewInt(1000), "debt": big.NewInt(500)},
+		true,
+	}, values)
+
+	// Tuple inputs expand to their components in the signature
+	setAccount := abi.ContractABI{Name: "setAccount", Type: "function", Inputs: []abi.ABIParameter{account}}
+	methodID, err := setAccount.MethodID()
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(abi.Keccak256([]byte("setAccount((uint256,uint256))"))[:4]), methodID)
+}
+
+func TestContract_ReadDescribed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	reserve := abi.ABIParameter{
+		Name: "reserve",
+		Type: "tuple",
+		Components: []abi.ABIParameter{
+			{Name: "token", Type: "address"},
+			{Name: "amounts", Type: "uint256[]"},
+		},
+	}
+	reservesABI := abi.ContractABI{
+		Name:    "reserves",
+		Type:    "function",
+		Outputs: []abi.ABIParameter{{Name: "", Type: "tuple[]", Components: []abi.ABIParameter{reserve}}},
+	}
+	totalSupplyABI := erc20TotalSupplyABI
+
+	reserves := []interface{}{
+		[]interface{}{map[string]interface{}{
+			"token":   "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+			"amounts": []interface{}{big.NewInt(1), big.NewInt(2)},
+		}},
+	}
+	ret, err := EncodeReturn(reservesABI.Outputs, []interface{}{reserves})
+	require.NoError(t, err)
+	reservesID, err := reservesABI.MethodID()
+	require.NoError(t, err)
+	registerEthCallResponder(t, map[string]string{
+		reservesID: ret,
+		"18160ddd": "0x00000000000000000000000000000000000000000000000000000000000003e8",
+	})
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	args := map[string]interface{}{}
+
+	value, schema, err := cli.ReadContractDescribed(ctx, testTokenAddr, reservesABI, args)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"reserve": map[string]interface{}{
+				"token":   "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+				"amounts": []interface{}{big.NewInt(1), big.NewInt(2)},
+			},
+		},
+	}, value)
+	require.Equal(t, reservesABI.Outputs, schema)
+
+	// The schema is a copy of the ABI outputs
+	schema[0].Components[0].Components[0].Name = "asset"
+	require.Equal(t, "token", reserve.Components[0].Name)
+
+	value, schema, err = cli.ReadContractDescribed(ctx, testTokenAddr, totalSupplyABI, args)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1000), value)
+	require.Equal(t, []abi.ABIParameter{{Type: "uint256"}}, schema)
+}
+
+func TestContract_ParseBool(t *testing.T) {
+	boolABI := abi.ContractABI{
+		Name:    "paused",
+		Type:    "function",
+		Outputs: []abi.ABIParameter{{Type: "bool"}},
+	}
+
+	tests := []struct {
+		word    string
+		lenient string
+		strict  bool // whether strict mode accepts the word
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000000", "false", true},
+		{"0000000000000000000000000000000000000000000000000000000000000001", "true", true},
+		// Non-canonical: the last character alone would read as false
+		{"000000000000000000000000000000000000000000000000000000000000000f", "true", false},
+		{"0100000000000000000000000000000000000000000000000000000000000000", "true", false},
+	}
+
+	lenient := &contractClient{}
+	strict := &contractClient{strictBool: true}
+	for _, tc := range tests {
+		ret, err := lenient.parseResponse("0x"+tc.word, boolABI)
+		require.NoError(t, err, tc.word)
+		require.Equal(t, tc.lenient, ret, tc.word)
+
+		ret, err = strict.parseResponse("0x"+tc.word, boolABI)
+		if tc.strict {
+			require.NoError(t, err, tc.word)
+			require.Equal(t, tc.lenient, ret, tc.word)
+		} else {
+			require.ErrorContains(t, err, "invalid bool value", tc.word)
+		}
+	}
+}
+
+func TestContract_ReadEmptyArray(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// getHolders() returns (address[] holders, uint256 total)
+	holdersABI := abi.ContractABI{
+		Name: "getHolders",
+		Type: "function",
+		Outputs: []abi.ABIParameter{
+			{Name: "holders", Type: "address[]"},
+			{Name: "total", Type: "uint256"},
+		},
+	}
+	ret, err := EncodeReturn(holdersABI.Outputs, []interface{}{[]interface{}{}, big.NewInt(0)})
+	require.NoError(t, err)
+	methodID, err := holdersABI.MethodID()
+	require.NoError(t, err)
+	registerEthCallResponder(t, map[string]string{methodID: ret})
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	args := map[string]interface{}{}
+
+	// An empty array is an empty slice by default
+	values, err := cli.ReadContractValues(ctx, testTokenAddr, holdersABI, args)
+	require.NoError(t, err)
+	require.NotNil(t, values[0])
+	require.Empty(t, values[0])
+
+	_, err = cli.ReadContractValues(ctx, testTokenAddr, holdersABI, args, WithErrorOnEmpty())
+	require.ErrorIs(t, err, ErrEmptyResult)
+	require.ErrorContains(t, err, "holders")
+
+	_, err = cli.ReadContractMap(ctx, testTokenAddr, holdersABI, args, WithErrorOnEmpty())
+	require.ErrorIs(t, err, ErrEmptyResult)
+}
+
+func TestContract_ReadJSONNumbers(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// totals() returns (uint256 supply, int256 delta, uint256[] shares)
+	totalsABI := abi.ContractABI{
+		Name: "totals",
+		Type: "function",
+		Outputs: []abi.ABIParameter{
+			{Name: "supply", Type: "uint256"},
+			{Name: "delta", Type: "int256"},
+			{Name: "shares", Type: "uint256[]"},
+		},
+	}
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	ret, err := EncodeReturn(totalsABI.Outputs, []interface{}{
+		maxUint256,
+		big.NewInt(-42),
+		[]interface{}{big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 60)},
+	})
+	require.NoError(t, err)
+	methodID, err := totalsABI.MethodID()
+	require.NoError(t, err)
+	registerEthCallResponder(t, map[string]string{methodID: ret})
+
+	cli := NewClient(testRPCURL)
+	result, err := cli.ReadContractMap(context.Background(), testTokenAddr, totalsABI, map[string]interface{}{}, WithJSONNumbers())
+	require.NoError(t, err)
+	require.Equal(t, json.Number(maxUint256.String()), result["supply"])
+
+	// Every digit of the 256-bit value is kept, as a JSON number rather than a string
+	out, err := json.Marshal(result)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"supply": 115792089237316195423570985008687907853269984665640564039457584007913129639935,
+		"delta": -42,
+		"shares": [1, 1152921504606846976]
+	}`, string(out))
+	require.Contains(t, string(out), `"supply":115792089237316195423570985008687907853269984665640564039457584007913129639935`)
+
+	// Integers are *big.Int by default
+	values, err := cli.ReadContractValues(context.Background(), testTokenAddr, totalsABI, map[string]interface{}{})
+	require.NoError(t, err)
+	require.Equal(t, maxUint256, values[0])
+}
+
+func TestContract_ReadValidator(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerEthCallResponder(t, erc20Results)
+
+	balanceOf, err := loadFixtureABIs(t).Find("balanceOf")
+	require.NoError(t, err)
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	args := map[string]interface{}{"": testTokenAddr}
+
+	// A balance can't exceed the total supply
+	maxBalance := func(limit *big.Int) Validator {
+		return func(values []interface{}) error {
+			if values[0].(*big.Int).Cmp(limit) > 0 {
+				return fmt.Errorf("balance %s exceeds supply %s", values[0], limit)
+			}
+			return nil
+		}
+	}
+
+	values, err := cli.ReadContractValues(ctx, testTokenAddr, *balanceOf, args, WithValidator(maxBalance(big.NewInt(2000000))))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1500000), values[0])
+
+	_, err = cli.ReadContractMap(ctx, testTokenAddr, *balanceOf, args, WithValidator(maxBalance(big.NewInt(1000000))))
+	require.ErrorIs(t, err, ErrValidationFailed)
+	require.ErrorContains(t, err, "balance 1500000 exceeds supply 1000000")
+
+	// Validators see the outputs as the other options converted them
+	var seen interface{}
+	_, err = cli.ReadContractValues(ctx, testTokenAddr, *balanceOf, args, WithJSONNumbers(), WithValidator(func(values []interface{}) error {
+		seen = values[0]
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, json.Number("1500000"), seen)
+}
+
+func TestContract_ParseSignedInt(t *testing.T) {
+	cli := &contractClient{}
+	parse := func(typ, resp string) (string, error) {
+		return cli.parseResponse(resp, abi.ContractABI{
+			Name:    "latestAnswer",
+			Type:    "function",
+			Outputs: []abi.ABIParameter{{Type: typ}},
+		})
+	}
+
+	tests := []struct {
+		typ      string
+		resp     string
+		expected string
+	}{
+		{"int256", "0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff6", "-10"},
+		{"int256", "0x000000000000000000000000000000000000000000000000000000746a528800", "500000000000"},
+		{"int256", "0x8000000000000000000000000000000000000000000000000000000000000000",
+			"-57896044618658097711785492504343953926634992332820282019728792003956564819968"},
+		{"int128", "0xffffffffffffffffffffffffffffffff80000000000000000000000000000000",
+			"-170141183460469231731687303715884105728"},
+		{"int8", "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff80", "-128"},
+		{"int8", "0x000000000000000000000000000000000000000000000000000000000000007f", "127"},
+		{"int", "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", "-1"},
+	}
+	for _, test := range tests {
+		ret, err := parse(test.typ, test.resp)
+		require.NoError(t, err, test.typ)
+		require.Equal(t, test.expected, ret, test.typ)
+	}
+
+	// Values must be sign extended to the whole word within the declared width
+	_, err := parse("int8", "0x0000000000000000000000000000000000000000000000000000000000000080")
+	require.ErrorContains(t, err, "overflows int8")
+	_, err = parse("int8", "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	require.ErrorContains(t, err, "overflows int8")
+}
+
+func TestContract_ReadAtBlock(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var blocks []string
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			var rpcReq rpcRequest
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			blocks = append(blocks, rpcReq.Params[1].(string))
+			return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"`+erc20Results["70a08231"]+`"}`), nil
+		})
+
+	balanceOf, err := loadFixtureABIs(t).Find("balanceOf")
+	require.NoError(t, err)
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	args := map[string]interface{}{"": testTokenAddr}
+
+	for _, opts := range [][]CallOption{
+		nil,
+		{WithBlockNumber(big.NewInt(4000000))},
+		{WithBlockTag("finalized")},
+		{WithBlockTag("safe"), WithBlockNumber(big.NewInt(0))},
+		{WithBlockNumber(big.NewInt(1)), WithBlockTag("pending")},
+	} {
+		balance, err := cli.ReadContract(ctx, testTokenAddr, *balanceOf, args, opts...)
+		require.NoError(t, err)
+		require.Equal(t, "1500000", balance)
+	}
+	require.Equal(t, []string{"latest", "0x3d0900", "finalized", "0x0", "pending"}, blocks)
+
+	// Malformed blocks are rejected before calling the node
+	for _, opt := range []CallOption{
+		WithBlockTag("Latest"),
+		WithBlockTag("0x10"),
+		WithBlockTag("finalised"),
+		WithBlockNumber(big.NewInt(-1)),
+	} {
+		_, err := cli.ReadContractValues(ctx, testTokenAddr, *balanceOf, args, opt)
+		require.Error(t, err)
+	}
+	require.Len(t, blocks, 5)
+}
+
+func TestContract_BytesRoundTrip(t *testing.T) {
+	cli := &contractClient{}
+
+	// getRoleAdmin(bytes32 role) returns (bytes32), the role being keccak256("MINTER_ROLE")
+	role := "0x9f2df0fed2c77648de5860a4cc508cd0818c85b8b8a1ab4ceeef8d981c8956a6"
+	getRoleAdmin := abi.ContractABI{
+		Name:    "getRoleAdmin",
+		Type:    "function",
+		Inputs:  []abi.ABIParameter{{Name: "role", Type: "bytes32"}},
+		Outputs: []abi.ABIParameter{{Type: "bytes32"}},
+	}
+	roleBytes, err := hex.DecodeString(role[2:])
+	require.NoError(t, err)
+	args := map[string]interface{}{"role": roleBytes}
+	require.NoError(t, cli.validateInputs(getRoleAdmin, args))
+	data, err := cli.encodeData(getRoleAdmin, args)
+	require.NoError(t, err)
+	require.Equal(t, "0x248a9ca3"+role[2:], data)
+
+	// The encoded word decodes back to the same value
+	ret, err := cli.parseResponse("0x"+data[10:], getRoleAdmin)
+	require.NoError(t, err)
+	require.Equal(t, role, ret)
+
+	// Shorter values are left aligned
+	args = map[string]interface{}{"role": []byte{0xab, 0xcd}}
+	data, err = cli.encodeData(getRoleAdmin, args)
+	require.NoError(t, err)
+	require.Equal(t, "0x248a9ca3abcd"+strings.Repeat("0", 60), data)
+
+	// A dynamic bytes of 40 bytes takes its offset, its length and two words of padded data
+	payload := make([]byte, 40)
+	for i := range payload {
+		payload[i] = byte(i + 1)
+	}
+	execute := abi.ContractABI{
+		Name:    "execute",
+		Type:    "function",
+		Inputs:  []abi.ABIParameter{{Name: "payload", Type: "bytes"}},
+		Outputs: []abi.ABIParameter{{Type: "bytes"}},
+	}
+	args = map[string]interface{}{"payload": payload}
+	require.NoError(t, cli.validateInputs(execute, args))
+	data, err = cli.encodeData(execute, args)
+	require.NoError(t, err)
+	methodID, err := execute.MethodID()
+	require.NoError(t, err)
+	require.Equal(t, "0x"+methodID+
+		"0000000000000000000000000000000000000000000000000000000000000020"+
+		"0000000000000000000000000000000000000000000000000000000000000028"+
+		hex.EncodeToString(payload)+strings.Repeat("0", 48), data)
+
+	ret, err = cli.parseResponse("0x"+data[10:], execute)
+	require.NoError(t, err)
+	require.Equal(t, "0x"+hex.EncodeToString(payload), ret)
+}
@@ -0,0 +1,24 @@
+package contract
+
+import "time"
+
+// Clock tells the time and waits for durations to elapse.
+// Time-dependent features of the client, like circuit breaker cooldowns and receipt polling, go through it,
+// so tests can control time instead of sleeping.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After returns a channel receiving the current time once d has elapsed
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock of the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
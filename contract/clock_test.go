@@ -0,0 +1,150 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a Clock whose time only moves when advanced, firing the waits it has elapsed
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{deadline: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d and fires the waits which have elapsed
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// WaitForWaiters blocks until n waits are pending, so a goroutine has reached its wait before time is advanced
+func (c *fakeClock) WaitForWaiters(t *testing.T, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.waiters) >= n
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestClock_BreakerCooldown(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL, httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+
+	clock := newFakeClock()
+	cli := NewClient(testRPCURL, WithCircuitBreaker(1, time.Minute), WithClock(clock))
+	ctx := context.Background()
+
+	_, err := cli.ChainID(ctx)
+	require.Error(t, err)
+	_, err = cli.ChainID(ctx)
+	require.ErrorIs(t, err, ErrNoEndpointAvailable)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+
+	clock.Advance(59 * time.Second)
+	_, err = cli.ChainID(ctx)
+	require.ErrorIs(t, err, ErrNoEndpointAvailable)
+
+	// The cooldown elapses without any real time passing
+	clock.Advance(time.Second)
+	_, err = cli.ChainID(ctx)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNoEndpointAvailable)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestClock_WaitForReceipt(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var mu sync.Mutex
+	polls := 0
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			var rpcReq rpcRequest
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+
+			mu.Lock()
+			defer mu.Unlock()
+			polls++
+			if polls < 3 {
+				return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":null}`), nil
+			}
+			return httpmock.NewStringResponse(http.StatusOK, receiptJSON(t)), nil
+		})
+
+	clock := newFakeClock()
+	cli := NewClient(testRPCURL, WithClock(clock))
+
+	done := make(chan *Receipt)
+	go func() {
+		receipt, err := cli.WaitForReceipt(context.Background(), testTxHash, time.Hour)
+		assert.NoError(t, err)
+		done <- receipt
+	}()
+
+	// Each hour-long poll interval passes instantly
+	for i := 0; i < 2; i++ {
+		clock.WaitForWaiters(t, 1)
+		clock.Advance(time.Hour)
+	}
+
+	select {
+	case receipt := <-done:
+		require.NotNil(t, receipt)
+		assert.Equal(t, testTxHash, receipt.TransactionHash)
+	case <-time.After(5 * time.Second):
+		t.Fatal("receipt not returned")
+	}
+	assert.Equal(t, 3, polls)
+}
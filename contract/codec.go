@@ -0,0 +1,74 @@
+package contract
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TypeCodec encodes and decodes values of a custom type, e.g. a protocol-specific fixed-point number.
+// Custom types are static: every value is encoded in place in a single word.
+type TypeCodec interface {
+	// Encode returns the 32-byte word encoding value
+	Encode(value interface{}) ([]byte, error)
+	// Decode returns the value encoded in word
+	Decode(word []byte) (interface{}, error)
+}
+
+var (
+	typeCodecsMu sync.RWMutex
+	typeCodecs   = make(map[string]TypeCodec)
+)
+
+// RegisterType makes every client encode and decode inputs and outputs of type name with codec.
+// Built-in types, including arrays and tuples, can't be overridden, and registering one is an error.
+// Registering a name again replaces its codec.
+func RegisterType(name string, codec TypeCodec) error {
+	if isBuiltinType(name) {
+		return fmt.Errorf("cannot register built-in type %s", name)
+	}
+	if codec == nil {
+		return fmt.Errorf("nil codec for type %s", name)
+	}
+
+	typeCodecsMu.Lock()
+	defer typeCodecsMu.Unlock()
+	typeCodecs[name] = codec
+	return nil
+}
+
+// lookupType returns the codec registered for typ, if any
+func lookupType(typ string) (TypeCodec, bool) {
+	typeCodecsMu.RLock()
+	defer typeCodecsMu.RUnlock()
+	codec, ok := typeCodecs[typ]
+	return codec, ok
+}
+
+// isBuiltinType reports whether typ is handled by the encoder and decoder themselves
+func isBuiltinType(typ string) bool {
+	typ = canonicalType(typ)
+	switch typ {
+	case "", "address", "bool", "string", "bytes", "tuple":
+		return true
+	}
+	if _, _, ok := parseArrayType(typ); ok {
+		return true
+	}
+	if _, _, ok := parseIntType(typ); ok {
+		return true
+	}
+	_, ok := parseFixedBytesType(typ)
+	return ok
+}
+
+// encodeCustom encodes value with the codec registered for typ
+func encodeCustom(codec TypeCodec, typ string, value interface{}) ([]byte, error) {
+	word, err := codec.Encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", typ, err)
+	}
+	if len(word) != wordSize {
+		return nil, fmt.Errorf("codec of %s encoded %d bytes instead of a word", typ, len(word))
+	}
+	return word, nil
+}
@@ -0,0 +1,78 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedPointCodec handles an unsigned fixed-point number with 18 decimals, given and returned as a decimal string
+type fixedPointCodec struct{}
+
+func (fixedPointCodec) Encode(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected decimal string, got %T", value)
+	}
+	raw, err := ParseUnits(s, 18)
+	if err != nil {
+		return nil, err
+	}
+	return encodeInt(raw, false, 256)
+}
+
+func (fixedPointCodec) Decode(word []byte) (interface{}, error) {
+	return FormatUnits(new(big.Int).SetBytes(word), 18), nil
+}
+
+func TestCodec_RegisterType(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	const fixedType = "ufixed256x18"
+	require.NoError(t, RegisterType(fixedType, fixedPointCodec{}))
+	t.Cleanup(func() {
+		typeCodecsMu.Lock()
+		delete(typeCodecs, fixedType)
+		typeCodecsMu.Unlock()
+	})
+
+	// scale(ufixed256x18 factor) returns (ufixed256x18)
+	scale := abi.ContractABI{
+		Name:    "scale",
+		Type:    "function",
+		Inputs:  []abi.ABIParameter{{Name: "factor", Type: fixedType}},
+		Outputs: []abi.ABIParameter{{Type: fixedType}},
+	}
+	methodID, err := scale.MethodID()
+	require.NoError(t, err)
+	ret, err := EncodeReturn(scale.Outputs, []interface{}{"2.5"})
+	require.NoError(t, err)
+	registerEthCallResponder(t, map[string]string{methodID: ret})
+
+	cli := &contractClient{}
+	args := map[string]interface{}{"factor": "1.5"}
+	require.NoError(t, cli.validateInputs(scale, args))
+	data, err := cli.encodeData(scale, args)
+	require.NoError(t, err)
+	assert.Equal(t, "0x"+methodID+"00000000000000000000000000000000000000000000000014d1120d7b160000", data)
+
+	result, err := NewClient(testRPCURL).ReadContract(context.Background(), testTokenAddr, scale, args)
+	require.NoError(t, err)
+	assert.Equal(t, "2.5", result)
+
+	// Codec errors are reported like built-in type errors
+	err = cli.validateInputs(scale, map[string]interface{}{"factor": 1.5})
+	assert.ErrorContains(t, err, "expected decimal string")
+
+	// Built-in types take precedence
+	for _, builtin := range []string{"uint256", "address", "bytes32", "string", "tuple", "int8[]", "address payable"} {
+		assert.Error(t, RegisterType(builtin, fixedPointCodec{}), builtin)
+	}
+}
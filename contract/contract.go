@@ -0,0 +1,132 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// Contract bundles the ABI of a contract with a client to interact with it at a given address
+type Contract struct {
+	address string
+	client  ContractClient
+	entries map[string]abi.ContractABIs
+	errors  []abi.ContractABI
+}
+
+// NewContract creates a Contract for addr described by abis.
+// ABI entries are indexed by name once, keeping every overload of a name to pick from when it is used.
+func NewContract(addr string, abis abi.ContractABIs, client ContractClient) *Contract {
+	entries := make(map[string]abi.ContractABIs, len(abis))
+	var errs []abi.ContractABI
+	for _, entry := range abis {
+		if entry.Type == "error" {
+			errs = append(errs, entry)
+		}
+		if entry.Name != "" {
+			entries[entry.Name] = append(entries[entry.Name], entry)
+		}
+	}
+	return &Contract{
+		address: addr,
+		client:  client,
+		entries: entries,
+		errors:  errs,
+	}
+}
+
+// Address returns the address of the contract
+func (c *Contract) Address() string {
+	return c.address
+}
+
+// Read calls the function funcName with args given in the order of its inputs and returns its decoded outputs.
+// An overloaded function is picked by the number of args, or by its signature given as funcName,
+// e.g. "safeTransferFrom(address,address,uint256)". Overloads it can't tell apart fail with abi.ErrAmbiguousName.
+// Reverts with custom errors declared in the ABI return a *CustomError.
+func (c *Contract) Read(ctx context.Context, funcName string, args ...interface{}) ([]interface{}, error) {
+	function, err := c.entry(funcName, "function", len(args))
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != len(function.Inputs) {
+		return nil, fmt.Errorf("%s expects %d arguments, got %d", funcName, len(function.Inputs), len(args))
+	}
+
+	// Key inputs so unnamed and duplicate names stay addressable, the selector only depends on types
+	keys := fieldKeys(function.Inputs)
+	inputs := make([]abi.ABIParameter, len(function.Inputs))
+	named := make(map[string]interface{}, len(args))
+	for i, input := range function.Inputs {
+		input.Name = keys[i]
+		inputs[i] = input
+		named[keys[i]] = args[i]
+	}
+	function.Inputs = inputs
+
+	return c.client.ReadContractValues(ctx, c.address, function, named, WithErrorABIs(c.errors...))
+}
+
+// Logs fetches and decodes the logs of eventName emitted between fromBlock and toBlock inclusive.
+// A nil block means the latest block. No matching logs is an empty slice, or ErrEmptyResult with WithErrorOnEmpty.
+// Logs which fail to decode keep their error in DecodedLog.Err.
+func (c *Contract) Logs(ctx context.Context, eventName string, fromBlock, toBlock *big.Int, opts ...CallOption) ([]DecodedLog, error) {
+	event, err := c.entry(eventName, "event", -1)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := c.client.GetLogs(ctx, c.address, event, fromBlock, toBlock, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeLogs(logs, []abi.ContractABI{event})
+}
+
+// entry returns the ABI entry of the given type designated by name, or by signature when name has parentheses.
+// Overloads of a name are narrowed down to those with inputs inputs unless it is negative.
+func (c *Contract) entry(name, typ string, inputs int) (abi.ContractABI, error) {
+	baseName, _, bySignature := strings.Cut(name, "(")
+	entries, ok := c.entries[strings.TrimSpace(baseName)]
+	if !ok {
+		return abi.ContractABI{}, fmt.Errorf("%s not found in contract ABI", name)
+	}
+
+	var candidates abi.ContractABIs
+	for _, entry := range entries {
+		if entry.Type == typ {
+			candidates = append(candidates, entry)
+		}
+	}
+	if len(candidates) == 0 {
+		return abi.ContractABI{}, fmt.Errorf("%s is a %s, not a %s", name, entries[0].Type, typ)
+	}
+
+	if bySignature {
+		entry, err := candidates.FindBySignature(name)
+		if err != nil {
+			return abi.ContractABI{}, err
+		}
+		return *entry, nil
+	}
+
+	if len(candidates) > 1 && inputs >= 0 {
+		var matching abi.ContractABIs
+		for _, entry := range candidates {
+			if len(entry.Inputs) == inputs {
+				matching = append(matching, entry)
+			}
+		}
+		if len(matching) == 0 {
+			return abi.ContractABI{}, fmt.Errorf("%s has no overload taking %d arguments", name, inputs)
+		}
+		candidates = matching
+	}
+	if len(candidates) > 1 {
+		return abi.ContractABI{}, fmt.Errorf("%w: %s has %d overloads, designate one by signature", abi.ErrAmbiguousName, name, len(candidates))
+	}
+	return candidates[0], nil
+}
@@ -0,0 +1,140 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWMATICAddr = "0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270"
+
+func TestContract_FacadeRead(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := registerEthCallResponder(t, map[string]string{
+		"70a08231": "0x0000000000000000000000000000000000000000000000000de0b6b3a7640000",
+		"dd62ed3e": "0x0000000000000000000000000000000000000000000000000000000000000064",
+	})
+
+	c := NewContract(testWMATICAddr, loadFixtureABIs(t), NewClient(testRPCURL))
+	ctx := context.Background()
+
+	balance, err := c.Read(ctx, "balanceOf", testHolderAddr)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(1000000000000000000)}, balance)
+
+	// Both inputs of allowance are unnamed
+	allowance, err := c.Read(ctx, "allowance", testHolderAddr, testTokenAddr)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(100)}, allowance)
+	assert.Equal(t, 1, calls["dd62ed3e"])
+
+	_, err = c.Read(ctx, "balanceOf")
+	require.ErrorContains(t, err, "expects 1 arguments")
+
+	_, err = c.Read(ctx, "Transfer")
+	require.ErrorContains(t, err, "not a function")
+
+	_, err = c.Read(ctx, "mint")
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestContract_FacadeLogs(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var filter map[string]interface{}
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			var rpcReq struct {
+				Method string                   `json:"method"`
+				Params []map[string]interface{} `json:"params"`
+			}
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			require.Equal(t, "eth_getLogs", rpcReq.Method)
+			filter = rpcReq.Params[0]
+
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"result":  []RawLog{transferLog},
+			})
+		})
+
+	c := NewContract(testWMATICAddr, loadFixtureABIs(t), NewClient(testRPCURL))
+
+	logs, err := c.Logs(context.Background(), "Transfer", big.NewInt(4000000), nil)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "Transfer", logs[0].Event)
+	assert.Equal(t, "0x807a96288a1a408dbc13de2b1d087d10356395d2", logs[0].Fields["dst"])
+
+	assert.Equal(t, testWMATICAddr, filter["address"])
+	assert.Equal(t, "0x3d0900", filter["fromBlock"])
+	assert.Equal(t, "latest", filter["toBlock"])
+	assert.Equal(t, []interface{}{transferLog.Topics[0]}, filter["topics"])
+}
+
+func TestContract_FacadeOverloads(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	abis, err := abi.ParseABI([]byte(`[
+		{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"price","stateMutability":"view","inputs":[{"name":"token","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"price","stateMutability":"view","inputs":[{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]}
+	]`))
+	require.NoError(t, err)
+
+	selector := func(sig string) string {
+		fn, err := abis.FindBySignature(sig)
+		require.NoError(t, err)
+		methodID, err := fn.MethodID()
+		require.NoError(t, err)
+		return methodID
+	}
+	calls := registerEthCallResponder(t, map[string]string{
+		selector("balanceOf(address)"):         "0x0000000000000000000000000000000000000000000000000000000000000001",
+		selector("balanceOf(address,uint256)"): "0x0000000000000000000000000000000000000000000000000000000000000002",
+		selector("price(address)"):             "0x0000000000000000000000000000000000000000000000000000000000000003",
+		selector("price(uint256)"):             "0x0000000000000000000000000000000000000000000000000000000000000004",
+	})
+
+	c := NewContract(testWMATICAddr, abis, NewClient(testRPCURL))
+	ctx := context.Background()
+
+	// Overloads taking different numbers of arguments are picked by the arguments given
+	balance, err := c.Read(ctx, "balanceOf", testHolderAddr)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(1)}, balance)
+	balance, err = c.Read(ctx, "balanceOf", testHolderAddr, big.NewInt(7))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(2)}, balance)
+
+	_, err = c.Read(ctx, "balanceOf")
+	assert.ErrorContains(t, err, "no overload taking 0 arguments")
+
+	// Others must be designated by signature
+	_, err = c.Read(ctx, "price", big.NewInt(7))
+	assert.ErrorIs(t, err, abi.ErrAmbiguousName)
+	assert.Zero(t, calls[selector("price(address)")]+calls[selector("price(uint256)")])
+
+	price, err := c.Read(ctx, "price(uint256)", big.NewInt(7))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(4)}, price)
+	price, err = c.Read(ctx, "price(address token)", testHolderAddr)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(3)}, price)
+
+	_, err = c.Read(ctx, "price(bool)", true)
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,540 @@
+package contract
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// wordSize is the size in bytes of an ABI word
+const wordSize = 32
+
+// DefaultMaxDepth is how deeply arrays and tuples may nest in encoded and decoded types unless set by WithMaxDepth
+const DefaultMaxDepth = 32
+
+// ErrMaxDepthExceeded is returned for types nesting arrays and tuples deeper than the maximum depth
+var ErrMaxDepthExceeded = errors.New("maximum nesting depth exceeded")
+
+// DecodeError reports a value which failed to decode, e.g. from returndata too short for the declared outputs
+type DecodeError struct {
+	// Index is the position of the value among the outputs, or components of a tuple
+	Index int
+	// Type is the declared type of the value
+	Type string
+	// Offset is the byte offset of the head of the value in the data
+	Offset int
+	// Data is the hex of the data around Offset, shortened for long data
+	Data string
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	msg := fmt.Sprintf("failed to decode value %d (%s) at offset %d: %v", e.Index, e.Type, e.Offset, e.Err)
+	if e.Data != "" {
+		msg += " in data " + e.Data
+	}
+	return msg
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// dataSnippet returns the hex of up to two words of data from offset, or of its last words when offset is past the end
+func dataSnippet(data []byte, offset int) string {
+	const maxLen = 2 * wordSize
+	start := min(max(offset, 0), len(data))
+	if start == len(data) {
+		start = max(len(data)-maxLen, 0)
+	}
+	end := min(start+maxLen, len(data))
+
+	s := "0x" + hex.EncodeToString(data[start:end])
+	if start > 0 {
+		s = "..." + s[2:]
+	}
+	if end < len(data) {
+		s += "..."
+	}
+	return s
+}
+
+// decoder decodes ABI encoded data into Go values.
+// uintN and intN decode as *big.Int, bool as bool, address as a 0x-prefixed lowercase hex string,
+// string as string, bytes/bytesN as []byte, arrays as []interface{} and tuples as map[string]interface{}.
+type decoder struct {
+	utf8Mode   UTF8Mode
+	strictBool bool
+	// maxDepth bounds the nesting of arrays and tuples, DefaultMaxDepth when 0
+	maxDepth int
+}
+
+// decodeValues decodes an ABI encoded sequence of values, as found in call returndata and event data.
+// Failures are reported as a *DecodeError of the value failing, along with a snippet of data.
+func (d *decoder) decodeValues(params []abi.ABIParameter, data []byte) ([]interface{}, error) {
+	// Bound the recursion over the types before any of it happens
+	if err := checkDepth(params, d.maxDepth); err != nil {
+		return nil, err
+	}
+
+	values, err := d.decodeSequence(params, data)
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		decodeErr.Data = dataSnippet(data, decodeErr.Offset)
+	}
+	return values, err
+}
+
+// decodeSequence decodes a sequence of values whose types are known not to nest too deeply
+func (d *decoder) decodeSequence(params []abi.ABIParameter, data []byte) ([]interface{}, error) {
+	tailStart := 0
+	for _, param := range params {
+		tailStart += headSize(param)
+	}
+
+	values := make([]interface{}, len(params))
+	head := 0
+	for i, param := range params {
+		value, err := d.decodeAt(param, data, head, tailStart)
+		if err != nil {
+			return nil, &DecodeError{Index: i, Type: param.Type, Offset: head, Err: err}
+		}
+		values[i] = value
+		head += headSize(param)
+	}
+	return values, nil
+}
+
+// decodeAt decodes the value whose head starts at head in data, in a sequence whose heads end at tailStart.
+// Offsets of dynamic values are relative to the start of data, and must point forward past the heads
+// and within data, so crafted offsets can't make values overlap the heads or read out of bounds.
+func (d *decoder) decodeAt(param abi.ABIParameter, data []byte, head, tailStart int) (interface{}, error) {
+	if isDynamic(param) {
+		offset, err := readSize(data, head)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset: %w", err)
+		}
+		if offset < tailStart {
+			return nil, fmt.Errorf("invalid offset: %d points back into the heads ending at %d", offset, tailStart)
+		}
+		if offset > len(data)-wordSize {
+			return nil, fmt.Errorf("invalid offset: %d out of bounds for data of %d bytes", offset, len(data))
+		}
+		return d.decodeDynamic(param, data, offset)
+	}
+
+	// Static arrays and tuples are encoded in place, element after element
+	if elem, length, ok := arrayElem(param); ok {
+		return d.decodeArray(elem, length, data, head)
+	}
+	if param.Type == "tuple" {
+		if head > len(data) {
+			return nil, fmt.Errorf("data of %d bytes too short to read tuple at %d", len(data), head)
+		}
+		return d.decodeTuple(param.Components, data[head:])
+	}
+
+	word, err := readWord(data, head)
+	if err != nil {
+		return nil, err
+	}
+	return d.decodeStatic(param.Type, word)
+}
+
+// decodeArray decodes length consecutive elements whose heads start at head in data
+func (d *decoder) decodeArray(elem abi.ABIParameter, length int, data []byte, head int) ([]interface{}, error) {
+	// Elements taking no space, like tuples without components, can't bound the length by the data
+	size := headSize(elem)
+	if size == 0 {
+		return nil, fmt.Errorf("array of %s: element type encodes to no data", elem.Type)
+	}
+	if length > (len(data)-head)/size {
+		return nil, fmt.Errorf("array of %d %s exceeds data of %d bytes", length, elem.Type, len(data))
+	}
+
+	tailStart := head + length*size
+	values := make([]interface{}, length)
+	for i := range values {
+		value, err := d.decodeAt(elem, data, head+i*size, tailStart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode element %d: %w", i, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// decodeTuple decodes the components of a tuple encoded as a sequence at the start of data.
+// Components are keyed like ReadContractMap outputs.
+func (d *decoder) decodeTuple(components []abi.ABIParameter, data []byte) (map[string]interface{}, error) {
+	values, err := d.decodeSequence(components, data)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := fieldKeys(components)
+	fields := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		fields[keys[i]] = value
+	}
+	return fields, nil
+}
+
+// decodeStatic decodes a value which is encoded in place in a single word
+func (d *decoder) decodeStatic(typ string, word []byte) (interface{}, error) {
+	typ = canonicalType(typ)
+	switch typ {
+	case "address":
+		if !isZero(word[:12]) {
+			return nil, fmt.Errorf("address has non-zero upper bytes")
+		}
+		return "0x" + hex.EncodeToString(word[12:]), nil
+	case "bool":
+		// Any nonzero word is true, unless only the canonical 0 and 1 are accepted
+		value := new(big.Int).SetBytes(word)
+		if d.strictBool && value.Cmp(big.NewInt(1)) > 0 {
+			return nil, fmt.Errorf("invalid bool value: 0x%s", value.Text(16))
+		}
+		return value.Sign() != 0, nil
+	}
+
+	if signed, bits, ok := parseIntType(typ); ok {
+		return decodeInt(word, signed, bits)
+	}
+
+	if size, ok := parseFixedBytesType(typ); ok {
+		// bytesN is left aligned, the remainder of the word must be padding
+		if !isZero(word[size:]) {
+			return nil, fmt.Errorf("%s has non-zero padding", typ)
+		}
+		value := make([]byte, size)
+		copy(value, word[:size])
+		return value, nil
+	}
+
+	if codec, ok := lookupType(typ); ok {
+		value, err := codec.Decode(word)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", typ, err)
+		}
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type: %s", typ)
+}
+
+// decodeDynamic decodes a value encoded out of place at offset in data.
+// Arrays decode as []interface{} of their elements and tuples as map[string]interface{} of their components.
+func (d *decoder) decodeDynamic(param abi.ABIParameter, data []byte, offset int) (interface{}, error) {
+	if elem, length, ok := arrayElem(param); ok {
+		if length >= 0 {
+			// Fixed-size arrays of dynamic elements are encoded as a sequence at offset
+			return d.decodeArray(elem, length, data[offset:], 0)
+		}
+
+		count, err := readSize(data, offset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid length: %w", err)
+		}
+		// Offsets of dynamic elements are relative to the first element, after the length word
+		return d.decodeArray(elem, count, data[offset+wordSize:], 0)
+	}
+	if param.Type == "tuple" {
+		// Offsets of dynamic components are relative to the start of the tuple
+		return d.decodeTuple(param.Components, data[offset:])
+	}
+
+	typ := param.Type
+	length, err := readSize(data, offset)
+	if err != nil {
+		return nil, fmt.Errorf("invalid length: %w", err)
+	}
+
+	start := offset + wordSize
+	if length > len(data)-start {
+		return nil, fmt.Errorf("%s of length %d exceeds data of %d bytes", typ, length, len(data))
+	}
+	content := data[start : start+length]
+
+	switch typ {
+	case "string":
+		return d.decodeString(content)
+	case "bytes":
+		value := make([]byte, length)
+		copy(value, content)
+		return value, nil
+	default:
+		return nil, fmt.Errorf("unsupported type: %s", typ)
+	}
+}
+
+// decodeString converts raw string bytes according to the configured UTF8Mode
+func (d *decoder) decodeString(b []byte) (string, error) {
+	if d.utf8Mode == UTF8Strict && !utf8.Valid(b) {
+		return "", fmt.Errorf("string data is not valid UTF-8")
+	}
+	return strings.ToValidUTF8(string(b), string(utf8.RuneError)), nil
+}
+
+// decodeInt decodes an intN or uintN word and checks the value fits the declared width
+func decodeInt(word []byte, signed bool, bits int) (*big.Int, error) {
+	value := new(big.Int).SetBytes(word)
+	if !signed {
+		if value.BitLen() > bits {
+			return nil, fmt.Errorf("value overflows uint%d", bits)
+		}
+		return value, nil
+	}
+
+	// Two's complement: a set top bit means the value is negative
+	if value.Bit(wordSize*8-1) == 1 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), wordSize*8))
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	if value.Cmp(limit) >= 0 || value.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("value overflows int%d", bits)
+	}
+	return value, nil
+}
+
+// readWord returns the word starting at pos in data
+func readWord(data []byte, pos int) ([]byte, error) {
+	if pos < 0 || pos > len(data)-wordSize {
+		return nil, fmt.Errorf("data of %d bytes too short to read word at %d", len(data), pos)
+	}
+	return data[pos : pos+wordSize], nil
+}
+
+// readSize reads the word at pos as an offset or length, which must fit within data
+func readSize(data []byte, pos int) (int, error) {
+	word, err := readWord(data, pos)
+	if err != nil {
+		return 0, err
+	}
+
+	size := new(big.Int).SetBytes(word)
+	if !size.IsInt64() || size.Int64() > int64(len(data)) {
+		return 0, fmt.Errorf("value %s out of range for data of %d bytes", size, len(data))
+	}
+	return int(size.Int64()), nil
+}
+
+// checkDepth returns ErrMaxDepthExceeded when arrays and tuples in params nest deeper than maxDepth,
+// or DefaultMaxDepth when maxDepth is 0. The check stops at the limit, so it is itself bounded.
+func checkDepth(params []abi.ABIParameter, maxDepth int) error {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	for _, param := range params {
+		if err := checkParamDepth(param, maxDepth); err != nil {
+			return fmt.Errorf("%w: %s nests deeper than %d", ErrMaxDepthExceeded, param.Type, maxDepth)
+		}
+	}
+	return nil
+}
+
+// checkParamDepth fails when param nests more than remaining levels of arrays and tuples
+func checkParamDepth(param abi.ABIParameter, remaining int) error {
+	elem, _, isArray := arrayElem(param)
+	if !isArray && param.Type != "tuple" {
+		return nil
+	}
+	if remaining == 0 {
+		return ErrMaxDepthExceeded
+	}
+
+	if isArray {
+		return checkParamDepth(elem, remaining-1)
+	}
+	for _, component := range param.Components {
+		if err := checkParamDepth(component, remaining-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// canonicalType normalizes annotated types such as "address payable" to the type they encode as
+func canonicalType(typ string) string {
+	return abi.CanonicalType(typ)
+}
+
+// isDynamic reports whether values of param are encoded out of place with an offset in the head
+func isDynamic(param abi.ABIParameter) bool {
+	if elem, length, ok := arrayElem(param); ok {
+		return length < 0 || isDynamic(elem)
+	}
+	if param.Type == "tuple" {
+		for _, component := range param.Components {
+			if isDynamic(component) {
+				return true
+			}
+		}
+		return false
+	}
+	return param.Type == "string" || param.Type == "bytes"
+}
+
+// headSize returns the number of bytes a value of param occupies in the head of a sequence.
+// Dynamic values only store their offset, static arrays and tuples store all their elements in place.
+func headSize(param abi.ABIParameter) int {
+	if isDynamic(param) {
+		return wordSize
+	}
+	if elem, length, ok := arrayElem(param); ok {
+		return length * headSize(elem)
+	}
+	if param.Type == "tuple" {
+		size := 0
+		for _, component := range param.Components {
+			size += headSize(component)
+		}
+		return size
+	}
+	return wordSize
+}
+
+// arrayElem returns the element parameter and length of an array parameter, the length being -1 for T[].
+// Elements of tuple arrays share the components of the array.
+func arrayElem(param abi.ABIParameter) (abi.ABIParameter, int, bool) {
+	elem, length, ok := parseArrayType(param.Type)
+	if !ok {
+		return abi.ABIParameter{}, 0, false
+	}
+	return abi.ABIParameter{Type: elem, Components: param.Components}, length, true
+}
+
+// parseArrayType parses T[] and T[k] types into their element type and length, which is -1 for T[]
+func parseArrayType(typ string) (elem string, length int, ok bool) {
+	if !strings.HasSuffix(typ, "]") {
+		return "", 0, false
+	}
+	open := strings.LastIndex(typ, "[")
+	if open <= 0 {
+		return "", 0, false
+	}
+
+	elem = typ[:open]
+	size := typ[open+1 : len(typ)-1]
+	if size == "" {
+		return elem, -1, true
+	}
+	length, err := strconv.Atoi(size)
+	if err != nil || length < 1 || size[0] == '0' {
+		return "", 0, false
+	}
+	return elem, length, true
+}
+
+// parseIntType parses uintN and intN types, where uint and int are aliases for a width of 256
+func parseIntType(typ string) (signed bool, bits int, ok bool) {
+	var width string
+	switch {
+	case strings.HasPrefix(typ, "uint"):
+		width = strings.TrimPrefix(typ, "uint")
+	case strings.HasPrefix(typ, "int"):
+		signed = true
+		width = strings.TrimPrefix(typ, "int")
+	default:
+		return false, 0, false
+	}
+
+	if width == "" {
+		return signed, 256, true
+	}
+	bits, err := strconv.Atoi(width)
+	if err != nil || bits < 8 || bits > 256 || bits%8 != 0 || width[0] == '0' {
+		return false, 0, false
+	}
+	return signed, bits, true
+}
+
+// parseFixedBytesType parses bytesN types with 1 <= N <= 32
+func parseFixedBytesType(typ string) (int, bool) {
+	if !strings.HasPrefix(typ, "bytes") {
+		return 0, false
+	}
+
+	width := strings.TrimPrefix(typ, "bytes")
+	size, err := strconv.Atoi(width)
+	if err != nil || size < 1 || size > 32 || width[0] == '0' {
+		return 0, false
+	}
+	return size, true
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldKeys returns the map keys of params: their name, or their index when unnamed.
+// Duplicate names get their index appended, e.g. "amount_2".
+func fieldKeys(params []abi.ABIParameter) []string {
+	keys := make([]string, len(params))
+	used := make(map[string]bool, len(params))
+	for i, param := range params {
+		key := param.Name
+		if key == "" {
+			key = strconv.Itoa(i)
+		}
+		for suffix := i; used[key]; suffix++ {
+			key = fmt.Sprintf("%s_%d", param.Name, suffix)
+		}
+		used[key] = true
+		keys[i] = key
+	}
+	return keys
+}
+
+// cloneParameters deep copies params, so callers can't alter the ABI they were taken from
+func cloneParameters(params []abi.ABIParameter) []abi.ABIParameter {
+	if params == nil {
+		return nil
+	}
+	clone := make([]abi.ABIParameter, len(params))
+	for i, param := range params {
+		param.Components = cloneParameters(param.Components)
+		clone[i] = param
+	}
+	return clone
+}
+
+// toJSONNumbers replaces the *big.Int of a decoded value, including within arrays and tuples, with json.Number
+func toJSONNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return json.Number(v.String())
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = toJSONNumbers(elem)
+		}
+	case map[string]interface{}:
+		for key, field := range v {
+			v[key] = toJSONNumbers(field)
+		}
+	}
+	return value
+}
+
+// formatValue renders a decoded value as a string.
+// Integers are formatted in decimal and bytes as 0x-prefixed hex.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,243 @@
+package contract
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode_Offsets(t *testing.T) {
+	word := func(n int) string {
+		return hex.EncodeToString(encodeSize(n))
+	}
+	decode := func(params []abi.ABIParameter, encoded string) ([]interface{}, error) {
+		data, err := hex.DecodeString(encoded)
+		require.NoError(t, err)
+		return (&decoder{}).decodeValues(params, data)
+	}
+
+	str := []abi.ABIParameter{{Type: "string"}}
+	content := word(3) + hex.EncodeToString([]byte("abc")) + strings.Repeat("00", 29)
+
+	values, err := decode(str, word(32)+content)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"abc"}, values)
+
+	// An offset of 0 points back at itself, the string would be read from its own head
+	_, err = decode(str, word(0)+content)
+	assert.ErrorContains(t, err, "points back")
+
+	// Offsets past the end, including the end itself which leaves no room for the length
+	_, err = decode(str, word(96)+content)
+	assert.ErrorContains(t, err, "out of bounds")
+
+	_, err = decode(str, word(128)+content)
+	assert.ErrorContains(t, err, "invalid offset")
+
+	_, err = decode(str, word(1<<40)+content)
+	assert.ErrorContains(t, err, "invalid offset")
+
+	// With two values, the tail starts after both heads
+	pair := []abi.ABIParameter{{Type: "uint256"}, {Type: "string"}}
+	values, err = decode(pair, word(7)+word(64)+content)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", values[1])
+
+	_, err = decode(pair, word(7)+word(32)+content)
+	assert.ErrorContains(t, err, "points back into the heads ending at 64")
+
+	// Offsets of array elements are checked against the heads of the elements
+	strs := []abi.ABIParameter{{Type: "string[]"}}
+	values, err = decode(strs, word(32)+word(1)+word(32)+content)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"abc"}, values[0])
+
+	_, err = decode(strs, word(32)+word(1)+word(0)+content)
+	assert.ErrorContains(t, err, "points back")
+}
+
+func TestDecode_MaxDepth(t *testing.T) {
+	// nested returns a tuple wrapping a uint256 depth levels deep
+	nested := func(depth int) abi.ABIParameter {
+		param := abi.ABIParameter{Type: "uint256"}
+		for i := 0; i < depth; i++ {
+			param = abi.ABIParameter{Type: "tuple", Components: []abi.ABIParameter{param}}
+		}
+		return param
+	}
+	data := encodeSize(7)
+
+	_, err := (&decoder{}).decodeValues([]abi.ABIParameter{nested(DefaultMaxDepth)}, data)
+	require.NoError(t, err)
+
+	_, err = (&decoder{}).decodeValues([]abi.ABIParameter{nested(DefaultMaxDepth + 1)}, data)
+	assert.ErrorIs(t, err, ErrMaxDepthExceeded)
+
+	// Arrays count as a level each, like tuples
+	_, err = (&decoder{}).decodeValues([]abi.ABIParameter{{Type: "uint256" + strings.Repeat("[1]", DefaultMaxDepth+1)}}, data)
+	assert.ErrorIs(t, err, ErrMaxDepthExceeded)
+
+	// The limit is configurable, and applies to encoding too
+	_, err = (&decoder{maxDepth: 2}).decodeValues([]abi.ABIParameter{nested(3)}, data)
+	assert.ErrorIs(t, err, ErrMaxDepthExceeded)
+
+	_, err = EncodeReturn([]abi.ABIParameter{nested(DefaultMaxDepth + 1)}, []interface{}{nil})
+	assert.ErrorIs(t, err, ErrMaxDepthExceeded)
+
+	cli := &contractClient{maxDepth: 2}
+	fn := abi.ContractABI{Type: "function", Name: "f", Inputs: []abi.ABIParameter{{Name: "arg", Type: "tuple", Components: nested(3).Components}}}
+	err = cli.validateInputs(fn, map[string]interface{}{"arg": nil})
+	assert.ErrorIs(t, err, ErrMaxDepthExceeded)
+}
+
+func TestDecode_AddressArray(t *testing.T) {
+	cli := &contractClient{}
+
+	// getOwners() returns (address[]) with two owners
+	getOwners := abi.ContractABI{
+		Type:    "function",
+		Name:    "getOwners",
+		Outputs: []abi.ABIParameter{{Type: "address[]"}},
+	}
+	data := "0x" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000d500b1d8e8ef31e21c99d1db9a6444d3adf1270" +
+		"0000000000000000000000002791bca1f2de4661ed88a30c99a7a9449aa84174"
+
+	raw, err := hex.DecodeString(data[2:])
+	require.NoError(t, err)
+	values, err := cli.decoder().decodeValues(getOwners.Outputs, raw)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	assert.Equal(t, []interface{}{
+		"0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270",
+		"0x2791bca1f2de4661ed88a30c99a7a9449aa84174",
+	}, values[0])
+
+	ret, err := cli.parseResponse(data, getOwners)
+	require.NoError(t, err)
+	// ReadContract returns checksummed addresses
+	assert.Equal(t, "[0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270 0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174]", ret)
+
+	// A length running past the data is rejected
+	_, err = cli.decoder().decodeValues(getOwners.Outputs, raw[:96])
+	assert.Error(t, err)
+}
+
+func TestDecode_StringOffset(t *testing.T) {
+	cli := &contractClient{}
+	name := abi.ContractABI{
+		Type:    "function",
+		Name:    "name",
+		Outputs: []abi.ABIParameter{{Type: "string"}},
+	}
+	content := "0000000000000000000000000000000000000000000000000000000000000006" +
+		"574d415449430000000000000000000000000000000000000000000000000000"
+
+	// Encoders may leave gaps between the heads and the tails: the offset locates the length word
+	for _, tc := range []struct {
+		offset string
+		gap    string
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000020", ""},
+		{"0000000000000000000000000000000000000000000000000000000000000040", strings.Repeat("ff", 32)},
+		{"0000000000000000000000000000000000000000000000000000000000000060", strings.Repeat("00", 64)},
+	} {
+		ret, err := cli.parseResponse("0x"+tc.offset+tc.gap+content, name)
+		require.NoError(t, err, tc.offset)
+		assert.Equal(t, "WMATIC", ret)
+	}
+
+	// A word in the gap which looks like a length isn't read as one
+	ret, err := cli.parseResponse("0x"+
+		"0000000000000000000000000000000000000000000000000000000000000040"+
+		"0000000000000000000000000000000000000000000000000000000000000003"+
+		content, name)
+	require.NoError(t, err)
+	assert.Equal(t, "WMATIC", ret)
+}
+
+func TestDecode_Error(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// decimals returns 16 bytes instead of a word, and name something else than hex
+	registerEthCallResponder(t, map[string]string{
+		"18160ddd": "0x0000000000000000000000000000000000000000000000000de0b6b3a7640000",
+		"313ce567": "0x00000000000000000000000000000012",
+		"06fdde03": "0x0x12",
+	})
+
+	contractABIs := loadFixtureABIs(t)
+	decimals, err := contractABIs.Find("decimals")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+	_, err = cli.ReadContract(ctx, testWMATICAddr, *decimals, map[string]interface{}{})
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, 0, decodeErr.Index)
+	assert.Equal(t, "uint8", decodeErr.Type)
+	assert.Equal(t, 0, decodeErr.Offset)
+	assert.Equal(t, "0x00000000000000000000000000000012", decodeErr.Data)
+	assert.ErrorContains(t, err, "failed to decode value 0 (uint8) at offset 0")
+
+	// The failing output is reported by its position among the outputs
+	totalSupply, err := contractABIs.Find("totalSupply")
+	require.NoError(t, err)
+	_, err = cli.ReadContractValues(ctx, testWMATICAddr, *totalSupply, map[string]interface{}{},
+		WithOutputTypes("uint256", "bool"))
+	require.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, 1, decodeErr.Index)
+	assert.Equal(t, "bool", decodeErr.Type)
+	assert.Equal(t, 32, decodeErr.Offset)
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000de0b6b3a7640000", decodeErr.Data)
+
+	// So are responses which aren't hex
+	name, err := contractABIs.Find("name")
+	require.NoError(t, err)
+	_, err = cli.ReadContract(ctx, testWMATICAddr, *name, map[string]interface{}{})
+	require.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, "string", decodeErr.Type)
+	assert.Equal(t, "0x0x12", decodeErr.Data)
+}
+
+func TestDecode_DataSnippet(t *testing.T) {
+	data := make([]byte, 5*wordSize)
+	data[2*wordSize] = 0xab
+
+	assert.Equal(t, "0x", dataSnippet(nil, 0))
+	assert.Equal(t, "0x"+strings.Repeat("00", 64)+"...", dataSnippet(data, 0))
+	assert.Equal(t, "...ab"+strings.Repeat("00", 63)+"...", dataSnippet(data, 2*wordSize))
+	assert.Equal(t, "..."+strings.Repeat("00", 64), dataSnippet(data, 10*wordSize))
+}
+
+func TestDecode_EmptyTupleArray(t *testing.T) {
+	event := abi.ContractABI{
+		Type: "event",
+		Name: "Empty",
+		Inputs: []abi.ABIParameter{
+			{Name: "items", Type: "tuple[]", Components: []abi.ABIParameter{}},
+		},
+	}
+	topic, err := event.EventTopic()
+	require.NoError(t, err)
+
+	// A tuple without components takes no space, so the length can't be checked against the data
+	data := "0x0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000003"
+	_, err = DecodeLog(event, []string{topic}, data)
+	assert.ErrorContains(t, err, "element type encodes to no data")
+
+	event.Inputs[0].Type = "tuple[2]"
+	_, err = DecodeLog(event, []string{topic}, "0x")
+	assert.ErrorContains(t, err, "element type encodes to no data")
+}
@@ -0,0 +1,74 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// ErrMethodNotFound is returned by reads made WithMethodDiagnostics when the function most likely doesn't exist
+// on the contract, e.g. because the ABI doesn't match the deployed code
+var ErrMethodNotFound = errors.New("function may not exist on this contract, ABI mismatch?")
+
+const (
+	// push0 is the EVM opcode pushing zero
+	push0 = 0x5f
+	// push1 is the EVM opcode pushing a 1-byte constant, followed by PUSH2 to PUSH32 for longer ones
+	push1 = 0x60
+)
+
+// diagnoseRead explains an empty or reverted read of fn at addr when the contract is missing
+// or its code doesn't dispatch the selector of fn. It returns nil when the function looks present,
+// or when the code can't be fetched.
+// The code of a proxy doesn't contain the selectors of its implementation, so reads through proxies are reported too,
+// as are contracts whose dispatcher doesn't push selectors as constants, which is why ErrMethodNotFound is only a hint.
+func (c *contractClient) diagnoseRead(ctx context.Context, addr string, fn abi.ContractABI) error {
+	result, err := c.call(ctx, "eth_getCode", addr, "latest")
+	if err != nil {
+		return nil
+	}
+	code, err := decodeData(result)
+	if err != nil {
+		return nil
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w: no contract code at %s", ErrMethodNotFound, addr)
+	}
+
+	methodID, err := fn.MethodID()
+	if err != nil {
+		return nil
+	}
+	selector, err := hex.DecodeString(methodID)
+	if err != nil {
+		return nil
+	}
+	if !pushesSelector(code, selector) {
+		return fmt.Errorf("%w: selector %s of %s not found in the code at %s", ErrMethodNotFound, methodID, fn.Name, addr)
+	}
+	return nil
+}
+
+// pushesSelector reports whether code pushes selector, as dispatchers do to compare it to the selector of the call.
+// Compilers push the selector without its leading zero bytes, e.g. 0x00fdd58e with PUSH3 fdd58e.
+func pushesSelector(code, selector []byte) bool {
+	trimmed := bytes.TrimLeft(selector, "\x00")
+	if len(trimmed) == 0 {
+		return bytes.IndexByte(code, push0) >= 0 || bytes.Contains(code, []byte{push1, 0x00})
+	}
+	return bytes.Contains(code, append([]byte{push1 + byte(len(trimmed)-1)}, trimmed...))
+}
+
+// isBareRevert reports whether err is a revert without revert data, which is how calls to missing functions fail
+func isBareRevert(err error) bool {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || !isRevert(rpcErr) {
+		return false
+	}
+	_, hasData := rpcErr.revertData()
+	return !hasData
+}
@@ -0,0 +1,94 @@
+package contract
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnose_MethodNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	decimals, err := loadFixtureABIs(t).Find("decimals")
+	require.NoError(t, err)
+
+	// A dispatcher comparing the selector of decimals() and one without it
+	withSelector := "0x6080604052348015600f57600080fd5b5063313ce567"
+	withoutSelector := "0x6080604052348015600f57600080fd5b506306fdde03"
+
+	tests := []struct {
+		name     string
+		call     string
+		code     string
+		notFound bool
+		contains string
+	}{
+		{"empty result, missing selector", `"result":"0x"`, withoutSelector, true, "selector 313ce567 of decimals not found"},
+		{"bare revert, missing selector", `"error":{"code":-32000,"message":"execution reverted"}`, withoutSelector, true, "execution reverted"},
+		{"empty result, no contract", `"result":"0x"`, "0x", true, "no contract code"},
+		{"empty result, selector present", `"result":"0x"`, withSelector, false, "too short"},
+		{"revert with data", `"error":{"code":3,"message":"execution reverted","data":"0x08c379a0"}`, withoutSelector, false, "execution reverted"},
+	}
+
+	for _, tt := range tests {
+		var getCodeCalls int
+		httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+			func(req *http.Request) (*http.Response, error) {
+				var rpcReq rpcRequest
+				require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+				if rpcReq.Method == "eth_getCode" {
+					getCodeCalls++
+					return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":%q}`, tt.code)), nil
+				}
+				return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,`+tt.call+`}`), nil
+			})
+
+		cli := NewClient(testRPCURL, WithMethodDiagnostics())
+		_, err := cli.ReadContract(context.Background(), testWMATICAddr, *decimals, map[string]interface{}{})
+		require.Error(t, err, tt.name)
+		assert.Equal(t, tt.notFound, errors.Is(err, ErrMethodNotFound), tt.name)
+		assert.ErrorContains(t, err, tt.contains, tt.name)
+
+		// Without the option the code isn't checked
+		getCodeCalls = 0
+		_, err = NewClient(testRPCURL).ReadContract(context.Background(), testWMATICAddr, *decimals, map[string]interface{}{})
+		require.Error(t, err, tt.name)
+		assert.NotErrorIs(t, err, ErrMethodNotFound, tt.name)
+		assert.Zero(t, getCodeCalls, tt.name)
+	}
+}
+
+func TestDiagnose_PushesSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		selector string
+		found    bool
+	}{
+		{"push4", "6080604052" + "63313ce567" + "14", "313ce567", true},
+		{"push4 missing", "6080604052" + "6306fdde03" + "14", "313ce567", false},
+		// balanceOf(address,uint256) of ERC-1155 starts with a zero byte, so solc pushes it with PUSH3
+		{"push3", "6080604052" + "62fdd58e" + "14", "00fdd58e", true},
+		{"push4 of a zero-led selector", "6080604052" + "6300fdd58e" + "14", "00fdd58e", false},
+		{"push1", "6080604052" + "6042" + "14", "00000042", true},
+		{"zero selector with push0", "6080604052" + "5f" + "14", "00000000", true},
+		{"zero selector with push1", "6080604052" + "6000" + "14", "00000000", true},
+	}
+
+	for _, tt := range tests {
+		code, err := hex.DecodeString(tt.code)
+		require.NoError(t, err, tt.name)
+		selector, err := hex.DecodeString(tt.selector)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.found, pushesSelector(code, selector), tt.name)
+	}
+}
@@ -0,0 +1,321 @@
+package contract
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// EncodeReturn ABI encodes values as a contract would return them from a function with the given outputs.
+// It produces the 0x-prefixed hex returndata of eth_call, e.g. to build mocked RPC responses.
+// Values use the same Go types as the decoder: *big.Int for integers, bool, hex strings for addresses,
+// string, []byte or hex strings for bytes, slices for arrays, and maps, slices or structs for tuples.
+// Types nesting arrays and tuples deeper than DefaultMaxDepth are rejected.
+func EncodeReturn(outputs []abi.ABIParameter, values []interface{}) (string, error) {
+	if err := checkDepth(outputs, DefaultMaxDepth); err != nil {
+		return "", err
+	}
+	data, err := encodeValues(outputs, values)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(data), nil
+}
+
+// encodeValues ABI encodes a sequence of values.
+// Static values are placed in the head and dynamic values in the tail, referenced from the head by offset.
+func encodeValues(params []abi.ABIParameter, values []interface{}) ([]byte, error) {
+	if len(params) != len(values) {
+		return nil, fmt.Errorf("value count mismatch: expected %d, got %d", len(params), len(values))
+	}
+
+	// Encode every value first, so the sequence is assembled in a single allocation
+	encoded := make([][]byte, len(params))
+	headLen, tailLen := 0, 0
+	for i, param := range params {
+		value, err := encodeValue(param, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode value %d (%s): %w", i, param.Type, err)
+		}
+		encoded[i] = value
+
+		headLen += headSize(param)
+		if isDynamic(param) {
+			tailLen += len(value)
+		}
+	}
+
+	data := make([]byte, 0, headLen+tailLen)
+	tailOffset := headLen
+	for i, param := range params {
+		if isDynamic(param) {
+			data = append(data, encodeSize(tailOffset)...)
+			tailOffset += len(encoded[i])
+			continue
+		}
+		data = append(data, encoded[i]...)
+	}
+	for i, param := range params {
+		if isDynamic(param) {
+			data = append(data, encoded[i]...)
+		}
+	}
+	return data, nil
+}
+
+// encodeValue encodes a single value: its head for static types, or its tail for dynamic types
+func encodeValue(param abi.ABIParameter, value interface{}) ([]byte, error) {
+	if elem, length, ok := arrayElem(param); ok {
+		elements, err := arrayElements(value)
+		if err != nil {
+			return nil, err
+		}
+		if length >= 0 && len(elements) != length {
+			return nil, fmt.Errorf("expected %d elements, got %d", length, len(elements))
+		}
+
+		params := make([]abi.ABIParameter, len(elements))
+		for i := range params {
+			params[i] = elem
+		}
+		encoded, err := encodeValues(params, elements)
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return append(encodeSize(len(elements)), encoded...), nil
+		}
+		return encoded, nil
+	}
+
+	if param.Type == "tuple" {
+		components, err := tupleValues(param.Components, value)
+		if err != nil {
+			return nil, err
+		}
+		return encodeValues(param.Components, components)
+	}
+
+	if isDynamic(param) {
+		return encodeDynamic(param.Type, value)
+	}
+	return encodeStatic(param.Type, value)
+}
+
+// tupleValues returns the component values of a tuple given as a map keyed like decoded tuples,
+// a slice in order, or a struct as described by structValues
+func tupleValues(components []abi.ABIParameter, value interface{}) ([]interface{}, error) {
+	if v := reflect.Indirect(reflect.ValueOf(value)); v.Kind() == reflect.Struct {
+		return structValues(components, v)
+	}
+
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return arrayElements(value)
+	}
+
+	keys := fieldKeys(components)
+	values := make([]interface{}, len(components))
+	for i, key := range keys {
+		v, ok := fields[key]
+		if !ok {
+			return nil, fmt.Errorf("missing tuple component %q", key)
+		}
+		values[i] = v
+	}
+	if len(fields) != len(keys) {
+		return nil, fmt.Errorf("tuple has %d components, got %d values", len(keys), len(fields))
+	}
+	return values, nil
+}
+
+// structValues returns the component values of a tuple given as a struct.
+// When any field has an `abi:"name"` tag, tagged fields are matched to the components by name,
+// using the same keys as decoded tuples, and untagged fields are ignored.
+// Otherwise the exported fields are the components in order. Fields tagged `abi:"-"` are always ignored.
+// Field values must have the Go types the component types are encoded from, e.g. *big.Int for uint24.
+func structValues(components []abi.ABIParameter, v reflect.Value) ([]interface{}, error) {
+	typ := v.Type()
+
+	var positional []interface{}
+	tagged := make(map[string]interface{})
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, hasTag := field.Tag.Lookup("abi")
+		if tag == "-" {
+			continue
+		}
+		if hasTag {
+			if _, dup := tagged[tag]; dup {
+				return nil, fmt.Errorf("duplicate abi tag %q in %s", tag, typ)
+			}
+			tagged[tag] = v.Field(i).Interface()
+			continue
+		}
+		positional = append(positional, v.Field(i).Interface())
+	}
+
+	if len(tagged) == 0 {
+		if len(positional) != len(components) {
+			return nil, fmt.Errorf("tuple has %d components, %s has %d exported fields", len(components), typ, len(positional))
+		}
+		return positional, nil
+	}
+
+	values, err := tupleValues(components, tagged)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", typ, err)
+	}
+	return values, nil
+}
+
+// arrayElements returns the elements of a slice or array value, e.g. []interface{} or []*big.Int
+func arrayElements(value interface{}) ([]interface{}, error) {
+	if elements, ok := value.([]interface{}); ok {
+		return elements, nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a slice, got %T", value)
+	}
+	elements := make([]interface{}, v.Len())
+	for i := range elements {
+		elements[i] = v.Index(i).Interface()
+	}
+	return elements, nil
+}
+
+// encodeStatic encodes a value which fits in place in a single word
+func encodeStatic(typ string, value interface{}) ([]byte, error) {
+	typ = canonicalType(typ)
+	switch typ {
+	case "address":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected address string, got %T", value)
+		}
+		if err := ValidateAddress(s); err != nil {
+			return nil, err
+		}
+		addr, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q", s)
+		}
+		return leftPad(addr), nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected bool, got %T", value)
+		}
+		if b {
+			return encodeSize(1), nil
+		}
+		return encodeSize(0), nil
+	}
+
+	if signed, bits, ok := parseIntType(typ); ok {
+		if u, ok := value.(Uint256); ok {
+			return encodeInt(u.Big(), signed, bits)
+		}
+		v, ok := value.(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("expected *big.Int, got %T", value)
+		}
+		if v == nil {
+			return nil, fmt.Errorf("nil *big.Int for %s", typ)
+		}
+		return encodeInt(v, signed, bits)
+	}
+
+	if size, ok := parseFixedBytesType(typ); ok {
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > size {
+			return nil, fmt.Errorf("%d bytes do not fit in %s", len(b), typ)
+		}
+		// bytesN is left aligned and right padded
+		word := make([]byte, wordSize)
+		copy(word, b)
+		return word, nil
+	}
+
+	if codec, ok := lookupType(typ); ok {
+		return encodeCustom(codec, typ, value)
+	}
+
+	return nil, fmt.Errorf("unsupported type: %s", typ)
+}
+
+// encodeDynamic encodes a length-prefixed value padded to a whole number of words
+func encodeDynamic(typ string, value interface{}) ([]byte, error) {
+	var content []byte
+	switch typ {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", value)
+		}
+		content = []byte(s)
+	case "bytes":
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		content = b
+	default:
+		return nil, fmt.Errorf("unsupported type: %s", typ)
+	}
+
+	paddedLen := (len(content) + wordSize - 1) / wordSize * wordSize
+	encoded := make([]byte, wordSize+paddedLen)
+	copy(encoded, encodeSize(len(content)))
+	copy(encoded[wordSize:], content)
+	return encoded, nil
+}
+
+// encodeInt encodes an intN or uintN value after checking it fits the declared width
+func encodeInt(value *big.Int, signed bool, bits int) ([]byte, error) {
+	if !signed {
+		if value.Sign() < 0 || value.BitLen() > bits {
+			return nil, fmt.Errorf("value %s out of range for uint%d", value, bits)
+		}
+		return leftPad(value.Bytes()), nil
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	if value.Cmp(limit) >= 0 || value.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("value %s out of range for int%d", value, bits)
+	}
+
+	// Negative values are encoded as their 256-bit two's complement
+	v := new(big.Int).Set(value)
+	if v.Sign() < 0 {
+		v.Add(v, new(big.Int).Lsh(big.NewInt(1), wordSize*8))
+	}
+	return leftPad(v.Bytes()), nil
+}
+
+// encodeSize encodes an offset or length as a word
+func encodeSize(size int) []byte {
+	word := make([]byte, wordSize)
+	binary.BigEndian.PutUint64(word[wordSize-8:], uint64(size))
+	return word
+}
+
+// leftPad right aligns b in a word
+func leftPad(b []byte) []byte {
+	word := make([]byte, wordSize)
+	copy(word[wordSize-len(b):], b)
+	return word
+}
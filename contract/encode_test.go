@@ -0,0 +1,598 @@
+package contract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode_Return(t *testing.T) {
+	// Matches the canned name() result of a real token
+	ret, err := EncodeReturn([]abi.ABIParameter{{Type: "string"}}, []interface{}{"USD Coin"})
+	require.NoError(t, err)
+	assert.Equal(t, erc20Results["06fdde03"], ret)
+
+	ret, err = EncodeReturn([]abi.ABIParameter{{Type: "uint8"}}, []interface{}{big.NewInt(6)})
+	require.NoError(t, err)
+	assert.Equal(t, erc20Results["313ce567"], ret)
+
+	// Out of range for the declared width
+	_, err = EncodeReturn([]abi.ABIParameter{{Type: "uint8"}}, []interface{}{big.NewInt(256)})
+	assert.Error(t, err)
+
+	_, err = EncodeReturn([]abi.ABIParameter{{Type: "uint256"}}, []interface{}{"1"})
+	assert.Error(t, err)
+
+	_, err = EncodeReturn([]abi.ABIParameter{{Type: "uint256"}}, []interface{}{})
+	assert.Error(t, err)
+}
+
+func TestEncode_ReturnRoundTrip(t *testing.T) {
+	outputs := []abi.ABIParameter{
+		{Name: "amount", Type: "uint256"},
+		{Name: "label", Type: "string"},
+		{Name: "owner", Type: "address"},
+		{Name: "delta", Type: "int128"},
+		{Name: "payload", Type: "bytes"},
+		{Name: "active", Type: "bool"},
+		{Name: "role", Type: "bytes32"},
+	}
+	role := make([]byte, 32)
+	role[0] = 0xab
+	values := []interface{}{
+		big.NewInt(1000),
+		"first",
+		"0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		big.NewInt(-42),
+		[]byte(strings.Repeat("x", 40)),
+		true,
+		role,
+	}
+
+	ret, err := EncodeReturn(outputs, values)
+	require.NoError(t, err)
+
+	data, err := hex.DecodeString(strings.TrimPrefix(ret, "0x"))
+	require.NoError(t, err)
+
+	// Head of 7 words, then "first" and the 40 bytes payload in the tail
+	require.Len(t, data, 7*32+2*32+3*32)
+	assert.Equal(t, encodeSize(7*32), data[32:64])
+	assert.Equal(t, encodeSize(9*32), data[4*32:5*32])
+
+	decoded, err := (&decoder{}).decodeValues(outputs, data)
+	require.NoError(t, err)
+	assert.Equal(t, values, decoded)
+}
+
+func TestEncode_AddressPayable(t *testing.T) {
+	owner := "0x807a96288a1a408dbc13de2b1d087d10356395d2"
+	outputs := []abi.ABIParameter{{Name: "owner", Type: "address payable"}}
+
+	ret, err := EncodeReturn(outputs, []interface{}{owner})
+	require.NoError(t, err)
+	assert.Equal(t, "0x000000000000000000000000"+owner[2:], ret)
+
+	data, err := hex.DecodeString(strings.TrimPrefix(ret, "0x"))
+	require.NoError(t, err)
+	values, err := (&decoder{}).decodeValues(outputs, data)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{owner}, values)
+
+	// Inputs are validated and encoded as a plain address
+	c := &contractClient{}
+	owners := abi.ContractABI{
+		Name:   "isOwner",
+		Type:   "function",
+		Inputs: []abi.ABIParameter{{Name: "account", Type: "address payable"}},
+	}
+	args := map[string]interface{}{"account": owner}
+	require.NoError(t, c.validateInputs(owners, args))
+
+	callData, err := c.encodeData(owners, args)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(callData, owner[2:]))
+}
+
+func TestEncode_SignedArrays(t *testing.T) {
+	// int256[] {-5, 0, 5}: offset, length and two's complement elements
+	expected := "0x" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb" +
+		"0000000000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000005"
+
+	outputs := []abi.ABIParameter{{Name: "series", Type: "int256[]"}}
+	series := []*big.Int{big.NewInt(-5), big.NewInt(0), big.NewInt(5)}
+	ret, err := EncodeReturn(outputs, []interface{}{series})
+	require.NoError(t, err)
+	assert.Equal(t, expected, ret)
+
+	data, err := hex.DecodeString(strings.TrimPrefix(expected, "0x"))
+	require.NoError(t, err)
+	values, err := (&decoder{}).decodeValues(outputs, data)
+	require.NoError(t, err)
+	// Compared as text since a decoded zero differs from big.NewInt(0) in its internal representation
+	assert.Equal(t, "[[-5 0 5]]", fmt.Sprint(values))
+
+	// Elements are checked against the width of the element type
+	outputs = []abi.ABIParameter{
+		{Name: "fixed", Type: "int128[2]"},
+		{Name: "labels", Type: "string[]"},
+		{Name: "last", Type: "int8"},
+	}
+	in := []interface{}{
+		[]interface{}{big.NewInt(-170141183460469231), big.NewInt(42)},
+		[]string{"up", "down"},
+		big.NewInt(-128),
+	}
+	ret, err = EncodeReturn(outputs, in)
+	require.NoError(t, err)
+
+	data, err = hex.DecodeString(strings.TrimPrefix(ret, "0x"))
+	require.NoError(t, err)
+	values, err = (&decoder{}).decodeValues(outputs, data)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		[]interface{}{big.NewInt(-170141183460469231), big.NewInt(42)},
+		[]interface{}{"up", "down"},
+		big.NewInt(-128),
+	}, values)
+
+	// The sign is handled per element type: -5 fits int8 but is out of range for uint8
+	data, err = hex.DecodeString(strings.TrimPrefix(expected, "0x"))
+	require.NoError(t, err)
+	values, err = (&decoder{}).decodeValues([]abi.ABIParameter{{Type: "int8[]"}}, data)
+	require.NoError(t, err)
+	assert.Equal(t, "[-5 0 5]", fmt.Sprint(values[0]))
+
+	_, err = (&decoder{}).decodeValues([]abi.ABIParameter{{Type: "uint8[]"}}, data)
+	assert.ErrorContains(t, err, "element 0")
+}
+
+func TestEncode_TupleStruct(t *testing.T) {
+	// exactInputSingle of the Uniswap V3 router
+	exactInputSingle := abi.ContractABI{
+		Type: "function",
+		Name: "exactInputSingle",
+		Inputs: []abi.ABIParameter{{
+			Name: "params",
+			Type: "tuple",
+			Components: []abi.ABIParameter{
+				{Name: "tokenIn", Type: "address"},
+				{Name: "tokenOut", Type: "address"},
+				{Name: "fee", Type: "uint24"},
+				{Name: "recipient", Type: "address"},
+				{Name: "deadline", Type: "uint256"},
+				{Name: "amountIn", Type: "uint256"},
+				{Name: "amountOutMinimum", Type: "uint256"},
+				{Name: "sqrtPriceLimitX96", Type: "uint160"},
+			},
+		}},
+	}
+
+	params := map[string]interface{}{
+		"tokenIn":           "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270",
+		"tokenOut":          "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174",
+		"fee":               big.NewInt(500),
+		"recipient":         "0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214",
+		"deadline":          big.NewInt(1700000000),
+		"amountIn":          big.NewInt(1000000),
+		"amountOutMinimum":  big.NewInt(0),
+		"sqrtPriceLimitX96": big.NewInt(0),
+	}
+
+	// Tagged fields are matched by name, so their order doesn't matter
+	type taggedParams struct {
+		Fee               *big.Int `abi:"fee"`
+		TokenIn           string   `abi:"tokenIn"`
+		TokenOut          string   `abi:"tokenOut"`
+		Recipient         string   `abi:"recipient"`
+		Deadline          *big.Int `abi:"deadline"`
+		AmountIn          *big.Int `abi:"amountIn"`
+		AmountOutMinimum  *big.Int `abi:"amountOutMinimum"`
+		SqrtPriceLimitX96 *big.Int `abi:"sqrtPriceLimitX96"`
+		Note              string
+	}
+	// Untagged fields are taken in order
+	type positionalParams struct {
+		TokenIn, TokenOut          string
+		Fee                        *big.Int
+		Recipient                  string
+		Deadline, AmountIn         *big.Int
+		AmountOutMinimum, PriceLim *big.Int
+		internal                   int
+	}
+
+	cli := &contractClient{}
+	want, err := cli.encodeData(exactInputSingle, map[string]interface{}{"params": params})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(want, "0x414bf389"))
+	assert.Len(t, want, 2+8+8*64)
+
+	tagged := taggedParams{
+		Fee:               big.NewInt(500),
+		TokenIn:           "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270",
+		TokenOut:          "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174",
+		Recipient:         "0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214",
+		Deadline:          big.NewInt(1700000000),
+		AmountIn:          big.NewInt(1000000),
+		AmountOutMinimum:  big.NewInt(0),
+		SqrtPriceLimitX96: big.NewInt(0),
+		Note:              "ignored",
+	}
+	positional := positionalParams{
+		TokenIn:          tagged.TokenIn,
+		TokenOut:         tagged.TokenOut,
+		Fee:              tagged.Fee,
+		Recipient:        tagged.Recipient,
+		Deadline:         tagged.Deadline,
+		AmountIn:         tagged.AmountIn,
+		AmountOutMinimum: tagged.AmountOutMinimum,
+		PriceLim:         tagged.SqrtPriceLimitX96,
+	}
+
+	for _, arg := range []interface{}{tagged, &tagged, positional} {
+		args := map[string]interface{}{"params": arg}
+		require.NoError(t, cli.validateInputs(exactInputSingle, args))
+		got, err := cli.encodeData(exactInputSingle, args)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	// Field types must match the component types
+	type wrongType struct {
+		TokenIn, TokenOut string
+		Fee               int
+		Recipient         string
+		A, B, C, D        *big.Int
+	}
+	wrong := wrongType{TokenIn: tagged.TokenIn, TokenOut: tagged.TokenOut, Fee: 500}
+	err = cli.validateInputs(exactInputSingle, map[string]interface{}{"params": wrong})
+	assert.ErrorContains(t, err, "expected *big.Int, got int")
+
+	type missingTag struct {
+		TokenIn string `abi:"tokenIn"`
+	}
+	err = cli.validateInputs(exactInputSingle, map[string]interface{}{"params": missingTag{}})
+	assert.ErrorContains(t, err, `missing tuple component "tokenOut"`)
+
+	type tooFewFields struct {
+		TokenIn, TokenOut string
+	}
+	err = cli.validateInputs(exactInputSingle, map[string]interface{}{"params": tooFewFields{}})
+	assert.ErrorContains(t, err, "8 components")
+}
+
+func TestEncode_DynamicTuple(t *testing.T) {
+	// exactInput of the Uniswap V3 router, whose params hold the bytes swap path
+	exactInput := abi.ContractABI{
+		Type: "function",
+		Name: "exactInput",
+		Inputs: []abi.ABIParameter{{
+			Name: "params",
+			Type: "tuple",
+			Components: []abi.ABIParameter{
+				{Name: "path", Type: "bytes"},
+				{Name: "recipient", Type: "address"},
+				{Name: "deadline", Type: "uint256"},
+				{Name: "amountIn", Type: "uint256"},
+				{Name: "amountOutMinimum", Type: "uint256"},
+			},
+		}},
+	}
+
+	// WMATIC, fee 500, USDC
+	path := "0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270" + "0001f4" + "2791bca1f2de4661ed88a30c99a7a9449aa84174"
+	args := map[string]interface{}{"params": map[string]interface{}{
+		"path":             path,
+		"recipient":        "0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214",
+		"deadline":         big.NewInt(1700000000),
+		"amountIn":         big.NewInt(1000000),
+		"amountOutMinimum": big.NewInt(0),
+	}}
+
+	cli := &contractClient{}
+	require.NoError(t, cli.validateInputs(exactInput, args))
+	data, err := cli.encodeData(exactInput, args)
+	require.NoError(t, err)
+	assert.Equal(t, "0xc04b8d59"+
+		// Offset of the tuple, then the offset of path within the tuple
+		"0000000000000000000000000000000000000000000000000000000000000020"+
+		"00000000000000000000000000000000000000000000000000000000000000a0"+
+		"00000000000000000000000017f935d9b5e73c63b1cec73f97dd988c5e2d9214"+
+		"000000000000000000000000000000000000000000000000000000006553f100"+
+		"00000000000000000000000000000000000000000000000000000000000f4240"+
+		"0000000000000000000000000000000000000000000000000000000000000000"+
+		"000000000000000000000000000000000000000000000000000000000000002b"+
+		"0d500b1d8e8ef31e21c99d1db9a6444d3adf12700001f42791bca1f2de4661ed"+
+		"88a30c99a7a9449aa84174000000000000000000000000000000000000000000", data)
+
+	// Dynamic components are still checked against their types
+	bad := map[string]interface{}{"params": []interface{}{
+		42, "0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214", big.NewInt(0), big.NewInt(0), big.NewInt(0),
+	}}
+	assert.Error(t, cli.validateInputs(exactInput, bad))
+}
+
+func TestEncode_DynamicArguments(t *testing.T) {
+	cli := &contractClient{}
+
+	// Reference calldata produced by go-ethereum's abi.Pack
+	foo := abi.ContractABI{
+		Type: "function",
+		Name: "foo",
+		Inputs: []abi.ABIParameter{
+			{Name: "a", Type: "string"},
+			{Name: "b", Type: "string"},
+		},
+	}
+	data, err := cli.encodeData(foo, map[string]interface{}{"a": "hello", "b": "world"})
+	require.NoError(t, err)
+	assert.Equal(t, "0x124a83fa"+
+		"0000000000000000000000000000000000000000000000000000000000000040"+
+		"0000000000000000000000000000000000000000000000000000000000000080"+
+		"0000000000000000000000000000000000000000000000000000000000000005"+
+		"68656c6c6f000000000000000000000000000000000000000000000000000000"+
+		"0000000000000000000000000000000000000000000000000000000000000005"+
+		"776f726c64000000000000000000000000000000000000000000000000000000", data)
+
+	// The second string starts after the two words of the first
+	bar := abi.ContractABI{
+		Type: "function",
+		Name: "bar",
+		Inputs: []abi.ABIParameter{
+			{Name: "n", Type: "uint256"},
+			{Name: "a", Type: "string"},
+			{Name: "b", Type: "string"},
+		},
+	}
+	data, err = cli.encodeData(bar, map[string]interface{}{
+		"n": big.NewInt(7),
+		"a": "hello, world and then some more than thirty two bytes",
+		"b": "x",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "0x1e80f4bd"+
+		"0000000000000000000000000000000000000000000000000000000000000007"+
+		"0000000000000000000000000000000000000000000000000000000000000060"+
+		"00000000000000000000000000000000000000000000000000000000000000c0"+
+		"0000000000000000000000000000000000000000000000000000000000000035"+
+		"68656c6c6f2c20776f726c6420616e64207468656e20736f6d65206d6f726520"+
+		"7468616e207468697274792074776f2062797465730000000000000000000000"+
+		"0000000000000000000000000000000000000000000000000000000000000001"+
+		"7800000000000000000000000000000000000000000000000000000000000000", data)
+}
+
+func BenchmarkEncodeData(b *testing.B) {
+	// transferWithMemo(address to, uint256 amount, bool flag, bytes32 ref, string memo)
+	fn := abi.ContractABI{
+		Type: "function",
+		Name: "transferWithMemo",
+		Inputs: []abi.ABIParameter{
+			{Name: "to", Type: "address"},
+			{Name: "amount", Type: "uint256"},
+			{Name: "flag", Type: "bool"},
+			{Name: "ref", Type: "bytes32"},
+			{Name: "memo", Type: "string"},
+		},
+	}
+	args := map[string]interface{}{
+		"to":     "0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214",
+		"amount": big.NewInt(1000000000000000000),
+		"flag":   true,
+		"ref":    "0xab",
+		"memo":   strings.Repeat("memo", 50),
+	}
+
+	cli := &contractClient{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := cli.encodeData(fn, args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeReturnArray(b *testing.B) {
+	outputs := []abi.ABIParameter{{Type: "uint256[]"}, {Type: "string[]"}}
+	amounts := make([]interface{}, 100)
+	labels := make([]interface{}, 100)
+	for i := range amounts {
+		amounts[i] = big.NewInt(int64(i) * 1000000)
+		labels[i] = fmt.Sprintf("label-%d", i)
+	}
+	values := []interface{}{amounts, labels}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodeReturn(outputs, values); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncode_ArrayArguments(t *testing.T) {
+	cli := &contractClient{}
+
+	// A dynamic array is its length followed by the elements, in the tail after the offset
+	foo := abi.ContractABI{
+		Type:   "function",
+		Name:   "foo",
+		Inputs: []abi.ABIParameter{{Name: "values", Type: "uint256[]"}},
+	}
+	args := map[string]interface{}{"values": []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}}
+	require.NoError(t, cli.validateInputs(foo, args))
+	data, err := cli.encodeData(foo, args)
+	require.NoError(t, err)
+	methodID, err := foo.MethodID()
+	require.NoError(t, err)
+	assert.Equal(t, "0x"+methodID+
+		"0000000000000000000000000000000000000000000000000000000000000020"+
+		"0000000000000000000000000000000000000000000000000000000000000003"+
+		"0000000000000000000000000000000000000000000000000000000000000001"+
+		"0000000000000000000000000000000000000000000000000000000000000002"+
+		"0000000000000000000000000000000000000000000000000000000000000003", data)
+
+	// A fixed array is inlined in the head
+	fixed := abi.ContractABI{
+		Type: "function",
+		Name: "fixed",
+		Inputs: []abi.ABIParameter{
+			{Name: "pair", Type: "address[2]"},
+			{Name: "flag", Type: "bool"},
+		},
+	}
+	args = map[string]interface{}{
+		"pair": []string{"0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"},
+		"flag": true,
+	}
+	require.NoError(t, cli.validateInputs(fixed, args))
+	data, err = cli.encodeData(fixed, args)
+	require.NoError(t, err)
+	assert.Equal(t, 2+8+3*64, len(data))
+	assert.Equal(t, "0000000000000000000000000d500b1d8e8ef31e21c99d1db9a6444d3adf1270", data[10:74])
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000001", data[138:])
+
+	// ERC-1155 balanceOfBatch(address[],uint256[]) takes two dynamic arrays
+	balanceOfBatch := abi.ContractABI{
+		Type: "function",
+		Name: "balanceOfBatch",
+		Inputs: []abi.ABIParameter{
+			{Name: "accounts", Type: "address[]"},
+			{Name: "ids", Type: "uint256[]"},
+		},
+		Outputs: []abi.ABIParameter{{Type: "uint256[]"}},
+	}
+	args = map[string]interface{}{
+		"accounts": []interface{}{"0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270"},
+		"ids":      []interface{}{big.NewInt(5)},
+	}
+	require.NoError(t, cli.validateInputs(balanceOfBatch, args))
+	data, err = cli.encodeData(balanceOfBatch, args)
+	require.NoError(t, err)
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000040", data[10:74])
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000080", data[74:138])
+
+	// Elements of the wrong type, a wrong length or a non-slice are rejected
+	for _, tc := range []struct {
+		fn   abi.ContractABI
+		args map[string]interface{}
+	}{
+		{foo, map[string]interface{}{"values": []int{1, 2, 3}}},
+		{foo, map[string]interface{}{"values": big.NewInt(1)}},
+		{fixed, map[string]interface{}{"pair": []string{"0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270"}, "flag": true}},
+		{fixed, map[string]interface{}{"pair": []string{"0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", "0x12"}, "flag": true}},
+	} {
+		assert.Error(t, cli.validateInputs(tc.fn, tc.args))
+	}
+}
+
+func TestEncode_IntWidths(t *testing.T) {
+	fn := abi.ContractABI{
+		Type: "function",
+		Name: "configure",
+		Inputs: []abi.ABIParameter{
+			{Name: "decimals", Type: "uint8"},
+			{Name: "deadline", Type: "uint64"},
+			{Name: "tick", Type: "int24"},
+			{Name: "amount", Type: "uint"},
+		},
+	}
+	methodID, err := fn.MethodID()
+	require.NoError(t, err)
+
+	one, err := NewUint256(big.NewInt(1))
+	require.NoError(t, err)
+
+	cli := &contractClient{}
+	args := map[string]interface{}{
+		"decimals": big.NewInt(255),
+		"deadline": new(big.Int).SetUint64(math.MaxUint64),
+		"tick":     big.NewInt(-887272),
+		"amount":   one,
+	}
+	require.NoError(t, cli.validateInputs(fn, args))
+	data, err := cli.encodeData(fn, args)
+	require.NoError(t, err)
+	assert.Equal(t, "0x"+methodID+
+		"00000000000000000000000000000000000000000000000000000000000000ff"+
+		"000000000000000000000000000000000000000000000000ffffffffffffffff"+
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffff27618"+
+		"0000000000000000000000000000000000000000000000000000000000000001", data)
+
+	// Values must fit the declared width
+	tests := []struct {
+		input    string
+		value    interface{}
+		expected string
+	}{
+		{"decimals", big.NewInt(256), "value 256 out of range for uint8"},
+		{"decimals", big.NewInt(-1), "value -1 out of range for uint8"},
+		{"deadline", new(big.Int).Lsh(big.NewInt(1), 64), "out of range for uint64"},
+		{"tick", big.NewInt(1 << 23), "value 8388608 out of range for int24"},
+		{"decimals", uint8(8), "expected *big.Int, got uint8"},
+		{"deadline", (*big.Int)(nil), "nil *big.Int"},
+	}
+	for _, test := range tests {
+		invalid := make(map[string]interface{}, len(args))
+		for k, v := range args {
+			invalid[k] = v
+		}
+		invalid[test.input] = test.value
+		err := cli.validateInputs(fn, invalid)
+		assert.ErrorContains(t, err, fmt.Sprintf("input %q", test.input))
+		assert.ErrorContains(t, err, test.expected)
+	}
+}
+
+func TestEncode_NilBigInt(t *testing.T) {
+	// A typed nil *big.Int fails like any invalid value instead of panicking
+	_, err := EncodeReturn([]abi.ABIParameter{{Type: "uint256[]"}}, []interface{}{[]*big.Int{nil}})
+	assert.ErrorContains(t, err, "nil *big.Int for uint256")
+
+	_, err = EncodeReturn([]abi.ABIParameter{{Type: "int64"}}, []interface{}{(*big.Int)(nil)})
+	assert.ErrorContains(t, err, "nil *big.Int for int64")
+
+	// So do unset fields of struct and tuple inputs
+	fn := abi.ContractABI{
+		Type: "function",
+		Name: "setAccount",
+		Inputs: []abi.ABIParameter{{
+			Name: "account",
+			Type: "tuple",
+			Components: []abi.ABIParameter{
+				{Name: "id", Type: "uint256"},
+				{Name: "balance", Type: "uint128"},
+			},
+		}},
+	}
+	type account struct {
+		ID      *big.Int `abi:"id"`
+		Balance *big.Int `abi:"balance"`
+	}
+	cli := &contractClient{}
+	for _, arg := range []interface{}{
+		account{ID: big.NewInt(1)},
+		map[string]interface{}{"id": big.NewInt(1), "balance": (*big.Int)(nil)},
+	} {
+		args := map[string]interface{}{"account": arg}
+		err := cli.validateInputs(fn, args)
+		assert.ErrorContains(t, err, "nil *big.Int for uint128")
+		_, err = cli.encodeData(fn, args)
+		assert.ErrorContains(t, err, "nil *big.Int for uint128")
+	}
+
+	// An unset Uint256 is zero
+	encoded, err := EncodeReturn([]abi.ABIParameter{{Type: "uint256"}}, []interface{}{Uint256{}})
+	require.NoError(t, err)
+	assert.Equal(t, "0x"+strings.Repeat("0", 64), encoded)
+}
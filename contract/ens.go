@@ -0,0 +1,132 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// ENSRegistryAddress is the address the ENS registry is deployed at on Ethereum mainnet and its testnets
+const ENSRegistryAddress = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+// ErrNameNotResolved is returned when an ENS name has no resolver or its resolver has no address for it
+var ErrNameNotResolved = errors.New("ENS name not resolved")
+
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// ENS function ABIs, for the registry's resolver(bytes32) and the resolver's addr(bytes32)
+var (
+	ensResolverABI = abi.ContractABI{
+		Name:            "resolver",
+		Type:            "function",
+		StateMutability: "view",
+		Inputs:          []abi.ABIParameter{{Name: "node", Type: "bytes32"}},
+		Outputs:         []abi.ABIParameter{{Type: "address"}},
+	}
+	ensAddrABI = abi.ContractABI{
+		Name:            "addr",
+		Type:            "function",
+		StateMutability: "view",
+		Inputs:          []abi.ABIParameter{{Name: "node", Type: "bytes32"}},
+		Outputs:         []abi.ABIParameter{{Type: "address"}},
+	}
+)
+
+// ResolveName resolves an ENS name, e.g. "uniswap.eth", to the checksummed address it points to,
+// by looking up the resolver of the name in the registry and querying its address from the resolver.
+// Names are only lowercased, not fully UTS-46 normalized. Lookups go to the endpoint set by WithENS, if any.
+// Hex addresses are returned unchanged.
+func (c *contractClient) ResolveName(ctx context.Context, name string) (string, error) {
+	if ValidateAddress(name) == nil {
+		return name, nil
+	}
+	if name == "" || !strings.Contains(name, ".") {
+		return "", fmt.Errorf("invalid ENS name %q", name)
+	}
+
+	ens := c
+	if c.ens != nil {
+		ens = c.ens
+	}
+
+	node := namehash(name)
+	args := map[string]interface{}{"node": node}
+	resolver, err := ens.ReadContract(ctx, ENSRegistryAddress, ensResolverABI, args)
+	if err != nil {
+		return "", fmt.Errorf("failed to get resolver of %s: %w", name, err)
+	}
+	if strings.EqualFold(resolver, zeroAddress) {
+		return "", fmt.Errorf("%w: %s has no resolver", ErrNameNotResolved, name)
+	}
+
+	addr, err := ens.ReadContract(ctx, resolver, ensAddrABI, args)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", name, err)
+	}
+	if strings.EqualFold(addr, zeroAddress) {
+		return "", fmt.Errorf("%w: %s has no address", ErrNameNotResolved, name)
+	}
+	return addr, nil
+}
+
+// namehash returns the EIP-137 namehash of name, hashing its labels from the top-level domain down
+func namehash(name string) []byte {
+	node := make([]byte, 32)
+	if name == "" {
+		return node
+	}
+
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = abi.Keccak256(append(node, abi.Keccak256([]byte(labels[i]))...))
+	}
+	return node
+}
+
+// resolveNames resolves the contract address and the address arguments of a read given as ENS names.
+// args is copied when any of them is replaced, leaving the caller's map untouched.
+func (c *contractClient) resolveNames(ctx context.Context, addr string, fn abi.ContractABI, args map[string]interface{}) (string, map[string]interface{}, error) {
+	addr, err := c.resolveIfName(ctx, addr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resolved, copied := args, false
+	for _, input := range fn.Inputs {
+		if canonicalType(input.Type) != "address" {
+			continue
+		}
+		name, ok := args[input.Name].(string)
+		if !ok {
+			continue
+		}
+		value, err := c.resolveIfName(ctx, name)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid value for input %q: %w", input.Name, err)
+		}
+		if value == name {
+			continue
+		}
+
+		if !copied {
+			resolved = make(map[string]interface{}, len(args))
+			for k, v := range args {
+				resolved[k] = v
+			}
+			copied = true
+		}
+		resolved[input.Name] = value
+	}
+	return addr, resolved, nil
+}
+
+// resolveIfName resolves s when it is an ENS name, i.e. not a hex address but a dotted name
+func (c *contractClient) resolveIfName(ctx context.Context, s string) (string, error) {
+	if ValidateAddress(s) == nil || !strings.Contains(s, ".") {
+		return s, nil
+	}
+	return c.ResolveName(ctx, s)
+}
@@ -0,0 +1,139 @@
+package contract
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestENS_Namehash(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"", "0000000000000000000000000000000000000000000000000000000000000000"},
+		{"eth", "93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"},
+		{"foo.eth", "de9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"},
+		{"Foo.ETH", "de9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, hex.EncodeToString(namehash(test.name)), test.name)
+	}
+}
+
+// registerENSResponder answers the registry and resolver lookups of names with their addresses,
+// and every other eth_call with the balance 42. It returns the eth_call parameters it was sent.
+func registerENSResponder(t *testing.T, url string, names map[string]string) *[]map[string]interface{} {
+	const resolver = "0x4976fb03c32e5b8cfe2b6ccb31c09ba78ebaba41"
+	nodes := make(map[string]string, len(names))
+	for name, addr := range names {
+		nodes[hex.EncodeToString(namehash(name))] = addr
+	}
+
+	var calls []map[string]interface{}
+	httpmock.RegisterResponder(http.MethodPost, url,
+		func(req *http.Request) (*http.Response, error) {
+			var rpcReq rpcRequest
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			call := rpcReq.Params[0].(map[string]interface{})
+			calls = append(calls, call)
+
+			to, data := strings.ToLower(call["to"].(string)), call["data"].(string)
+			result := "0x000000000000000000000000000000000000000000000000000000000000002a"
+			switch {
+			case to == strings.ToLower(ENSRegistryAddress):
+				require.True(t, strings.HasPrefix(data, "0x0178b8bf"))
+				addr := "0x0000000000000000000000000000000000000000"
+				if _, ok := nodes[data[10:]]; ok {
+					addr = resolver
+				}
+				result = "0x" + strings.Repeat("0", 24) + addr[2:]
+			case to == resolver:
+				require.True(t, strings.HasPrefix(data, "0x3b3b57de"))
+				result = "0x" + strings.Repeat("0", 24) + strings.ToLower(nodes[data[10:]][2:])
+			}
+			return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"`+result+`"}`), nil
+		})
+	return &calls
+}
+
+func TestENS_ResolveName(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerENSResponder(t, testRPCURL, map[string]string{
+		"wmatic.eth": testWMATICAddr,
+		"nobody.eth": zeroAddress,
+	})
+
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+	addr, err := cli.ResolveName(ctx, "WMATIC.eth")
+	require.NoError(t, err)
+	assert.Equal(t, "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", addr)
+
+	// Hex addresses are not looked up
+	httpmock.ZeroCallCounters()
+	addr, err = cli.ResolveName(ctx, testWMATICAddr)
+	require.NoError(t, err)
+	assert.Equal(t, testWMATICAddr, addr)
+	assert.Zero(t, httpmock.GetTotalCallCount())
+
+	_, err = cli.ResolveName(ctx, "unknown.eth")
+	assert.ErrorIs(t, err, ErrNameNotResolved)
+	_, err = cli.ResolveName(ctx, "nobody.eth")
+	assert.ErrorIs(t, err, ErrNameNotResolved)
+	_, err = cli.ResolveName(ctx, "wmatic")
+	assert.Error(t, err)
+}
+
+func TestENS_Read(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	const holder = "0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214"
+	calls := registerENSResponder(t, testRPCURL, map[string]string{
+		"wmatic.eth": testWMATICAddr,
+		"alice.eth":  holder,
+	})
+
+	contractABIs := loadFixtureABIs(t)
+	balanceOf, err := contractABIs.Find("balanceOf")
+	require.NoError(t, err)
+
+	// The contract and the address argument are both resolved before the read
+	ctx := context.Background()
+	args := map[string]interface{}{"": "alice.eth"}
+	balance, err := NewClient(testRPCURL, WithENS("")).ReadContract(ctx, "wmatic.eth", *balanceOf, args)
+	require.NoError(t, err)
+	assert.Equal(t, "42", balance)
+	assert.Equal(t, map[string]interface{}{"": "alice.eth"}, args)
+
+	read := (*calls)[len(*calls)-1]
+	assert.Equal(t, testWMATICAddr, read["to"])
+	assert.Equal(t, "0x70a08231"+strings.Repeat("0", 24)+strings.ToLower(holder[2:]), read["data"])
+
+	// Without WithENS names are rejected like any invalid address
+	_, err = NewClient(testRPCURL).ReadContract(ctx, testWMATICAddr, *balanceOf, args)
+	assert.Error(t, err)
+
+	_, err = NewClient(testRPCURL, WithENS("")).ReadContract(ctx, testWMATICAddr, *balanceOf, map[string]interface{}{"": "bob.eth"})
+	assert.ErrorIs(t, err, ErrNameNotResolved)
+
+	// Names may be resolved on another chain than the one read
+	const mainnetURL = "https://mainnet.example.com"
+	mainnetCalls := registerENSResponder(t, mainnetURL, map[string]string{"alice.eth": holder})
+	*calls = nil
+	_, err = NewClient(testRPCURL, WithENS(mainnetURL)).ReadContract(ctx, testWMATICAddr, *balanceOf, args)
+	require.NoError(t, err)
+	assert.Len(t, *mainnetCalls, 2)
+	assert.Len(t, *calls, 1)
+}
@@ -0,0 +1,111 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// ERC-20 function ABIs, so reading standard tokens doesn't require fetching their ABI
+var (
+	erc20NameABI = abi.ContractABI{
+		Name:            "name",
+		Type:            "function",
+		StateMutability: "view",
+		Outputs:         []abi.ABIParameter{{Type: "string"}},
+	}
+	erc20SymbolABI = abi.ContractABI{
+		Name:            "symbol",
+		Type:            "function",
+		StateMutability: "view",
+		Outputs:         []abi.ABIParameter{{Type: "string"}},
+	}
+	erc20DecimalsABI = abi.ContractABI{
+		Name:            "decimals",
+		Type:            "function",
+		StateMutability: "view",
+		Outputs:         []abi.ABIParameter{{Type: "uint8"}},
+	}
+	erc20TotalSupplyABI = abi.ContractABI{
+		Name:            "totalSupply",
+		Type:            "function",
+		StateMutability: "view",
+		Outputs:         []abi.ABIParameter{{Type: "uint256"}},
+	}
+	erc20BalanceOfABI = abi.ContractABI{
+		Name:            "balanceOf",
+		Type:            "function",
+		StateMutability: "view",
+		Inputs:          []abi.ABIParameter{{Name: "account", Type: "address"}},
+		Outputs:         []abi.ABIParameter{{Type: "uint256"}},
+	}
+)
+
+// ERC20 reads the standard fields of an ERC-20 token contract
+type ERC20 struct {
+	client  ContractClient
+	address string
+}
+
+// Name returns the name of the token
+func (e *ERC20) Name(ctx context.Context) (string, error) {
+	return e.client.ReadContract(ctx, e.address, erc20NameABI, map[string]interface{}{})
+}
+
+// Symbol returns the symbol of the token
+func (e *ERC20) Symbol(ctx context.Context) (string, error) {
+	return e.client.ReadContract(ctx, e.address, erc20SymbolABI, map[string]interface{}{})
+}
+
+// Decimals returns the number of decimals used to display token amounts
+func (e *ERC20) Decimals(ctx context.Context) (uint8, error) {
+	ret, err := e.client.ReadContract(ctx, e.address, erc20DecimalsABI, map[string]interface{}{})
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := parseBigInt(ret)
+	if err != nil {
+		return 0, err
+	}
+	if !value.IsUint64() || value.Uint64() > 255 {
+		return 0, fmt.Errorf("decimals out of range: %s", ret)
+	}
+	return uint8(value.Uint64()), nil
+}
+
+// TotalSupply returns the total supply of the token in base units
+func (e *ERC20) TotalSupply(ctx context.Context) (*big.Int, error) {
+	ret, err := e.client.ReadContract(ctx, e.address, erc20TotalSupplyABI, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	return parseBigInt(ret)
+}
+
+// BalanceOf returns the token balance of holder in base units
+func (e *ERC20) BalanceOf(ctx context.Context, holder string) (*big.Int, error) {
+	ret, err := e.client.ReadContract(ctx, e.address, erc20BalanceOfABI, map[string]interface{}{"account": holder})
+	if err != nil {
+		return nil, err
+	}
+	return parseBigInt(ret)
+}
+
+// NewERC20 creates an ERC-20 reader for the token at address
+func NewERC20(client ContractClient, address string) *ERC20 {
+	return &ERC20{
+		client:  client,
+		address: address,
+	}
+}
+
+func parseBigInt(s string) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer result %q", s)
+	}
+	return value, nil
+}
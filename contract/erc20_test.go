@@ -0,0 +1,134 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTokenAddr  = "0x2791bca1f2de4661ed88a30c99a7a9449aa84174"
+	testHolderAddr = "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214"
+)
+
+// erc20Results are canned eth_call results of a USDC-like token keyed by method ID
+var erc20Results = map[string]string{
+	// name() = "USD Coin"
+	"06fdde03": "0x0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000008" +
+		"55534420436f696e000000000000000000000000000000000000000000000000",
+	// symbol() = "USDC"
+	"95d89b41": "0x0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"5553444300000000000000000000000000000000000000000000000000000000",
+	// decimals() = 6
+	"313ce567": "0x0000000000000000000000000000000000000000000000000000000000000006",
+	// totalSupply() = 2^70
+	"18160ddd": "0x0000000000000000000000000000000000000000000000400000000000000000",
+	// balanceOf(address) = 1500000
+	"70a08231": "0x000000000000000000000000000000000000000000000000000000000016e360",
+}
+
+func TestERC20_Read(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerEthCallResponder(t, erc20Results)
+
+	ctx := context.Background()
+	token := NewERC20(NewClient(testRPCURL), testTokenAddr)
+
+	name, err := token.Name(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "USD Coin", name)
+
+	symbol, err := token.Symbol(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "USDC", symbol)
+
+	decimals, err := token.Decimals(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(6), decimals)
+
+	totalSupply, err := token.TotalSupply(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, new(big.Int).Lsh(big.NewInt(1), 70), totalSupply)
+
+	balance, err := token.BalanceOf(ctx, testHolderAddr)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1500000), balance)
+}
+
+func TestERC20_TokenReader(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := registerEthCallResponder(t, erc20Results)
+
+	ctx := context.Background()
+	reader := NewTokenReader(NewClient(testRPCURL))
+
+	balance, err := reader.BalanceOf(ctx, testTokenAddr, testHolderAddr)
+	require.NoError(t, err)
+	assert.Equal(t, "1.5", balance)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			balance, err := reader.BalanceOf(ctx, testTokenAddr, testHolderAddr)
+			assert.NoError(t, err)
+			assert.Equal(t, "1.5", balance)
+		}()
+	}
+	wg.Wait()
+
+	amount, err := reader.ParseAmount(ctx, testTokenAddr, "2.25")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2250000), amount)
+
+	// USDC has 6 decimals
+	_, err = reader.ParseAmount(ctx, testTokenAddr, "0.0000001")
+	assert.Error(t, err)
+
+	// decimals is fetched once, regardless of address casing
+	_, err = reader.Decimals(ctx, "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174")
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls["313ce567"])
+	assert.Equal(t, 9, calls["70a08231"])
+}
+
+func TestERC20_ReadErrors(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// A decimals value that doesn't fit in uint8, and a reverting balanceOf
+	registerEthCallResponder(t, map[string]string{
+		"313ce567": "0x000000000000000000000000000000000000000000000000000000000000012c",
+	})
+	httpmock.RegisterMatcherResponder(http.MethodPost, testRPCURL, httpmock.BodyContainsString("0x70a08231"),
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted"}}`))
+
+	ctx := context.Background()
+	token := NewERC20(NewClient(testRPCURL), testTokenAddr)
+
+	_, err := token.Decimals(ctx)
+	assert.ErrorContains(t, err, "overflows uint8")
+
+	_, err = token.BalanceOf(ctx, testHolderAddr)
+	assert.ErrorIs(t, err, ErrExecutionReverted)
+
+	// The holder is validated before any call
+	httpmock.ZeroCallCounters()
+	_, err = token.BalanceOf(ctx, "0x1234")
+	assert.Error(t, err)
+	assert.Zero(t, httpmock.GetTotalCallCount())
+}
@@ -0,0 +1,52 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// EstimateGas estimates the gas a transaction from from calling the function of the contract at to would use,
+// via eth_estimateGas. from may be empty to let the node pick the sender.
+// A call which would revert fails like a read does, with a *RevertError, a *PanicError
+// or an error wrapping ErrExecutionReverted.
+func (c *contractClient) EstimateGas(ctx context.Context, from, to string, abi abi.ContractABI, args map[string]interface{}) (uint64, error) {
+	if err := ValidateAddress(to); err != nil {
+		return 0, fmt.Errorf("invalid to address: %w", err)
+	}
+	if from != "" {
+		if err := ValidateAddress(from); err != nil {
+			return 0, fmt.Errorf("invalid from address: %w", err)
+		}
+	}
+	if err := c.validateInputs(abi, args); err != nil {
+		return 0, err
+	}
+	data, err := c.encodeData(abi, args)
+	if err != nil {
+		return 0, err
+	}
+
+	tx := map[string]string{
+		"to":   to,
+		"data": data,
+	}
+	if from != "" {
+		tx["from"] = from
+	}
+
+	result, err := c.call(ctx, "eth_estimateGas", tx)
+	if err != nil {
+		return 0, c.revertError(err, nil)
+	}
+
+	gas, err := decodeQuantity(result)
+	if err != nil {
+		return 0, err
+	}
+	if !gas.IsUint64() {
+		return 0, fmt.Errorf("gas estimate %s overflows uint64", gas)
+	}
+	return gas.Uint64(), nil
+}
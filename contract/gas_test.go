@@ -0,0 +1,72 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGas_EstimateGas(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	const from = "0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214"
+	var rpcReq rpcRequest
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0xb411"}`), nil
+		})
+
+	contractABIs := loadFixtureABIs(t)
+	transfer, err := contractABIs.Find("transfer")
+	require.NoError(t, err)
+	args := map[string]interface{}{
+		"dst": "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174",
+		"wad": big.NewInt(1e18),
+	}
+
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+	gas, err := cli.EstimateGas(ctx, from, testWMATICAddr, *transfer, args)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(46097), gas)
+
+	assert.Equal(t, "eth_estimateGas", rpcReq.Method)
+	require.Len(t, rpcReq.Params, 1)
+	tx := rpcReq.Params[0].(map[string]interface{})
+	assert.Equal(t, from, tx["from"])
+	assert.Equal(t, testWMATICAddr, tx["to"])
+	assert.Equal(t, "0xa9059cbb"+
+		"0000000000000000000000002791bca1f2de4661ed88a30c99a7a9449aa84174"+
+		"0000000000000000000000000000000000000000000000000de0b6b3a7640000", tx["data"])
+
+	// Without a sender the node picks one
+	_, err = cli.EstimateGas(ctx, "", testWMATICAddr, *transfer, args)
+	require.NoError(t, err)
+	assert.NotContains(t, rpcReq.Params[0], "from")
+
+	// Reverts are decoded like those of reads
+	reason, err := EncodeReturn([]abi.ABIParameter{{Type: "string"}}, []interface{}{"insufficient balance"})
+	require.NoError(t, err)
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted: insufficient balance",`+
+			`"data":"0x08c379a0`+reason[2:]+`"}}`))
+	_, err = cli.EstimateGas(ctx, from, testWMATICAddr, *transfer, args)
+	var revertErr *RevertError
+	require.ErrorAs(t, err, &revertErr)
+	assert.Equal(t, "insufficient balance", revertErr.Reason)
+	assert.ErrorIs(t, err, ErrExecutionReverted)
+
+	_, err = cli.EstimateGas(ctx, from, "0x1234", *transfer, args)
+	assert.Error(t, err)
+	_, err = cli.EstimateGas(ctx, from, testWMATICAddr, *transfer, map[string]interface{}{"dst": from})
+	assert.Error(t, err)
+}
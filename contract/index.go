@@ -0,0 +1,117 @@
+package contract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// ProtocolIndex decodes calls and logs across the contracts of a protocol, e.g. for an indexer.
+// Functions and events are looked up in the ABI of the contract the call targets or the log was emitted by,
+// so contracts whose selectors or topics collide don't shadow each other.
+type ProtocolIndex struct {
+	contracts map[string]*indexedContract
+	decoder   *decoder
+}
+
+// indexedContract holds the function and event lookups of a contract
+type indexedContract struct {
+	functions map[string]abi.ContractABI
+	logs      *LogDecoder
+}
+
+// DecodedCall is calldata decoded against a known function
+type DecodedCall struct {
+	Address  string
+	Function string
+	Args     map[string]interface{}
+}
+
+// NewProtocolIndex indexes the functions and events of the ABIs of each contract, keyed by address.
+// Anonymous events have no topic0 and are left out.
+func NewProtocolIndex(abisByAddress map[string]abi.ContractABIs) (*ProtocolIndex, error) {
+	p := &ProtocolIndex{
+		contracts: make(map[string]*indexedContract, len(abisByAddress)),
+		decoder:   &decoder{},
+	}
+
+	for addr, abis := range abisByAddress {
+		key := strings.ToLower(addr)
+		if _, ok := p.contracts[key]; ok {
+			return nil, fmt.Errorf("duplicate contract %s", addr)
+		}
+
+		functions, err := abis.SelectorTable()
+		if err != nil {
+			return nil, fmt.Errorf("failed to index functions of %s: %w", addr, err)
+		}
+
+		var events []abi.ContractABI
+		for _, entry := range abis {
+			if entry.Type == "event" && !entry.Anonymous {
+				events = append(events, entry)
+			}
+		}
+		logs, err := NewLogDecoder(events...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to index events of %s: %w", addr, err)
+		}
+
+		p.contracts[key] = &indexedContract{functions: functions, logs: logs}
+	}
+
+	return p, nil
+}
+
+// DecodeCall decodes calldata sent to addr with the function of that contract matching its selector.
+// Calls to contracts outside the index or to functions their ABI doesn't declare are not an error: they return nil.
+func (p *ProtocolIndex) DecodeCall(addr, data string) (*DecodedCall, error) {
+	contract, ok := p.contracts[strings.ToLower(addr)]
+	if !ok {
+		return nil, nil
+	}
+
+	raw, err := decodeData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode calldata: %w", err)
+	}
+	if len(raw) < 4 {
+		// Plain transfers and fallback calls carry no selector
+		return nil, nil
+	}
+
+	fn, ok := contract.functions[hex.EncodeToString(raw[:4])]
+	if !ok {
+		return nil, nil
+	}
+
+	values, err := p.decoder.decodeValues(fn.Inputs, raw[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode call of %s on %s: %w", fn.Name, addr, err)
+	}
+	args := make(map[string]interface{}, len(values))
+	for i, key := range fieldKeys(fn.Inputs) {
+		args[key] = values[i]
+	}
+	return &DecodedCall{Address: addr, Function: fn.Name, Args: args}, nil
+}
+
+// DecodeLog decodes log with the event of its emitting contract matching its topic0.
+// Logs of contracts outside the index or of events their ABI doesn't declare are not an error: they return nil.
+func (p *ProtocolIndex) DecodeLog(log RawLog) (*DecodedLog, error) {
+	contract, ok := p.contracts[strings.ToLower(log.Address)]
+	if !ok {
+		return nil, nil
+	}
+
+	name, fields, err := contract.logs.Decode(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode log of %s: %w", log.Address, err)
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return &DecodedLog{Log: log, Event: name, Fields: fields}, nil
+}
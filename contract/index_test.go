@@ -0,0 +1,96 @@
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNFTAddr = "0x2953399124F0cBB46d2CbACD8A89cF0599974963"
+
+func TestIndex_ProtocolIndex(t *testing.T) {
+	// The NFT shares the selector of transfer and the topic0 of Transfer with the token, with other parameters
+	nftABIs := abi.ContractABIs{
+		{
+			Type: "function",
+			Name: "transfer",
+			Inputs: []abi.ABIParameter{
+				{Name: "recipient", Type: "address"},
+				{Name: "tokenId", Type: "uint256"},
+			},
+		},
+		{
+			Type: "event",
+			Name: "Transfer",
+			Inputs: []abi.ABIParameter{
+				{Name: "from", Type: "address", Indexed: true},
+				{Name: "to", Type: "address", Indexed: true},
+				{Name: "tokenId", Type: "uint256", Indexed: true},
+			},
+		},
+		{Type: "event", Name: "Anonymous", Anonymous: true},
+	}
+
+	index, err := NewProtocolIndex(map[string]abi.ContractABIs{
+		testWMATICAddr: loadFixtureABIs(t),
+		testNFTAddr:    nftABIs,
+	})
+	require.NoError(t, err)
+
+	// transfer(0x17f9...9214, 1000)
+	calldata := "0xa9059cbb" +
+		"00000000000000000000000017f935d9b5e73c63b1cec73f97dd988c5e2d9214" +
+		"00000000000000000000000000000000000000000000000000000000000003e8"
+
+	call, err := index.DecodeCall(testWMATICAddr, calldata)
+	require.NoError(t, err)
+	require.NotNil(t, call)
+	assert.Equal(t, "transfer", call.Function)
+	assert.Equal(t, map[string]interface{}{
+		"dst": "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		"wad": big.NewInt(1000),
+	}, call.Args)
+
+	// The target address picks the function among colliding selectors, regardless of case
+	call, err = index.DecodeCall("0x2953399124f0cbb46d2cbacd8a89cf0599974963", calldata)
+	require.NoError(t, err)
+	require.NotNil(t, call)
+	assert.Equal(t, big.NewInt(1000), call.Args["tokenId"])
+
+	call, err = index.DecodeCall("0x0000000000000000000000000000000000000001", calldata)
+	require.NoError(t, err)
+	assert.Nil(t, call)
+	call, err = index.DecodeCall(testWMATICAddr, "0xdeadbeef")
+	require.NoError(t, err)
+	assert.Nil(t, call)
+	call, err = index.DecodeCall(testWMATICAddr, "0x")
+	require.NoError(t, err)
+	assert.Nil(t, call)
+	_, err = index.DecodeCall(testWMATICAddr, "0xa9059cbb00")
+	assert.Error(t, err)
+
+	decoded, err := index.DecodeLog(transferLog)
+	require.NoError(t, err)
+	require.NotNil(t, decoded)
+	assert.Equal(t, "Transfer", decoded.Event)
+	assert.Equal(t, big.NewInt(1000000000000000000), decoded.Fields["wad"])
+
+	nftLog := RawLog{
+		Address: testNFTAddr,
+		Topics: append(append([]string{}, transferLog.Topics...),
+			"0x000000000000000000000000000000000000000000000000000000000000002a"),
+		Data: "0x",
+	}
+	decoded, err = index.DecodeLog(nftLog)
+	require.NoError(t, err)
+	require.NotNil(t, decoded)
+	assert.Equal(t, big.NewInt(42), decoded.Fields["tokenId"])
+
+	nftLog.Address = "0x0000000000000000000000000000000000000001"
+	decoded, err = index.DecodeLog(nftLog)
+	require.NoError(t, err)
+	assert.Nil(t, decoded)
+}
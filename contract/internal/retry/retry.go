@@ -0,0 +1,69 @@
+// Package retry holds the retry policy shared by the RPC and explorer clients
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// maxBackoffShift bounds the doubling of the delay, so many attempts can't overflow it
+const maxBackoffShift = 16
+
+// StatusError is returned for HTTP responses whose status isn't 200 OK
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// IsTransient reports whether a failed request may succeed if retried:
+// a network error, or a rate limited or unavailable server answering 429, 502, 503 or 504
+func IsTransient(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled)
+}
+
+// Backoff returns the delay before retrying after the given attempt: baseDelay doubled for every attempt
+// after the first, of which a random half is kept so clients failing together don't retry in lockstep
+func Backoff(baseDelay time.Duration, attempt int) time.Duration {
+	delay := baseDelay << min(attempt-1, maxBackoffShift)
+	if delay <= 1 {
+		return delay
+	}
+	half := delay / 2
+	return half + rand.N(delay-half)
+}
+
+// Do calls fn until it succeeds, fails with an error IsTransient doesn't report as transient,
+// maxAttempts calls were made or ctx is done. Attempts are spaced by Backoff from baseDelay,
+// waiting on the channels after returns, e.g. time.After.
+func Do(ctx context.Context, maxAttempts int, baseDelay time.Duration, after func(time.Duration) <-chan time.Time, fn func() ([]byte, error)) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		body, err := fn()
+		if err == nil || attempt >= maxAttempts || !IsTransient(err) || ctx.Err() != nil {
+			return body, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w after %d attempts (last error: %v)", ctx.Err(), attempt, err)
+		case <-after(Backoff(baseDelay, attempt)):
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry_IsTransient(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		assert.True(t, IsTransient(fmt.Errorf("call failed: %w", &StatusError{StatusCode: status})), status)
+	}
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError} {
+		assert.False(t, IsTransient(&StatusError{StatusCode: status}), status)
+	}
+
+	assert.True(t, IsTransient(&url.Error{Op: "Post", URL: "https://rpc.example.com", Err: errors.New("connection reset by peer")}))
+	assert.False(t, IsTransient(&url.Error{Op: "Post", URL: "https://rpc.example.com", Err: context.Canceled}))
+	assert.False(t, IsTransient(errors.New("invalid response")))
+}
+
+func TestRetry_Backoff(t *testing.T) {
+	base := 100 * time.Millisecond
+	for attempt := 1; attempt <= 4; attempt++ {
+		delay := base << (attempt - 1)
+		for i := 0; i < 20; i++ {
+			d := Backoff(base, attempt)
+			assert.GreaterOrEqual(t, d, delay/2)
+			assert.Less(t, d, delay)
+		}
+	}
+
+	// The doubling stops before overflowing
+	assert.Positive(t, Backoff(time.Second, 1000))
+	assert.Zero(t, Backoff(0, 3))
+}
+
+func TestRetry_Do(t *testing.T) {
+	ctx := context.Background()
+	transient := &StatusError{StatusCode: http.StatusServiceUnavailable}
+
+	// Waits go through after, which fires at once here
+	var waits []time.Duration
+	after := func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		ch := make(chan time.Time, 1)
+		ch <- time.Time{}
+		return ch
+	}
+
+	calls := 0
+	body, err := Do(ctx, 3, time.Second, after, func() ([]byte, error) {
+		calls++
+		if calls < 3 {
+			return nil, transient
+		}
+		return []byte("ok"), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("ok"), body)
+	assert.Equal(t, 3, calls)
+	if assert.Len(t, waits, 2) {
+		assert.GreaterOrEqual(t, waits[0], 500*time.Millisecond)
+		assert.GreaterOrEqual(t, waits[1], time.Second)
+	}
+
+	// The last error is returned once the attempts run out
+	calls = 0
+	_, err = Do(ctx, 2, time.Second, after, func() ([]byte, error) {
+		calls++
+		return nil, transient
+	})
+	assert.ErrorIs(t, err, transient)
+	assert.Equal(t, 2, calls)
+
+	// Other errors aren't retried
+	calls = 0
+	_, err = Do(ctx, 3, time.Second, after, func() ([]byte, error) {
+		calls++
+		return nil, errors.New("invalid response")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	// A context ending while waiting stops the retries
+	cancelled, cancel := context.WithCancel(ctx)
+	never := func(time.Duration) <-chan time.Time {
+		cancel()
+		return nil
+	}
+	_, err = Do(cancelled, 3, time.Second, never, func() ([]byte, error) {
+		return nil, transient
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "after 1 attempts")
+}
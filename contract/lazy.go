@@ -0,0 +1,108 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// LazyResult holds the raw returndata of a read and decodes its outputs on demand.
+// Each Get only reads the words of the requested output, so malformed or costly outputs
+// that are never accessed don't fail or slow down the read.
+type LazyResult struct {
+	decoder   *decoder
+	params    []abi.ABIParameter
+	data      []byte
+	heads     []int
+	tailStart int
+}
+
+// ReadContractLazy reads the contract like ReadContractValues but returns the outputs undecoded.
+// A function returning a single tuple exposes the components of the tuple as its outputs.
+// Options converting decoded values, like WithTimestampOutputs, don't apply.
+func (c *contractClient) ReadContractLazy(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) (*LazyResult, error) {
+	abi, resultData, err := c.readContract(ctx, addr, abi, args, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := decodeData(resultData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response data: %w", err)
+	}
+	return newLazyResult(c.decoder(), abi.Outputs, data)
+}
+
+// newLazyResult locates the heads of params in data without decoding any value
+func newLazyResult(d *decoder, params []abi.ABIParameter, data []byte) (*LazyResult, error) {
+	if err := checkDepth(params, d.maxDepth); err != nil {
+		return nil, err
+	}
+
+	// A single struct output presents its fields like multiple outputs would
+	if len(params) == 1 && params[0].Type == "tuple" {
+		tuple := params[0]
+		params = tuple.Components
+		if isDynamic(tuple) {
+			offset, err := readSize(data, 0)
+			if err != nil || offset < wordSize {
+				return nil, fmt.Errorf("invalid tuple offset in data of %d bytes", len(data))
+			}
+			data = data[offset:]
+		}
+	}
+
+	heads := make([]int, len(params))
+	size := 0
+	for i, param := range params {
+		heads[i] = size
+		size += headSize(param)
+	}
+	if size > len(data) {
+		return nil, fmt.Errorf("data of %d bytes too short for outputs of %d bytes", len(data), size)
+	}
+
+	return &LazyResult{
+		decoder:   d,
+		params:    params,
+		data:      data,
+		heads:     heads,
+		tailStart: size,
+	}, nil
+}
+
+// Len returns the number of outputs
+func (r *LazyResult) Len() int {
+	return len(r.params)
+}
+
+// Outputs returns the ABI parameters of the outputs
+func (r *LazyResult) Outputs() []abi.ABIParameter {
+	return cloneParameters(r.params)
+}
+
+// Get decodes the output at index, with the same Go types as ReadContractValues.
+// The value is decoded again on every call.
+func (r *LazyResult) Get(index int) (interface{}, error) {
+	if index < 0 || index >= len(r.params) {
+		return nil, fmt.Errorf("output index %d out of range for %d outputs", index, len(r.params))
+	}
+
+	param := r.params[index]
+	value, err := r.decoder.decodeAt(param, r.data, r.heads[index], r.tailStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode output %d (%s): %w", index, param.Type, err)
+	}
+	return value, nil
+}
+
+// GetByName decodes the output keyed name, using the same keys as ReadContractMap
+func (r *LazyResult) GetByName(name string) (interface{}, error) {
+	for i, key := range fieldKeys(r.params) {
+		if key == name {
+			return r.Get(i)
+		}
+	}
+	return nil, fmt.Errorf("no output named %q", name)
+}
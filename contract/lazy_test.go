@@ -0,0 +1,101 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazy_Get(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// position() returns (address owner, uint256 amount, string label, bool active)
+	positionABI := abi.ContractABI{
+		Name: "position",
+		Type: "function",
+		Outputs: []abi.ABIParameter{
+			{Name: "owner", Type: "address"},
+			{Name: "amount", Type: "uint256"},
+			{Name: "label", Type: "string"},
+			{Name: "active", Type: "bool"},
+		},
+	}
+	ret, err := EncodeReturn(positionABI.Outputs, []interface{}{
+		"0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		big.NewInt(42),
+		"vault",
+		true,
+	})
+	require.NoError(t, err)
+
+	// Corrupt the owner and the offset of the label, which Get(1) and Get(3) must not read
+	hexWords := strings.TrimPrefix(ret, "0x")
+	corrupted := "0x" + strings.Repeat("f", 64) + hexWords[64:128] + strings.Repeat("f", 64) + hexWords[192:]
+
+	methodID, err := positionABI.MethodID()
+	require.NoError(t, err)
+	registerEthCallResponder(t, map[string]string{methodID: corrupted})
+
+	cli := NewClient(testRPCURL)
+	result, err := cli.ReadContractLazy(context.Background(), testTokenAddr, positionABI, map[string]interface{}{})
+	require.NoError(t, err)
+	require.Equal(t, 4, result.Len())
+
+	amount, err := result.Get(1)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(42), amount)
+
+	active, err := result.GetByName("active")
+	require.NoError(t, err)
+	assert.Equal(t, true, active)
+
+	// The corrupted outputs only fail when accessed
+	_, err = result.Get(0)
+	assert.ErrorContains(t, err, "non-zero upper bytes")
+	_, err = result.Get(2)
+	assert.ErrorContains(t, err, "invalid offset")
+
+	// The same data fails an eager read
+	_, err = cli.ReadContractValues(context.Background(), testTokenAddr, positionABI, map[string]interface{}{})
+	assert.Error(t, err)
+
+	_, err = result.Get(4)
+	assert.Error(t, err)
+	_, err = result.GetByName("missing")
+	assert.Error(t, err)
+}
+
+func TestLazy_SingleTuple(t *testing.T) {
+	components := []abi.ABIParameter{
+		{Name: "owner", Type: "address"},
+		{Name: "label", Type: "string"},
+		{Name: "amount", Type: "uint256"},
+	}
+	outputs := []abi.ABIParameter{{Type: "tuple", Components: components}}
+	ret, err := EncodeReturn(outputs, []interface{}{
+		[]interface{}{"0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214", "vault", big.NewInt(42)},
+	})
+	require.NoError(t, err)
+	data, err := decodeData(ret)
+	require.NoError(t, err)
+
+	// The components of a single tuple are the outputs, like ReadContractValues
+	result, err := newLazyResult(&decoder{}, outputs, data)
+	require.NoError(t, err)
+	assert.Equal(t, components, result.Outputs())
+
+	label, err := result.GetByName("label")
+	require.NoError(t, err)
+	assert.Equal(t, "vault", label)
+
+	amount, err := result.Get(2)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(42), amount)
+}
@@ -0,0 +1,344 @@
+package contract
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// ErrTooManyResults is returned when the node refuses an eth_getLogs query matching too many logs or spanning
+// too many blocks. Querying a smaller block range usually succeeds.
+var ErrTooManyResults = errors.New("too many log results, query a smaller block range")
+
+// tooManyResultsPhrasings are fragments of the lowercased messages providers refuse oversized log queries with
+var tooManyResultsPhrasings = []string{
+	"query returned more than",   // geth, Infura: "query returned more than 10000 results"
+	"log response size exceeded", // Alchemy
+	"block range is too wide",    // Erigon and others
+	"exceed maximum block range", // public endpoints like Polygon's
+	"range too large",
+}
+
+// RawLog is a log entry as returned by eth_getLogs and in transaction receipts
+type RawLog struct {
+	Address          string   `json:"address"`
+	Topics           []string `json:"topics"`
+	Data             string   `json:"data"`
+	BlockNumber      string   `json:"blockNumber"`
+	BlockHash        string   `json:"blockHash"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex"`
+	LogIndex         string   `json:"logIndex"`
+	Removed          bool     `json:"removed"`
+}
+
+// DecodeLog decodes the parameters of event from the topics and data of a log.
+// Indexed parameters are read from topics[1:] (topics[0:] for anonymous events) and the others are ABI decoded from data.
+// Indexed parameters of dynamic, array and tuple types are stored as the Keccak256 hash of their value,
+// so they are returned as the 0x-prefixed hex of that hash.
+// Parameters are keyed by name, or by their index when unnamed, with the index appended to duplicate names.
+func DecodeLog(event abi.ContractABI, topics []string, data string) (map[string]interface{}, error) {
+	return decodeLog(&decoder{}, event, topics, data)
+}
+
+func decodeLog(d *decoder, event abi.ContractABI, topics []string, data string) (map[string]interface{}, error) {
+	if event.Type != "event" {
+		return nil, fmt.Errorf("cannot decode log for non-event type: %s", event.Type)
+	}
+
+	for _, topic := range topics {
+		if word, err := decodeData(topic); err != nil || len(word) != wordSize {
+			return nil, fmt.Errorf("invalid topic %q of event %s: expected 32 bytes of 0x-prefixed hex", topic, event.Name)
+		}
+	}
+	if !event.Anonymous {
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("log has no topics")
+		}
+		topics = topics[1:]
+	}
+
+	var indexed, nonIndexed []abi.ABIParameter
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		} else {
+			nonIndexed = append(nonIndexed, input)
+		}
+	}
+	if len(topics) != len(indexed) {
+		return nil, fmt.Errorf("event %s expects %d indexed topics, got %d", event.Name, len(indexed), len(topics))
+	}
+
+	rawData, err := decodeData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode log data: %w", err)
+	}
+	values, err := d.decodeValues(nonIndexed, rawData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode log data of event %s: %w", event.Name, err)
+	}
+
+	keys := fieldKeys(event.Inputs)
+	fields := make(map[string]interface{}, len(event.Inputs))
+	topicIdx, valueIdx := 0, 0
+	for i, input := range event.Inputs {
+		key := keys[i]
+
+		if !input.Indexed {
+			fields[key] = values[valueIdx]
+			valueIdx++
+			continue
+		}
+
+		topic := topics[topicIdx]
+		topicIdx++
+		if _, _, isArray := arrayElem(input); isArray || input.Type == "tuple" || isDynamic(input) {
+			fields[key] = topic
+			continue
+		}
+
+		word, err := decodeData(topic)
+		if err != nil {
+			return nil, fmt.Errorf("invalid topic %q for parameter %s of event %s", topic, key, event.Name)
+		}
+		value, err := d.decodeStatic(input.Type, word)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode parameter %s of event %s: %w", key, event.Name, err)
+		}
+		fields[key] = value
+	}
+
+	return fields, nil
+}
+
+// LogDecoder decodes logs of several event types by matching their topic0 against the registered events
+type LogDecoder struct {
+	events  map[string]abi.ContractABI
+	decoder *decoder
+}
+
+// NewLogDecoder creates a LogDecoder for the given events.
+// Anonymous events have no topic0 and cannot be registered.
+func NewLogDecoder(events ...abi.ContractABI) (*LogDecoder, error) {
+	l := &LogDecoder{
+		events:  make(map[string]abi.ContractABI, len(events)),
+		decoder: &decoder{},
+	}
+
+	for _, event := range events {
+		if event.Anonymous {
+			return nil, fmt.Errorf("cannot register anonymous event %s", event.Name)
+		}
+
+		topic, err := event.EventTopic()
+		if err != nil {
+			return nil, err
+		}
+		l.events[topic] = event
+	}
+
+	return l, nil
+}
+
+// Decode decodes log with the event matching its topic0.
+// A log without topics or whose topic0 matches no registered event is not an error:
+// it returns an empty eventName and nil fields.
+func (l *LogDecoder) Decode(log RawLog) (eventName string, fields map[string]interface{}, err error) {
+	if len(log.Topics) == 0 {
+		return "", nil, nil
+	}
+
+	event, ok := l.events[strings.ToLower(log.Topics[0])]
+	if !ok {
+		return "", nil, nil
+	}
+
+	fields, err = decodeLog(l.decoder, event, log.Topics, log.Data)
+	if err != nil {
+		return "", nil, err
+	}
+	return event.Name, fields, nil
+}
+
+// GetLogs fetches the logs of event emitted by the contract at addr between fromBlock and toBlock inclusive via eth_getLogs.
+// A nil block means the latest block. No matching logs is an empty slice, or ErrEmptyResult with WithErrorOnEmpty.
+// Logs may be filtered on indexed parameters with WithIndexedFilter. A node refusing the query for returning too many logs
+// fails it with ErrTooManyResults. Anonymous events have no topic0 to filter on and cannot be fetched.
+func (c *contractClient) GetLogs(ctx context.Context, addr string, event abi.ContractABI, fromBlock, toBlock *big.Int, opts ...CallOption) ([]RawLog, error) {
+	return c.getLogs(ctx, addr, event, fromBlock, toBlock, newCallOptions(opts))
+}
+
+// GetLogsMulti fetches and decodes the logs of event emitted by any of the contracts at addrs in a single eth_getLogs,
+// e.g. the Transfer events of many tokens. Each decoded log keeps the address of the contract which emitted it in Log.Address.
+// A log which fails to decode, e.g. the Transfer of an ERC-721 among ERC-20 ones, keeps its error in DecodedLog.Err.
+// Blocks and empty results are handled like GetLogs.
+func (c *contractClient) GetLogsMulti(ctx context.Context, addrs []string, event abi.ContractABI, fromBlock, toBlock *big.Int, opts ...CallOption) ([]DecodedLog, error) {
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses to get logs from")
+	}
+
+	logs, err := c.getLogs(ctx, addrs, event, fromBlock, toBlock, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	// Every address shares the topic0 of the event, so one decoder covers all logs
+	return decodeLogs(logs, []abi.ContractABI{event})
+}
+
+// getLogs fetches the logs of event emitted by addr, a single address or a list of them
+func (c *contractClient) getLogs(ctx context.Context, addr interface{}, event abi.ContractABI, fromBlock, toBlock *big.Int, callOpts *callOptions) ([]RawLog, error) {
+	if event.Anonymous {
+		return nil, fmt.Errorf("cannot get logs of anonymous event %s", event.Name)
+	}
+
+	topic0, err := event.EventTopic()
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := topicFilters(event, topic0, callOpts.indexedFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := map[string]interface{}{
+		"address":   addr,
+		"topics":    topics,
+		"fromBlock": blockParam(fromBlock),
+		"toBlock":   blockParam(toBlock),
+	}
+	result, err := c.callJSON(ctx, "eth_getLogs", filter)
+	if err != nil {
+		if isTooManyResults(err) {
+			return nil, fmt.Errorf("%w: blocks %s to %s: %w", ErrTooManyResults, blockParam(fromBlock), blockParam(toBlock), err)
+		}
+		return nil, err
+	}
+
+	logs := []RawLog{}
+	if err := json.Unmarshal(result, &logs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal logs %s: %w", snippet(result), err)
+	}
+	// Some nodes answer null rather than an empty array
+	if logs == nil {
+		logs = []RawLog{}
+	}
+	if len(logs) == 0 && callOpts.errorOnEmpty {
+		return nil, fmt.Errorf("%w: no %s logs", ErrEmptyResult, event.Name)
+	}
+	return logs, nil
+}
+
+// topicFilters builds the topics of a log filter: topic0, then for each indexed parameter of event
+// null to match any value, its single value, or the list of its values to match any of them
+func topicFilters(event abi.ContractABI, topic0 string, filters map[string][]interface{}) ([]interface{}, error) {
+	topics := []interface{}{topic0}
+	if len(filters) == 0 {
+		return topics, nil
+	}
+
+	keys := fieldKeys(event.Inputs)
+	indexes := make(map[string]int, len(keys))
+	for i, key := range keys {
+		indexes[key] = i
+	}
+	for key := range filters {
+		i, ok := indexes[key]
+		if !ok {
+			return nil, fmt.Errorf("event %s has no parameter %s", event.Name, key)
+		}
+		if !event.Inputs[i].Indexed {
+			return nil, fmt.Errorf("parameter %s of event %s is not indexed", key, event.Name)
+		}
+	}
+
+	for i, input := range event.Inputs {
+		if !input.Indexed {
+			continue
+		}
+		values, ok := filters[keys[i]]
+		if !ok {
+			topics = append(topics, nil)
+			continue
+		}
+
+		encoded := make([]string, len(values))
+		for j, value := range values {
+			topic, err := encodeTopic(input, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter on parameter %s of event %s: %w", keys[i], event.Name, err)
+			}
+			encoded[j] = topic
+		}
+		if len(encoded) == 1 {
+			topics = append(topics, encoded[0])
+		} else {
+			topics = append(topics, encoded)
+		}
+	}
+
+	// Trailing parameters without a filter match anything
+	for len(topics) > 1 && topics[len(topics)-1] == nil {
+		topics = topics[:len(topics)-1]
+	}
+	return topics, nil
+}
+
+// encodeTopic encodes value of an indexed parameter as the topic logs store it under
+func encodeTopic(param abi.ABIParameter, value interface{}) (string, error) {
+	if _, _, isArray := arrayElem(param); isArray || param.Type == "tuple" {
+		return "", fmt.Errorf("filtering on indexed %s values is not supported", param.Type)
+	}
+
+	var word []byte
+	switch canonicalType(param.Type) {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("expected string, got %T", value)
+		}
+		word = abi.Keccak256([]byte(s))
+	case "bytes":
+		b, err := toBytes(value)
+		if err != nil {
+			return "", err
+		}
+		word = abi.Keccak256(b)
+	default:
+		var err error
+		if word, err = encodeStatic(param.Type, value); err != nil {
+			return "", err
+		}
+	}
+	return "0x" + hex.EncodeToString(word), nil
+}
+
+// isTooManyResults reports whether the node refused a log query for matching too many logs
+func isTooManyResults(err error) bool {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	msg := strings.ToLower(rpcErr.Message)
+	for _, phrasing := range tooManyResultsPhrasings {
+		if strings.Contains(msg, phrasing) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockParam encodes a block number as a hex quantity, or "latest" when nil
+func blockParam(block *big.Int) string {
+	if block == nil {
+		return "latest"
+	}
+	return "0x" + block.Text(16)
+}
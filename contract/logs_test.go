@@ -0,0 +1,428 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var transferLog = RawLog{
+	Address: "0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270",
+	Topics: []string{
+		"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		"0x00000000000000000000000017f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		"0x000000000000000000000000807a96288a1a408dbc13de2b1d087d10356395d2",
+	},
+	Data: "0x0000000000000000000000000000000000000000000000000de0b6b3a7640000",
+}
+
+func TestLogs_DecodeTransfer(t *testing.T) {
+	contractABIs := loadFixtureABIs(t)
+	transferEvent, err := contractABIs.Find("Transfer")
+	require.NoError(t, err)
+
+	fields, err := DecodeLog(*transferEvent, transferLog.Topics, transferLog.Data)
+	require.NoError(t, err)
+
+	assert.Equal(t, "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214", fields["src"])
+	assert.Equal(t, "0x807a96288a1a408dbc13de2b1d087d10356395d2", fields["dst"])
+	assert.Equal(t, big.NewInt(1000000000000000000), fields["wad"])
+
+	// Topics must match the indexed parameters
+	_, err = DecodeLog(*transferEvent, transferLog.Topics[:2], transferLog.Data)
+	assert.Error(t, err)
+
+	// Data and topics must be 0x-prefixed hex, and topics 32 bytes long
+	_, err = DecodeLog(*transferEvent, transferLog.Topics, transferLog.Data[2:])
+	assert.ErrorContains(t, err, "missing 0x prefix")
+	for i := range transferLog.Topics {
+		for _, topic := range []string{transferLog.Topics[i][2:], transferLog.Topics[i][:64], transferLog.Topics[i] + "00"} {
+			topics := append([]string{}, transferLog.Topics...)
+			topics[i] = topic
+			_, err = DecodeLog(*transferEvent, topics, transferLog.Data)
+			assert.ErrorContains(t, err, "invalid topic", topic)
+		}
+	}
+}
+
+func TestLogs_DecodeMixed(t *testing.T) {
+	// Message(address indexed sender, string indexed tag, uint256 id, string text)
+	messageEvent := abi.ContractABI{
+		Name: "Message",
+		Type: "event",
+		Inputs: []abi.ABIParameter{
+			{Name: "sender", Type: "address", Indexed: true},
+			{Name: "tag", Type: "string", Indexed: true},
+			{Name: "id", Type: "uint256"},
+			{Name: "text", Type: "string"},
+		},
+	}
+	topic0, err := messageEvent.EventTopic()
+	require.NoError(t, err)
+
+	// Hash of the tag string as stored in the topic
+	tagHash := "0x6f2bba8a2cb3f2d7d2e03ba2b1e2a3c2c53a9a9b0a0ad1fb1f8f2e8b3a7a2b4c"
+	topics := []string{
+		topic0,
+		"0x00000000000000000000000017f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		tagHash,
+	}
+	data := "0x" +
+		"0000000000000000000000000000000000000000000000000000000000000007" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"0000000000000000000000000000000000000000000000000000000000000005" +
+		"68656c6c6f000000000000000000000000000000000000000000000000000000"
+
+	fields, err := DecodeLog(messageEvent, topics, data)
+	require.NoError(t, err)
+
+	assert.Equal(t, "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214", fields["sender"])
+	// The value of an indexed dynamic parameter is only available as its hash
+	assert.Equal(t, tagHash, fields["tag"])
+	assert.Equal(t, big.NewInt(7), fields["id"])
+	assert.Equal(t, "hello", fields["text"])
+}
+
+func TestLogs_DecodeIndexedTypes(t *testing.T) {
+	// TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values)
+	addr := func(name string) abi.ABIParameter {
+		return abi.ABIParameter{Name: name, Type: "address", Indexed: true}
+	}
+	transferBatch := abi.ContractABI{
+		Name: "TransferBatch",
+		Type: "event",
+		Inputs: []abi.ABIParameter{
+			addr("operator"), addr("from"), addr("to"),
+			{Name: "ids", Type: "uint256[]"},
+			{Name: "values", Type: "uint256[]"},
+		},
+	}
+	topic0, err := transferBatch.EventTopic()
+	require.NoError(t, err)
+	topics := []string{
+		topic0,
+		"0x00000000000000000000000017f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		"0x0000000000000000000000000000000000000000000000000000000000000000",
+		"0x000000000000000000000000807a96288a1a408dbc13de2b1d087d10356395d2",
+	}
+	data := "0x" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"00000000000000000000000000000000000000000000000000000000000000a0" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"000000000000000000000000000000000000000000000000000000000000000a" +
+		"0000000000000000000000000000000000000000000000000000000000000014"
+
+	fields, err := DecodeLog(transferBatch, topics, data)
+	require.NoError(t, err)
+	assert.Equal(t, "0x0000000000000000000000000000000000000000", fields["from"])
+	assert.Equal(t, "0x807a96288a1a408dbc13de2b1d087d10356395d2", fields["to"])
+	assert.Equal(t, []interface{}{big.NewInt(1), big.NewInt(2)}, fields["ids"])
+	assert.Equal(t, []interface{}{big.NewInt(10), big.NewInt(20)}, fields["values"])
+
+	// Static indexed parameters other than addresses are decoded from their topic, and anonymous events
+	// have no topic0: Flag(bool indexed on, int256 indexed delta, bytes32 indexed key, uint256[] indexed ids) anonymous
+	flagEvent := abi.ContractABI{
+		Name:      "Flag",
+		Type:      "event",
+		Anonymous: true,
+		Inputs: []abi.ABIParameter{
+			{Name: "on", Type: "bool", Indexed: true},
+			{Name: "delta", Type: "int256", Indexed: true},
+			{Name: "key", Type: "bytes32", Indexed: true},
+			{Name: "ids", Type: "uint256[]", Indexed: true},
+		},
+	}
+	idsHash := "0x1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f809"
+	fields, err = DecodeLog(flagEvent, []string{
+		"0x0000000000000000000000000000000000000000000000000000000000000001",
+		"0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe",
+		"0xabcd000000000000000000000000000000000000000000000000000000000000",
+		idsHash,
+	}, "0x")
+	require.NoError(t, err)
+	assert.Equal(t, true, fields["on"])
+	assert.Equal(t, big.NewInt(-2), fields["delta"])
+	assert.Equal(t, append([]byte{0xab, 0xcd}, make([]byte, 30)...), fields["key"])
+	// Indexed arrays are only available as their hash
+	assert.Equal(t, idsHash, fields["ids"])
+
+	// A malformed topic is an error
+	_, err = DecodeLog(flagEvent, []string{"0x01", "0x02", "0x03", idsHash}, "0x")
+	assert.Error(t, err)
+}
+
+func TestLogs_LogDecoder(t *testing.T) {
+	contractABIs := loadFixtureABIs(t)
+
+	var events []abi.ContractABI
+	for _, name := range []string{"Transfer", "Approval", "Deposit"} {
+		event, err := contractABIs.Find(name)
+		require.NoError(t, err)
+		events = append(events, *event)
+	}
+
+	logDecoder, err := NewLogDecoder(events...)
+	require.NoError(t, err)
+
+	eventName, fields, err := logDecoder.Decode(transferLog)
+	require.NoError(t, err)
+	assert.Equal(t, "Transfer", eventName)
+	assert.Equal(t, "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214", fields["src"])
+
+	// Deposit(address indexed dst, uint256 wad)
+	depositLog := RawLog{
+		Topics: []string{
+			"0xe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c",
+			"0x00000000000000000000000017f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		},
+		Data: "0x0000000000000000000000000000000000000000000000000000000000000064",
+	}
+	eventName, fields, err = logDecoder.Decode(depositLog)
+	require.NoError(t, err)
+	assert.Equal(t, "Deposit", eventName)
+	assert.Equal(t, big.NewInt(100), fields["wad"])
+
+	// Withdrawal is not registered
+	withdrawalLog := RawLog{
+		Topics: []string{
+			"0x7fcf532c15f0a6db0bd6d0e038bea71d30d808c7d98cb3bf7268a95bf5081b65",
+			"0x00000000000000000000000017f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		},
+		Data: "0x0000000000000000000000000000000000000000000000000000000000000064",
+	}
+	eventName, fields, err = logDecoder.Decode(withdrawalLog)
+	require.NoError(t, err)
+	assert.Empty(t, eventName)
+	assert.Nil(t, fields)
+
+	_, err = NewLogDecoder(abi.ContractABI{Name: "approve", Type: "function"})
+	assert.Error(t, err)
+}
+
+func TestLogs_GetLogsEmpty(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	contractABIs := loadFixtureABIs(t)
+	transferEvent, err := contractABIs.Find("Transfer")
+	require.NoError(t, err)
+
+	cli := NewClient(testRPCURL)
+	for _, result := range []string{"[]", "null"} {
+		httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+			httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":`+result+`}`))
+
+		// No logs is an empty slice by default
+		logs, err := cli.GetLogs(context.Background(), testWMATICAddr, *transferEvent, big.NewInt(1), nil)
+		require.NoError(t, err)
+		assert.NotNil(t, logs)
+		assert.Empty(t, logs)
+
+		_, err = cli.GetLogs(context.Background(), testWMATICAddr, *transferEvent, big.NewInt(1), nil, WithErrorOnEmpty())
+		assert.ErrorIs(t, err, ErrEmptyResult)
+
+		c := NewContract(testWMATICAddr, contractABIs, cli)
+		decoded, err := c.Logs(context.Background(), "Transfer", big.NewInt(1), nil)
+		require.NoError(t, err)
+		assert.NotNil(t, decoded)
+		assert.Empty(t, decoded)
+
+		_, err = c.Logs(context.Background(), "Transfer", big.NewInt(1), nil, WithErrorOnEmpty())
+		assert.ErrorIs(t, err, ErrEmptyResult)
+	}
+}
+
+func TestLogs_GetLogsMulti(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	const usdcAddr = "0x2791bca1f2de4661ed88a30c99a7a9449aa84174"
+	usdcLog := transferLog
+	usdcLog.Address = usdcAddr
+	usdcLog.Data = "0x00000000000000000000000000000000000000000000000000000000000f4240"
+
+	// ERC-721 Transfer logs share the topic0 of ERC-20 ones, but index the token ID instead of logging the amount
+	nftLog := RawLog{
+		Address: "0x22c1f6050e56d2876009903609a2cc3fef83b415",
+		Topics:  append(append([]string{}, transferLog.Topics...), "0x0000000000000000000000000000000000000000000000000000000000000007"),
+		Data:    "0x",
+	}
+
+	var filter map[string]interface{}
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			var rpcReq struct {
+				Params []map[string]interface{} `json:"params"`
+			}
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			filter = rpcReq.Params[0]
+
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"result":  []RawLog{transferLog, nftLog, usdcLog},
+			})
+		})
+
+	transferEvent, err := loadFixtureABIs(t).Find("Transfer")
+	require.NoError(t, err)
+
+	addrs := []string{testWMATICAddr, usdcAddr}
+	logs, err := NewClient(testRPCURL).GetLogsMulti(context.Background(), addrs, *transferEvent, big.NewInt(1), nil)
+	require.NoError(t, err)
+
+	// A single filter covers every address
+	assert.Equal(t, []interface{}{testWMATICAddr, usdcAddr}, filter["address"])
+	assert.Equal(t, []interface{}{transferLog.Topics[0]}, filter["topics"])
+
+	// The log which doesn't decode keeps its error, without losing the others
+	require.Len(t, logs, 3)
+	require.NoError(t, logs[0].Err)
+	assert.Equal(t, transferLog.Address, logs[0].Log.Address)
+	assert.Equal(t, big.NewInt(1000000000000000000), logs[0].Fields["wad"])
+	assert.Equal(t, nftLog, logs[1].Log)
+	assert.Equal(t, "Transfer", logs[1].Event)
+	assert.Nil(t, logs[1].Fields)
+	assert.ErrorContains(t, logs[1].Err, "failed to decode log 1")
+	require.NoError(t, logs[2].Err)
+	assert.Equal(t, usdcAddr, logs[2].Log.Address)
+	assert.Equal(t, big.NewInt(1000000), logs[2].Fields["wad"])
+
+	_, err = NewClient(testRPCURL).GetLogsMulti(context.Background(), nil, *transferEvent, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestLogs_GetLogsFilter(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var filter map[string]interface{}
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			var rpcReq rpcRequest
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			require.Equal(t, "eth_getLogs", rpcReq.Method)
+			filter = rpcReq.Params[0].(map[string]interface{})
+
+			logs, err := json.Marshal([]RawLog{transferLog})
+			require.NoError(t, err)
+			return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":`+string(logs)+`}`), nil
+		})
+
+	contractABIs := loadFixtureABIs(t)
+	transferEvent, err := contractABIs.Find("Transfer")
+	require.NoError(t, err)
+	topic0 := transferLog.Topics[0]
+	from := "0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214"
+	to := "0x807a96288A1A408dBC13DE2b1d087d10356395d2"
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+
+	// Without filters only topic0 is set, and blocks are hex quantities
+	logs, err := cli.GetLogs(ctx, testWMATICAddr, *transferEvent, big.NewInt(1000), big.NewInt(2000))
+	require.NoError(t, err)
+	assert.Equal(t, []RawLog{transferLog}, logs)
+	assert.Equal(t, []interface{}{topic0}, filter["topics"])
+	assert.Equal(t, "0x3e8", filter["fromBlock"])
+	assert.Equal(t, "0x7d0", filter["toBlock"])
+
+	// Parameters before a filtered one match anything
+	_, err = cli.GetLogs(ctx, testWMATICAddr, *transferEvent, big.NewInt(1000), nil, WithIndexedFilter("dst", to))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{topic0, nil, transferLog.Topics[2]}, filter["topics"])
+	assert.Equal(t, "latest", filter["toBlock"])
+
+	// Several values match any of them, trailing parameters without filter are left out
+	_, err = cli.GetLogs(ctx, testWMATICAddr, *transferEvent, nil, nil, WithIndexedFilter("src", from, to))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{topic0, []interface{}{transferLog.Topics[1], transferLog.Topics[2]}}, filter["topics"])
+
+	// Contract.Logs filters the same way
+	c := NewContract(testWMATICAddr, contractABIs, cli)
+	_, err = c.Logs(ctx, "Transfer", nil, nil, WithIndexedFilter("src", from), WithIndexedFilter("dst", to))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{topic0, transferLog.Topics[1], transferLog.Topics[2]}, filter["topics"])
+
+	// Filters on unknown or non-indexed parameters, or with invalid values, fail before querying
+	filter = nil
+	for _, opt := range []CallOption{
+		WithIndexedFilter("wad", big.NewInt(1)),
+		WithIndexedFilter("owner", from),
+		WithIndexedFilter("src", big.NewInt(1)),
+		WithIndexedFilter("dst", "0x1234"),
+	} {
+		_, err = cli.GetLogs(ctx, testWMATICAddr, *transferEvent, nil, nil, opt)
+		assert.Error(t, err)
+	}
+	assert.Nil(t, filter)
+
+	// Indexed strings are matched by their hash
+	tagged := abi.ContractABI{
+		Name: "Tagged",
+		Type: "event",
+		Inputs: []abi.ABIParameter{
+			{Name: "tag", Type: "string", Indexed: true},
+			{Name: "", Type: "uint256", Indexed: true},
+		},
+	}
+	_, err = cli.GetLogs(ctx, testWMATICAddr, tagged, nil, nil, WithIndexedFilter("1", big.NewInt(7)), WithIndexedFilter("tag", "hello"))
+	require.NoError(t, err)
+	topics := filter["topics"].([]interface{})
+	require.Len(t, topics, 3)
+	assert.Equal(t, "0x1c8aff950685c2ed4bc3174f3472287b56d9517b9c948127319a09a7a36deac8", topics[1])
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000007", topics[2])
+
+	// Anonymous events have no topic0, so a filter would match the logs of any event
+	filter = nil
+	anonymous := tagged
+	anonymous.Anonymous = true
+	_, err = cli.GetLogs(ctx, testWMATICAddr, anonymous, nil, nil)
+	assert.ErrorContains(t, err, "anonymous event Tagged")
+	_, err = cli.GetLogsMulti(ctx, []string{testWMATICAddr}, anonymous, nil, nil)
+	assert.Error(t, err)
+	assert.Nil(t, filter)
+}
+
+func TestLogs_GetLogsTooManyResults(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	contractABIs := loadFixtureABIs(t)
+	transferEvent, err := contractABIs.Find("Transfer")
+	require.NoError(t, err)
+	cli := NewClient(testRPCURL)
+
+	for _, message := range []string{
+		"query returned more than 10000 results",
+		"Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range",
+		"exceed maximum block range: 5000",
+	} {
+		httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+			httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"`+message+`"}}`))
+
+		_, err := cli.GetLogs(context.Background(), testWMATICAddr, *transferEvent, big.NewInt(1), big.NewInt(1000000))
+		require.ErrorIs(t, err, ErrTooManyResults, message)
+		assert.ErrorContains(t, err, "blocks 0x1 to 0xf4240")
+		var rpcErr *RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, -32005, rpcErr.Code)
+	}
+
+	// Other errors are returned as they are
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid argument 0: hex string without 0x prefix"}}`))
+	_, err = cli.GetLogs(context.Background(), testWMATICAddr, *transferEvent, big.NewInt(1), nil)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrTooManyResults)
+}
@@ -0,0 +1,18 @@
+package contract
+
+import (
+	"strconv"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// personalMessagePrefix is the EIP-191 version 0x45 prefix used by personal_sign
+const personalMessagePrefix = "\x19Ethereum Signed Message:\n"
+
+// HashPersonalMessage returns the 32-byte EIP-191 hash of msg as signed by personal_sign,
+// keccak256("\x19Ethereum Signed Message:\n" + len(msg) + msg).
+// This is the digest recovered by on-chain signature verification such as ECDSA.toEthSignedMessageHash.
+func HashPersonalMessage(msg []byte) []byte {
+	prefix := personalMessagePrefix + strconv.Itoa(len(msg))
+	return abi.Keccak256([]byte(prefix), msg)
+}
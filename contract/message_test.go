@@ -0,0 +1,24 @@
+package contract
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_HashPersonalMessage(t *testing.T) {
+	tests := []struct {
+		msg      string
+		expected string
+	}{
+		{"Hello World", "a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2"},
+		{"", "5f35dce98ba4fba25530a026ed80b2cecdaa31091ba4958b99b52ea1d068adad"},
+	}
+
+	for _, tc := range tests {
+		hash := HashPersonalMessage([]byte(tc.msg))
+		assert.Len(t, hash, 32)
+		assert.Equal(t, tc.expected, hex.EncodeToString(hash), tc.msg)
+	}
+}
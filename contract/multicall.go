@@ -0,0 +1,169 @@
+package contract
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// DefaultMulticallAddress is the address Multicall3 is deployed at on most EVM chains
+const DefaultMulticallAddress = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+// ErrMulticallNotDeployed is returned when there is no contract code at the multicall address
+var ErrMulticallNotDeployed = errors.New("no contract code at multicall address")
+
+// aggregate3ABI is Multicall3's aggregate3((address,bool,bytes)[]) returns ((bool,bytes)[])
+var aggregate3ABI = abi.ContractABI{
+	Type:            "function",
+	Name:            "aggregate3",
+	StateMutability: "payable",
+	Inputs: []abi.ABIParameter{{
+		Name: "calls",
+		Type: "tuple[]",
+		Components: []abi.ABIParameter{
+			{Name: "target", Type: "address"},
+			{Name: "allowFailure", Type: "bool"},
+			{Name: "callData", Type: "bytes"},
+		},
+	}},
+	Outputs: []abi.ABIParameter{{
+		Name: "returnData",
+		Type: "tuple[]",
+		Components: []abi.ABIParameter{
+			{Name: "success", Type: "bool"},
+			{Name: "returnData", Type: "bytes"},
+		},
+	}},
+}
+
+// Multicall reads several functions, each of the contract at its Call.Address, in a single eth_call
+// through Multicall3. Results are returned in the same order as calls, and a reverting call only
+// sets the Err of its own result.
+//
+// When there is no contract code at the multicall address, e.g. on a chain Multicall3 isn't deployed on,
+// the calls are read with ReadBatch instead and the observer is notified with NoticeMulticallFallback.
+func (c *contractClient) Multicall(ctx context.Context, calls []Call) ([]BatchResult, error) {
+	results, err := c.multicall(ctx, calls)
+	if !errors.Is(err, ErrMulticallNotDeployed) {
+		return results, err
+	}
+
+	if c.observer != nil {
+		c.observer(Notice{Kind: NoticeMulticallFallback, Endpoint: c.rpcURL, Err: err})
+	}
+	return c.ReadBatch(ctx, "", calls)
+}
+
+// multicall reads calls with a single aggregate3 call to the multicall contract
+func (c *contractClient) multicall(ctx context.Context, calls []Call) ([]BatchResult, error) {
+	results := make([]BatchResult, len(calls))
+
+	// Calls which fail to encode are reported individually and left out of the aggregate
+	indexes := make([]int, 0, len(calls))
+	subCalls := make([]interface{}, 0, len(calls))
+	for i, call := range calls {
+		if call.Address == "" {
+			results[i].Err = fmt.Errorf("call %d has no address", i)
+			continue
+		}
+		target, err := normalizeAddress(call.Address)
+		if err != nil {
+			results[i].Err = fmt.Errorf("invalid contract address: %w", err)
+			continue
+		}
+		if err := c.validateInputs(call.ABI, call.Args); err != nil {
+			results[i].Err = err
+			continue
+		}
+		callData, err := c.encodeData(call.ABI, call.Args)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		indexes = append(indexes, i)
+		subCalls = append(subCalls, map[string]interface{}{
+			"target":       target,
+			"allowFailure": true,
+			"callData":     callData,
+		})
+	}
+	if len(subCalls) == 0 {
+		return results, nil
+	}
+
+	methodID, err := aggregate3ABI.MethodID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get method ID: %w", err)
+	}
+	args, err := encodeValues(aggregate3ABI.Inputs, []interface{}{subCalls})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode multicall: %w", err)
+	}
+
+	result, err := c.call(ctx, "eth_call", map[string]string{
+		"to":   c.multicallAddress(),
+		"data": "0x" + methodID + hex.EncodeToString(args),
+	}, "latest")
+	if err != nil {
+		return nil, err
+	}
+	data, err := decodeData(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode multicall response: %w", err)
+	}
+
+	// Calling an address without code succeeds with empty returndata
+	if len(data) == 0 {
+		return nil, c.checkMulticallCode(ctx)
+	}
+
+	values, err := c.decoder().decodeValues(aggregate3ABI.Outputs, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode multicall response: %w", err)
+	}
+	returns := values[0].([]interface{})
+	if len(returns) != len(indexes) {
+		return nil, fmt.Errorf("multicall returned %d results for %d calls", len(returns), len(indexes))
+	}
+
+	for j, i := range indexes {
+		ret := returns[j].(map[string]interface{})
+		returnData := ret["returnData"].([]byte)
+		if !ret["success"].(bool) {
+			results[i].Err = c.decoder().decodeBuiltinRevert(returnData)
+			if results[i].Err == nil {
+				results[i].Err = fmt.Errorf("%w: 0x%x", ErrExecutionReverted, returnData)
+			}
+			continue
+		}
+		results[i] = c.batchResult("0x"+hex.EncodeToString(returnData), calls[i].ABI)
+	}
+
+	return results, nil
+}
+
+// checkMulticallCode explains an empty multicall response, returning ErrMulticallNotDeployed
+// when there is no contract code at the multicall address
+func (c *contractClient) checkMulticallCode(ctx context.Context) error {
+	addr := c.multicallAddress()
+	code, err := c.call(ctx, "eth_getCode", addr, "latest")
+	if err != nil {
+		return fmt.Errorf("empty multicall response, failed to get code at %s: %w", addr, err)
+	}
+	if code == "0x" || code == "" {
+		return fmt.Errorf("%w %s", ErrMulticallNotDeployed, addr)
+	}
+	return fmt.Errorf("empty multicall response from %s", addr)
+}
+
+// multicallAddress returns the address set by WithMulticallAddress, or DefaultMulticallAddress
+func (c *contractClient) multicallAddress() string {
+	if c.multicallAddr != "" {
+		return c.multicallAddr
+	}
+	return DefaultMulticallAddress
+}
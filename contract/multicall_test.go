@@ -0,0 +1,193 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMulticall_MissingAggregator(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var methods []string
+	var batch []rpcRequest
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+
+			if strings.HasPrefix(string(body), "[") {
+				methods = append(methods, "batch")
+				require.NoError(t, json.Unmarshal(body, &batch))
+				return httpmock.NewStringResponse(http.StatusOK, `[
+					{"jsonrpc":"2.0","id":0,"result":"0x0000000000000000000000000000000000000000000000000000000000000012"},
+					{"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000000000000000006"}
+				]`), nil
+			}
+
+			var rpcReq rpcRequest
+			require.NoError(t, json.Unmarshal(body, &rpcReq))
+			methods = append(methods, rpcReq.Method)
+			if rpcReq.Method == "eth_call" {
+				assert.Equal(t, DefaultMulticallAddress, rpcReq.Params[0].(map[string]interface{})["to"])
+			}
+
+			// Neither calling nor fetching the code of an empty account fails, both return empty data
+			return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x"}`), nil
+		})
+
+	contractABIs := loadFixtureABIs(t)
+	decimals, err := contractABIs.Find("decimals")
+	require.NoError(t, err)
+
+	calls := []Call{
+		{Address: "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", ABI: *decimals},
+		{Address: "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174", ABI: *decimals},
+	}
+
+	var notices []Notice
+	cli := NewClient(testRPCURL, WithObserver(func(n Notice) {
+		notices = append(notices, n)
+	}))
+	results, err := cli.Multicall(context.Background(), calls)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, []string{"eth_call", "eth_getCode", "batch"}, methods)
+	require.Len(t, batch, 2)
+	assert.Equal(t, strings.ToLower(calls[0].Address), batch[0].Params[0].(map[string]interface{})["to"])
+	assert.Equal(t, strings.ToLower(calls[1].Address), batch[1].Params[0].(map[string]interface{})["to"])
+
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "18", results[0].Result)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, "6", results[1].Result)
+
+	require.Len(t, notices, 1)
+	assert.Equal(t, NoticeMulticallFallback, notices[0].Kind)
+	assert.ErrorIs(t, notices[0].Err, ErrMulticallNotDeployed)
+}
+
+func TestMulticall_EmptyResponseWithCode(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			var rpcReq rpcRequest
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			result := "0x"
+			if rpcReq.Method == "eth_getCode" {
+				result = "0x6080"
+			}
+			return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":%q}`, result)), nil
+		})
+
+	contractABIs := loadFixtureABIs(t)
+	decimals, err := contractABIs.Find("decimals")
+	require.NoError(t, err)
+
+	// Only a missing aggregator falls back, other failures are reported
+	cli := NewClient(testRPCURL, WithMulticallAddress("0x0000000000000000000000000000000000001234"))
+	_, err = cli.Multicall(context.Background(), []Call{{Address: "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", ABI: *decimals}})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrMulticallNotDeployed)
+	assert.ErrorContains(t, err, "0x0000000000000000000000000000000000001234")
+}
+
+func TestMulticall_Aggregate3(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	const aggregator = "0x0000000000000000000000000000000000001234"
+	var subCalls []interface{}
+	var to string
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			var rpcReq rpcRequest
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			require.Equal(t, "eth_call", rpcReq.Method)
+			params := rpcReq.Params[0].(map[string]interface{})
+			to = params["to"].(string)
+
+			// aggregate3 calldata is its selector followed by the encoded (address,bool,bytes)[]
+			data, err := decodeData(params["data"].(string))
+			require.NoError(t, err)
+			methodID, err := aggregate3ABI.MethodID()
+			require.NoError(t, err)
+			require.Equal(t, methodID, fmt.Sprintf("%x", data[:4]))
+			values, err := (&decoder{}).decodeValues(aggregate3ABI.Inputs, data[4:])
+			require.NoError(t, err)
+			subCalls = values[0].([]interface{})
+
+			// The first call succeeds, the second reverts with a reason
+			reason, err := EncodeReturn([]abi.ABIParameter{{Type: "string"}}, []interface{}{"paused"})
+			require.NoError(t, err)
+			revertData, err := decodeData("0x08c379a0" + reason[2:])
+			require.NoError(t, err)
+			returnData, err := EncodeReturn(aggregate3ABI.Outputs, []interface{}{[]interface{}{
+				map[string]interface{}{"success": true, "returnData": append(make([]byte, 31), 18)},
+				map[string]interface{}{"success": false, "returnData": revertData},
+			}})
+			require.NoError(t, err)
+			return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"`+returnData+`"}`), nil
+		})
+
+	contractABIs := loadFixtureABIs(t)
+	decimals, err := contractABIs.Find("decimals")
+	require.NoError(t, err)
+	balanceOf, err := contractABIs.Find("balanceOf")
+	require.NoError(t, err)
+	calls := []Call{
+		{Address: "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", ABI: *decimals},
+		{Address: "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174", ABI: *balanceOf, Args: map[string]interface{}{"": "0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214"}},
+	}
+
+	cli := NewClient(testRPCURL, WithMulticallAddress(aggregator))
+	results, err := cli.Multicall(context.Background(), calls)
+	require.NoError(t, err)
+	assert.Equal(t, aggregator, to)
+
+	// Each sub-call targets its contract, allowing failure
+	require.Len(t, subCalls, 2)
+	for i, subCall := range subCalls {
+		fields := subCall.(map[string]interface{})
+		assert.Equal(t, strings.ToLower(calls[i].Address), fields["target"])
+		assert.Equal(t, true, fields["allowFailure"])
+	}
+	assert.Equal(t, []byte{0x31, 0x3c, 0xe5, 0x67}, subCalls[0].(map[string]interface{})["callData"])
+	assert.Equal(t, "70a08231"+"00000000000000000000000017f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		fmt.Sprintf("%x", subCalls[1].(map[string]interface{})["callData"]))
+
+	require.Len(t, results, 2)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "18", results[0].Result)
+	var revertErr *RevertError
+	require.ErrorAs(t, results[1].Err, &revertErr)
+	assert.Equal(t, "paused", revertErr.Reason)
+
+	// Without WithMulticallAddress, calls go to the canonical deployment
+	_, err = NewClient(testRPCURL).Multicall(context.Background(), calls)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMulticallAddress, to)
+
+	// A call with an invalid address fails alone, and the others are still aggregated
+	withInvalid := []Call{calls[0], {Address: "0x1234", ABI: *decimals}, calls[1]}
+	results, err = cli.Multicall(context.Background(), withInvalid)
+	require.NoError(t, err)
+	require.Len(t, subCalls, 2)
+	require.Len(t, results, 3)
+	assert.Equal(t, "18", results[0].Result)
+	assert.ErrorContains(t, results[1].Err, "invalid contract address")
+	assert.ErrorAs(t, results[2].Err, &revertErr)
+}
@@ -0,0 +1,24 @@
+package contract
+
+// NoticeKind identifies what a Notice reports
+type NoticeKind int
+
+const (
+	// NoticeBreakerStateChange reports a circuit breaker transition of an endpoint
+	NoticeBreakerStateChange NoticeKind = iota
+	// NoticeMulticallFallback reports that Multicall fell back to individual calls
+	NoticeMulticallFallback
+)
+
+// Notice describes something notable happening inside the client
+type Notice struct {
+	Kind     NoticeKind
+	Endpoint string
+	// Breaker is the new state for NoticeBreakerStateChange
+	Breaker BreakerState
+	// Err is the cause of a NoticeMulticallFallback
+	Err error
+}
+
+// Observer receives notices from the client. It may be called concurrently and must not block.
+type Observer func(Notice)
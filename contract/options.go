@@ -0,0 +1,380 @@
+package contract
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// Option configures a contract client
+type Option func(*contractClient)
+
+// UTF8Mode controls how string outputs containing invalid UTF-8 are decoded
+type UTF8Mode int
+
+const (
+	// UTF8Lenient replaces invalid UTF-8 sequences with the Unicode replacement character
+	UTF8Lenient UTF8Mode = iota
+	// UTF8Strict rejects string outputs that are not valid UTF-8
+	UTF8Strict
+)
+
+// WithUTF8Mode sets how decoded string outputs are checked for valid UTF-8.
+// The default is UTF8Lenient.
+func WithUTF8Mode(mode UTF8Mode) Option {
+	return func(c *contractClient) {
+		c.utf8Mode = mode
+	}
+}
+
+// WithStrictBool rejects bool outputs encoded as anything but 0 or 1.
+// By default any nonzero word decodes as true.
+func WithStrictBool() Option {
+	return func(c *contractClient) {
+		c.strictBool = true
+	}
+}
+
+// WithMaxDepth bounds how deeply arrays and tuples may nest in the types of encoded inputs and decoded outputs.
+// Deeper types, e.g. from an untrusted ABI, fail with ErrMaxDepthExceeded. The default is DefaultMaxDepth.
+func WithMaxDepth(n int) Option {
+	return func(c *contractClient) {
+		c.maxDepth = n
+	}
+}
+
+// WithMethodDiagnostics checks the deployed code when a read reverts without data or returns nothing,
+// reporting ErrMethodNotFound when there is no contract at the address or its code doesn't dispatch the function.
+// This turns reads with a mismatched ABI into an actionable error, at the cost of an extra call for such reads.
+func WithMethodDiagnostics() Option {
+	return func(c *contractClient) {
+		c.methodDiagnostics = true
+	}
+}
+
+// WithChainIDCache makes ChainID query the node only once and reuse the result afterwards
+func WithChainIDCache() Option {
+	return func(c *contractClient) {
+		c.cacheChainID = true
+	}
+}
+
+// WithMaxBatchSize limits the number of calls sent in a single JSON-RPC batch request.
+// Larger batches are split into several requests. The default of 0 means no limit.
+func WithMaxBatchSize(n int) Option {
+	return func(c *contractClient) {
+		c.maxBatchSize = n
+	}
+}
+
+// WithMaxConcurrency caps the number of RPC requests the client has in flight at once.
+// The cap is shared by every operation of the client, including batches and polling,
+// and callers over it wait for a free slot or their context to end. The default of 0 means no limit.
+func WithMaxConcurrency(n int) Option {
+	return func(c *contractClient) {
+		c.maxConcurrency = n
+	}
+}
+
+// WithMulticallAddress sets the Multicall3 contract used by Multicall instead of DefaultMulticallAddress
+func WithMulticallAddress(addr string) Option {
+	return func(c *contractClient) {
+		c.multicallAddr = addr
+	}
+}
+
+// WithABIProvider sets where ReadAuto fetches contract ABIs from, e.g. an explorer client from abi.NewABIClient
+func WithABIProvider(provider abi.ABI) Option {
+	return func(c *contractClient) {
+		c.abiProvider = provider
+	}
+}
+
+// WithENS makes reads accept ENS names, e.g. "uniswap.eth", as the contract address and as address arguments,
+// resolving them with ResolveName before the call. Names are resolved through the ENS registry at rpcURL,
+// e.g. an Ethereum mainnet endpoint for a client reading another chain, or through the client's own endpoints when empty.
+func WithENS(rpcURL string) Option {
+	return func(c *contractClient) {
+		c.ensEnabled = true
+		c.ensURL = rpcURL
+	}
+}
+
+// WithFallbackURLs adds RPC endpoints tried in order when the previous ones fail
+func WithFallbackURLs(urls ...string) Option {
+	return func(c *contractClient) {
+		c.fallbackURLs = append(c.fallbackURLs, urls...)
+	}
+}
+
+// WithRetry makes up to maxAttempts calls to an endpoint while it fails with a transient error:
+// a network error, or an HTTP status 429, 502, 503 or 504. Retries wait baseDelay, doubled after every attempt,
+// with jitter, and stop once the context passed to the client methods is done.
+// An endpoint still failing after its attempts counts as a single failure for WithCircuitBreaker
+// before WithFallbackURLs moves on to the next endpoint.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(c *contractClient) {
+		c.retryAttempts = maxAttempts
+		c.retryDelay = baseDelay
+	}
+}
+
+// WithCircuitBreaker stops sending to an endpoint after threshold consecutive failures,
+// routing to the next endpoint if any, until cooldown has elapsed.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *contractClient) {
+		c.breakerThreshold = threshold
+		c.breakerCooldown = cooldown
+	}
+}
+
+// WithObserver sets a function notified of notable client events, like circuit breaker transitions
+func WithObserver(observer Observer) Option {
+	return func(c *contractClient) {
+		c.observer = observer
+	}
+}
+
+// WithClock sets the clock timing circuit breaker cooldowns and receipt polling instead of the system clock,
+// e.g. to drive them from tests without sleeping
+func WithClock(clock Clock) Option {
+	return func(c *contractClient) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to reach the RPC endpoints instead of http.DefaultClient
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *contractClient) {
+		c.httpClient = client
+	}
+}
+
+// WithTimeout bounds each HTTP request to the RPC endpoints, including reading the response, to d.
+// It applies on top of WithHTTPClient, keeping the other settings of that client. By default requests are only
+// bounded by the context passed to the client methods.
+func WithTimeout(d time.Duration) Option {
+	return func(c *contractClient) {
+		c.timeout = d
+	}
+}
+
+// WithTransport sets the http.RoundTripper used to reach the RPC endpoints, e.g. to add tracing.
+// It applies on top of WithHTTPClient, keeping the other settings of that client.
+// Requests carry the context passed to the client methods, so spans started from it nest under the caller's.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *contractClient) {
+		c.roundTripper = rt
+	}
+}
+
+// CallOption configures a single contract read
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	outputTypes      []string
+	timestampOutputs []string
+	errorOnEmpty     bool
+	errorABIs        []abi.ContractABI
+	jsonNumbers      bool
+	uint256Outputs   bool
+	validators       []Validator
+	blockNumber      *big.Int
+	blockTag         string
+	indexedFilters   map[string][]interface{}
+	value            *big.Int
+	stateOverrides   map[string]StateOverride
+}
+
+func newCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithOutputTypes decodes the result using the given types instead of the outputs declared in the ABI.
+// This is useful when the ABI entry is missing or wrong, e.g. for unverified contracts.
+func WithOutputTypes(types ...string) CallOption {
+	return func(o *callOptions) {
+		o.outputTypes = types
+	}
+}
+
+// WithTimestampOutputs decodes the given integer outputs, designated by name or index, as Unix timestamps in seconds.
+// They are returned as UTC time.Time by ReadContractValues and ReadContractMap, and
+// values beyond year 9999 or of other types are an error. Other outputs keep their default decoding.
+func WithTimestampOutputs(keys ...string) CallOption {
+	return func(o *callOptions) {
+		o.timestampOutputs = keys
+	}
+}
+
+// WithJSONNumbers returns integer outputs of ReadContractValues, ReadContractMap and ReadContractDescribed,
+// including those in arrays and tuples, as json.Number instead of *big.Int.
+// They marshal as JSON numbers with every digit, where float64 would lose precision beyond 2^53.
+func WithJSONNumbers() CallOption {
+	return func(o *callOptions) {
+		o.jsonNumbers = true
+	}
+}
+
+// WithUint256Outputs returns uintN outputs of ReadContractValues, ReadContractMap and ReadContractDescribed,
+// including those in arrays and tuples, as Uint256 instead of *big.Int. Signed integers stay *big.Int.
+// WithJSONNumbers takes precedence.
+func WithUint256Outputs() CallOption {
+	return func(o *callOptions) {
+		o.uint256Outputs = true
+	}
+}
+
+// WithErrorOnEmpty makes a read fail with ErrEmptyResult when a collection it returns is empty:
+// the logs of GetLogs and Contract.Logs, or any array output of ReadContractValues, ReadContractMap and ReadContractDescribed.
+// By default an empty collection is returned as an empty, non-nil slice.
+func WithErrorOnEmpty() CallOption {
+	return func(o *callOptions) {
+		o.errorOnEmpty = true
+	}
+}
+
+// WithErrorABIs decodes reverts with the custom errors declared by the given error ABI entries.
+// Without it, reverts are reported as the node phrased them, wrapping ErrExecutionReverted,
+// except reverts with a reason string or a panic code, which always fail with a *RevertError or a *PanicError.
+// A revert with a known error fails with a *CustomError holding its arguments, and one with an unknown error
+// reports the selector. Both wrap ErrExecutionReverted and the *RPCError, all found with errors.As.
+// Contract.Read declares the errors of its ABI.
+func WithErrorABIs(errs ...abi.ContractABI) CallOption {
+	return func(o *callOptions) {
+		o.errorABIs = append(o.errorABIs, errs...)
+	}
+}
+
+// WithIndexedFilter makes GetLogs, GetLogsMulti and Contract.Logs only return logs whose indexed parameter,
+// designated by name or index like the keys of decoded logs, equals any of values.
+// Values have the Go types inputs of the parameter type are encoded from, e.g. an address string or a *big.Int,
+// and values of indexed string and bytes parameters are matched by their Keccak256 hash.
+// Filters on several parameters must all match.
+func WithIndexedFilter(key string, values ...interface{}) CallOption {
+	return func(o *callOptions) {
+		if o.indexedFilters == nil {
+			o.indexedFilters = make(map[string][]interface{})
+		}
+		o.indexedFilters[key] = append(o.indexedFilters[key], values...)
+	}
+}
+
+// WithValue sends value wei along with the eth_call of a read, e.g. to simulate a payable function.
+// A positive value fails the read with ErrNotPayable unless the function is declared payable.
+func WithValue(value *big.Int) CallOption {
+	return func(o *callOptions) {
+		o.value = value
+	}
+}
+
+// WithStateOverride reads the contract as if the accounts, keyed by address, had the given state,
+// e.g. a larger balance or different code, by sending the overrides as the third parameter of eth_call.
+// Nodes failing the read other than by reverting may not support overrides, which their error points out.
+func WithStateOverride(overrides map[string]StateOverride) CallOption {
+	return func(o *callOptions) {
+		o.stateOverrides = overrides
+	}
+}
+
+// Validator checks the decoded outputs of a read, returning an error to reject them
+type Validator func(values []interface{}) error
+
+// WithValidator runs validate on the outputs of ReadContractValues, ReadContractMap and ReadContractDescribed
+// before they are returned, e.g. to reject a balance exceeding the total supply.
+// It sees the outputs in order, as ReadContractValues returns them after the other options applied.
+// A rejection fails the read with ErrValidationFailed wrapping the error of validate.
+// Validators run in the order they are given.
+func WithValidator(validate Validator) CallOption {
+	return func(o *callOptions) {
+		o.validators = append(o.validators, validate)
+	}
+}
+
+// blockTags are the block tags accepted by WithBlockTag
+var blockTags = map[string]bool{
+	"latest":    true,
+	"earliest":  true,
+	"pending":   true,
+	"safe":      true,
+	"finalized": true,
+}
+
+// WithBlockNumber reads the state of the contract at block n, e.g. a historical balance, instead of the latest block.
+// Reading old blocks usually requires an archive node.
+func WithBlockNumber(n *big.Int) CallOption {
+	return func(o *callOptions) {
+		o.blockNumber = n
+		o.blockTag = ""
+	}
+}
+
+// WithBlockTag reads the state of the contract at a block tag: "latest", "earliest", "pending", "safe" or "finalized".
+// Other tags fail the read.
+func WithBlockTag(tag string) CallOption {
+	return func(o *callOptions) {
+		o.blockTag = tag
+		o.blockNumber = nil
+	}
+}
+
+// block returns the block parameter of the read, "latest" unless set by WithBlockNumber or WithBlockTag
+func (o *callOptions) block() (string, error) {
+	switch {
+	case o.blockNumber != nil:
+		if o.blockNumber.Sign() < 0 {
+			return "", fmt.Errorf("invalid block number: %s", o.blockNumber)
+		}
+		return blockParam(o.blockNumber), nil
+	case o.blockTag != "":
+		if !blockTags[o.blockTag] {
+			return "", fmt.Errorf("invalid block tag: %q", o.blockTag)
+		}
+		return o.blockTag, nil
+	default:
+		return "latest", nil
+	}
+}
+
+// outputParameters synthesizes unnamed ABI outputs from a list of types
+func outputParameters(types []string) []abi.ABIParameter {
+	params := make([]abi.ABIParameter, len(types))
+	for i, typ := range types {
+		params[i] = abi.ABIParameter{Type: typ}
+	}
+	return params
+}
+
+// WaitOption configures WaitForReceipt
+type WaitOption func(*waitOptions)
+
+type waitOptions struct {
+	confirmations uint64
+}
+
+func newWaitOptions(opts []WaitOption) *waitOptions {
+	o := &waitOptions{confirmations: 1}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithConfirmations waits until the transaction's block is n blocks deep, counting the inclusion block.
+// The default of 1 returns as soon as the transaction is mined.
+func WithConfirmations(n uint64) WaitOption {
+	return func(o *waitOptions) {
+		if n > 0 {
+			o.confirmations = n
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package contract
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// StateOverride replaces the state of an account for the duration of an eth_call, e.g. to simulate a larger balance.
+// Unset fields keep the state of the block read.
+type StateOverride struct {
+	// Balance is the balance of the account in wei
+	Balance *big.Int
+	// Nonce is the nonce of the account
+	Nonce *uint64
+	// Code is the runtime bytecode of the account
+	Code []byte
+	// State replaces the whole storage of the account with the given slots, keyed by slot
+	State map[string]string
+	// StateDiff replaces the given slots, keyed by slot, keeping the rest of the storage
+	StateDiff map[string]string
+}
+
+// MarshalJSON encodes the override as the JSON object of the eth_call state override set,
+// with quantities and data in hex and storage slots and values as 32-byte words
+func (o StateOverride) MarshalJSON() ([]byte, error) {
+	if o.State != nil && o.StateDiff != nil {
+		return nil, errors.New("state and stateDiff are mutually exclusive")
+	}
+
+	obj := make(map[string]interface{})
+	if o.Balance != nil {
+		if o.Balance.Sign() < 0 {
+			return nil, fmt.Errorf("invalid balance: %s", o.Balance)
+		}
+		obj["balance"] = "0x" + o.Balance.Text(16)
+	}
+	if o.Nonce != nil {
+		obj["nonce"] = fmt.Sprintf("0x%x", *o.Nonce)
+	}
+	if o.Code != nil {
+		obj["code"] = "0x" + hex.EncodeToString(o.Code)
+	}
+	if o.State != nil {
+		state, err := storageWords(o.State)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state: %w", err)
+		}
+		obj["state"] = state
+	}
+	if o.StateDiff != nil {
+		stateDiff, err := storageWords(o.StateDiff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stateDiff: %w", err)
+		}
+		obj["stateDiff"] = stateDiff
+	}
+	return json.Marshal(obj)
+}
+
+// storageWords normalizes storage slots and values given as hex of up to 32 bytes into 0x-prefixed words
+func storageWords(storage map[string]string) (map[string]string, error) {
+	words := make(map[string]string, len(storage))
+	for slot, value := range storage {
+		slotWord, err := toWordHex(slot)
+		if err != nil {
+			return nil, fmt.Errorf("slot %q: %w", slot, err)
+		}
+		valueWord, err := toWordHex(value)
+		if err != nil {
+			return nil, fmt.Errorf("value of slot %q: %w", slot, err)
+		}
+		words[slotWord] = valueWord
+	}
+	return words, nil
+}
+
+// toWordHex left-pads hex of up to 32 bytes, with or without 0x prefix, into a 0x-prefixed word
+func toWordHex(s string) (string, error) {
+	h := strings.TrimPrefix(s, "0x")
+	if len(h) > 2*wordSize {
+		return "", fmt.Errorf("more than %d bytes", wordSize)
+	}
+	if len(h)%2 == 1 {
+		h = "0" + h
+	}
+	if _, err := hex.DecodeString(h); err != nil {
+		return "", errors.New("not hex")
+	}
+	return "0x" + strings.Repeat("0", 2*wordSize-len(h)) + strings.ToLower(h), nil
+}
+
+// stateOverrideSet returns the overrides keyed by normalized address, as sent in the third parameter of eth_call
+func stateOverrideSet(overrides map[string]StateOverride) (map[string]StateOverride, error) {
+	set := make(map[string]StateOverride, len(overrides))
+	for addr, override := range overrides {
+		normalized, err := normalizeAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state override: %w", err)
+		}
+		if _, err := override.MarshalJSON(); err != nil {
+			return nil, fmt.Errorf("invalid state override of %s: %w", addr, err)
+		}
+		set[normalized] = override
+	}
+	return set, nil
+}
+
+// stateOverrideHint points out that a node failing an eth_call with state overrides, other than by reverting,
+// may not support them
+func stateOverrideHint(err error) error {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || isRevert(rpcErr) {
+		return err
+	}
+	return fmt.Errorf("%w (hint: the node may not support eth_call state overrides)", err)
+}
@@ -0,0 +1,116 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverride_Serialize(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var params []json.RawMessage
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			var rpcReq struct {
+				Params []json.RawMessage `json:"params"`
+			}
+			require.NoError(t, json.Unmarshal(body, &rpcReq))
+			params = rpcReq.Params
+			return httpmock.NewStringResponse(http.StatusOK,
+				`{"jsonrpc":"2.0","id":1,"result":"0x00000000000000000000000000000000000000000000003635c9adc5dea00000"}`), nil
+		})
+
+	contractABIs := loadFixtureABIs(t)
+	balanceOf, err := contractABIs.Find("balanceOf")
+	require.NoError(t, err)
+	args := map[string]interface{}{"": testHolderAddr}
+
+	balance, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	nonce := uint64(5)
+	overrides := map[string]StateOverride{
+		"0x17f935d9b5E73C63b1CeC73f97dD988c5E2D9214": {Balance: balance, Nonce: &nonce},
+		testWMATICAddr: {
+			Code:      []byte{0x60, 0x80},
+			StateDiff: map[string]string{"0x1": "0x3635c9adc5dea00000"},
+		},
+	}
+
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+	ret, err := cli.ReadContract(ctx, testWMATICAddr, *balanceOf, args, WithStateOverride(overrides), WithBlockTag("pending"))
+	require.NoError(t, err)
+	assert.Equal(t, "1000000000000000000000", ret)
+
+	// Overrides are the third parameter, after the block
+	require.Len(t, params, 3)
+	assert.JSONEq(t, `"pending"`, string(params[1]))
+	assert.JSONEq(t, `{
+		"0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214": {"balance":"0x3635c9adc5dea00000","nonce":"0x5"},
+		"0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270": {
+			"code":"0x6080",
+			"stateDiff": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001":
+					"0x00000000000000000000000000000000000000000000003635c9adc5dea00000"
+			}
+		}
+	}`, string(params[2]))
+
+	// Without overrides only the call and the block are sent
+	_, err = cli.ReadContract(ctx, testWMATICAddr, *balanceOf, args)
+	require.NoError(t, err)
+	assert.Len(t, params, 2)
+
+	invalid := []map[string]StateOverride{
+		{"0x1234": {Balance: balance}},
+		{testWMATICAddr: {Balance: big.NewInt(-1)}},
+		{testWMATICAddr: {State: map[string]string{}, StateDiff: map[string]string{}}},
+		{testWMATICAddr: {State: map[string]string{"0x1": "0xzz"}}},
+		{testWMATICAddr: {StateDiff: map[string]string{"0x" + strings.Repeat("1", 66): "0x1"}}},
+	}
+	httpmock.ZeroCallCounters()
+	for _, overrides := range invalid {
+		_, err = cli.ReadContract(ctx, testWMATICAddr, *balanceOf, args, WithStateOverride(overrides))
+		assert.ErrorContains(t, err, "invalid state override")
+	}
+	assert.Zero(t, httpmock.GetTotalCallCount())
+}
+
+func TestOverride_Unsupported(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"too many arguments, want at most 2"}}`))
+
+	contractABIs := loadFixtureABIs(t)
+	decimals, err := contractABIs.Find("decimals")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+	overrides := WithStateOverride(map[string]StateOverride{testWMATICAddr: {Code: []byte{0x00}}})
+	_, err = cli.ReadContract(ctx, testWMATICAddr, *decimals, map[string]interface{}{}, overrides)
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, -32602, rpcErr.Code)
+	assert.ErrorContains(t, err, "may not support eth_call state overrides")
+
+	// Reverts are not blamed on the overrides
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted"}}`))
+	_, err = cli.ReadContract(ctx, testWMATICAddr, *decimals, map[string]interface{}{}, overrides)
+	assert.ErrorIs(t, err, ErrExecutionReverted)
+	assert.NotContains(t, err.Error(), "state overrides")
+}
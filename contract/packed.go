@@ -0,0 +1,128 @@
+package contract
+
+import (
+	"fmt"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// DecodePacked decodes data produced by abi.encodePacked, e.g. the preimage of a keccak256(abi.encodePacked(...)) key,
+// into values of the given types, returned like the outputs of ReadContractValues.
+//
+// Packed encoding isn't self-describing: values are laid out back to back in their minimal size, without offsets or
+// lengths, so decoding relies entirely on types. Decoding is best effort and limited accordingly:
+//   - uintN, intN, bytesN, address and bool take N/8, N, 20 and 1 bytes
+//   - elements of arrays are padded to a word each, as abi.encodePacked does
+//   - string, bytes and T[] have no length, so only the last type may be one of them and it takes the remaining data
+//   - tuples can't be packed and are rejected
+func DecodePacked(data string, types []string) ([]interface{}, error) {
+	raw, err := decodeData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &decoder{strictBool: true}
+	values := make([]interface{}, len(types))
+	pos := 0
+	for i, typ := range types {
+		typ = canonicalType(typ)
+		size, dynamic, err := packedSize(typ)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode packed value %d: %w", i, err)
+		}
+		if dynamic {
+			if i != len(types)-1 {
+				return nil, fmt.Errorf("dynamic type %s of packed value %d must be the last type", typ, i)
+			}
+			size = len(raw) - pos
+		}
+		if size > len(raw)-pos {
+			return nil, fmt.Errorf("packed data of %d bytes too short for %s at %d", len(raw), typ, pos)
+		}
+
+		values[i], err = d.decodePacked(typ, raw[pos:pos+size])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode packed value %d: %w", i, err)
+		}
+		pos += size
+	}
+
+	if pos != len(raw) {
+		return nil, fmt.Errorf("%d bytes of packed data left after decoding %d values", len(raw)-pos, len(types))
+	}
+	return values, nil
+}
+
+// packedSize returns the number of bytes a packed value of typ occupies, or whether it takes all the remaining data
+func packedSize(typ string) (int, bool, error) {
+	switch typ {
+	case "address":
+		return 20, false, nil
+	case "bool":
+		return 1, false, nil
+	case "string", "bytes":
+		return 0, true, nil
+	case "tuple":
+		return 0, false, fmt.Errorf("tuples can't be packed")
+	}
+
+	if elem, length, ok := parseArrayType(typ); ok {
+		if _, _, err := packedSize(elem); err != nil || isDynamic(abi.ABIParameter{Type: elem}) {
+			return 0, false, fmt.Errorf("unsupported packed array type: %s", typ)
+		}
+		if length < 0 {
+			return 0, true, nil
+		}
+		return length * wordSize, false, nil
+	}
+	if _, bits, ok := parseIntType(typ); ok {
+		return bits / 8, false, nil
+	}
+	if size, ok := parseFixedBytesType(typ); ok {
+		return size, false, nil
+	}
+	return 0, false, fmt.Errorf("unsupported packed type: %s", typ)
+}
+
+// decodePacked decodes the packed bytes b of a value of typ by widening them to the word the value is ABI encoded as
+func (d *decoder) decodePacked(typ string, b []byte) (interface{}, error) {
+	switch typ {
+	case "string":
+		return d.decodeString(b)
+	case "bytes":
+		value := make([]byte, len(b))
+		copy(value, b)
+		return value, nil
+	}
+
+	if elem, _, ok := parseArrayType(typ); ok {
+		if len(b)%wordSize != 0 {
+			return nil, fmt.Errorf("packed %s of %d bytes isn't a whole number of words", typ, len(b))
+		}
+		values := make([]interface{}, len(b)/wordSize)
+		for i := range values {
+			value, err := d.decodeStatic(elem, b[i*wordSize:(i+1)*wordSize])
+			if err != nil {
+				return nil, fmt.Errorf("invalid element %d: %w", i, err)
+			}
+			values[i] = value
+		}
+		return values, nil
+	}
+
+	word := make([]byte, wordSize)
+	if _, ok := parseFixedBytesType(typ); ok {
+		// bytesN is left aligned
+		copy(word, b)
+		return d.decodeStatic(typ, word)
+	}
+
+	// Numbers are right aligned, negative ones sign extended
+	if signed, _, ok := parseIntType(typ); ok && signed && len(b) > 0 && b[0]&0x80 != 0 {
+		for i := range word[:wordSize-len(b)] {
+			word[i] = 0xff
+		}
+	}
+	copy(word[wordSize-len(b):], b)
+	return d.decodeStatic(typ, word)
+}
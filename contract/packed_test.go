@@ -0,0 +1,49 @@
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacked_DecodePacked(t *testing.T) {
+	// abi.encodePacked(address(0x17f9...9214), uint256(1000))
+	data := "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214" +
+		"00000000000000000000000000000000000000000000000000000000000003e8"
+	values, err := DecodePacked(data, []string{"address", "uint256"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214", big.NewInt(1000)}, values)
+
+	// abi.encodePacked(int16(-2), bool(true), bytes4(0xdeadbeef), uint8(7), "hello")
+	values, err = DecodePacked("0xfffe01deadbeef0768656c6c6f", []string{"int16", "bool", "bytes4", "uint8", "string"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(-2), true, []byte{0xde, 0xad, 0xbe, 0xef}, big.NewInt(7), "hello"}, values)
+
+	// Array elements are padded to a word
+	values, err = DecodePacked("0x01"+
+		"0000000000000000000000000000000000000000000000000000000000000002"+
+		"0000000000000000000000000000000000000000000000000000000000000003", []string{"uint8", "uint16[]"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(1), []interface{}{big.NewInt(2), big.NewInt(3)}}, values)
+
+	tests := []struct {
+		name  string
+		data  string
+		types []string
+		err   string
+	}{
+		{name: "dynamic not last", data: "0x6869ff", types: []string{"string", "uint8"}, err: "must be the last type"},
+		{name: "too short", data: "0x17f935d9", types: []string{"address"}, err: "too short"},
+		{name: "trailing data", data: "0x0102", types: []string{"uint8"}, err: "left after decoding"},
+		{name: "invalid bool", data: "0x02", types: []string{"bool"}, err: "invalid bool"},
+		{name: "tuple", data: "0x01", types: []string{"tuple"}, err: "can't be packed"},
+		{name: "unknown type", data: "0x01", types: []string{"uint7"}, err: "unsupported packed type"},
+	}
+
+	for _, test := range tests {
+		_, err := DecodePacked(test.data, test.types)
+		assert.ErrorContains(t, err, test.err, test.name)
+	}
+}
@@ -0,0 +1,50 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// EIP-1967 storage slots, each being keccak256 of the slot name minus one
+const (
+	eip1967ImplementationSlot = "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"
+	eip1967AdminSlot          = "0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103"
+	eip1967BeaconSlot         = "0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50"
+)
+
+// ZeroAddress is the address returned when a proxy slot is empty
+const ZeroAddress = "0x0000000000000000000000000000000000000000"
+
+// ErrEmptyProxySlot is returned along with ZeroAddress when the requested EIP-1967 slot is not set,
+// which usually means the contract is not a proxy of that kind.
+var ErrEmptyProxySlot = errors.New("proxy slot is empty")
+
+// GetProxyImplementation returns the implementation address stored in the EIP-1967 implementation slot
+func (c *contractClient) GetProxyImplementation(ctx context.Context, addr string) (string, error) {
+	return c.readAddressSlot(ctx, addr, eip1967ImplementationSlot)
+}
+
+// GetProxyAdmin returns the admin address stored in the EIP-1967 admin slot
+func (c *contractClient) GetProxyAdmin(ctx context.Context, addr string) (string, error) {
+	return c.readAddressSlot(ctx, addr, eip1967AdminSlot)
+}
+
+// GetBeacon returns the beacon address stored in the EIP-1967 beacon slot
+func (c *contractClient) GetBeacon(ctx context.Context, addr string) (string, error) {
+	return c.readAddressSlot(ctx, addr, eip1967BeaconSlot)
+}
+
+func (c *contractClient) readAddressSlot(ctx context.Context, addr, slot string) (string, error) {
+	word, err := c.GetStorageAt(ctx, addr, slot)
+	if err != nil {
+		return "", err
+	}
+
+	// The address occupies the low 20 bytes of the word
+	proxyAddr := "0x" + word[len(word)-40:]
+	if strings.TrimLeft(word[2:], "0") == "" {
+		return ZeroAddress, ErrEmptyProxySlot
+	}
+	return proxyAddr, nil
+}
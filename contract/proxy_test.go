@@ -0,0 +1,57 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContract_Proxy(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	slots := map[string]string{
+		eip1967ImplementationSlot: "0x000000000000000000000000a2327a938febf5fec13bacfb16ae10ecbc4cbdcf",
+		eip1967AdminSlot:          "0x807a96288a1a408dbc13de2b1d087d10356395d2",
+		eip1967BeaconSlot:         "0x0000000000000000000000000000000000000000000000000000000000000000",
+	}
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+
+			var rpcReq rpcRequest
+			require.NoError(t, json.Unmarshal(body, &rpcReq))
+			require.Equal(t, "eth_getStorageAt", rpcReq.Method)
+			require.Len(t, rpcReq.Params, 3)
+
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      rpcReq.ID,
+				"result":  slots[rpcReq.Params[1].(string)],
+			})
+		})
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	proxyAddr := "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+
+	impl, err := cli.GetProxyImplementation(ctx, proxyAddr)
+	require.NoError(t, err)
+	assert.Equal(t, "0xa2327a938febf5fec13bacfb16ae10ecbc4cbdcf", impl)
+
+	// Nodes may return the word without leading zeros
+	admin, err := cli.GetProxyAdmin(ctx, proxyAddr)
+	require.NoError(t, err)
+	assert.Equal(t, "0x807a96288a1a408dbc13de2b1d087d10356395d2", admin)
+
+	beacon, err := cli.GetBeacon(ctx, proxyAddr)
+	require.ErrorIs(t, err, ErrEmptyProxySlot)
+	assert.Equal(t, ZeroAddress, beacon)
+}
@@ -0,0 +1,227 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// ErrReceiptNotFound is returned when a transaction has no receipt yet, because it is pending or unknown to the node
+var ErrReceiptNotFound = errors.New("transaction receipt not found")
+
+// Receipt is a transaction receipt as returned by eth_getTransactionReceipt.
+// Quantities are kept as the hex strings returned by the node.
+type Receipt struct {
+	TransactionHash   string   `json:"transactionHash"`
+	TransactionIndex  string   `json:"transactionIndex"`
+	BlockHash         string   `json:"blockHash"`
+	BlockNumber       string   `json:"blockNumber"`
+	From              string   `json:"from"`
+	To                string   `json:"to"`
+	CumulativeGasUsed string   `json:"cumulativeGasUsed"`
+	GasUsed           string   `json:"gasUsed"`
+	ContractAddress   string   `json:"contractAddress"`
+	Logs              []RawLog `json:"logs"`
+	LogsBloom         string   `json:"logsBloom"`
+	Status            string   `json:"status"` // Since Byzantium, replacing Root
+	Root              string   `json:"root"`
+	Type              string   `json:"type"`              // Since EIP-2718, absent from legacy receipts of older nodes
+	EffectiveGasPrice string   `json:"effectiveGasPrice"` // Since London
+}
+
+// EIP-2718 transaction types
+const (
+	TxTypeLegacy     uint8 = 0
+	TxTypeAccessList uint8 = 1
+	TxTypeDynamicFee uint8 = 2
+	TxTypeBlob       uint8 = 3
+)
+
+// Succeeded reports whether the transaction succeeded, i.e. whether its status is 1.
+// Receipts from before Byzantium carry a state root instead of a status, which is an error.
+func (r *Receipt) Succeeded() (bool, error) {
+	if r.Status == "" {
+		return false, fmt.Errorf("receipt of %s has no status", r.TransactionHash)
+	}
+
+	status, err := decodeQuantity(r.Status)
+	if err != nil {
+		return false, fmt.Errorf("invalid receipt status: %w", err)
+	}
+	switch status.Int64() {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid receipt status %s", r.Status)
+	}
+}
+
+// TransactionType returns the EIP-2718 type of the transaction, TxTypeLegacy when the receipt has none
+func (r *Receipt) TransactionType() (uint8, error) {
+	if r.Type == "" {
+		return TxTypeLegacy, nil
+	}
+
+	typ, err := decodeQuantity(r.Type)
+	if err != nil {
+		return 0, fmt.Errorf("invalid receipt type: %w", err)
+	}
+	if !typ.IsUint64() || typ.Uint64() > 0x7f {
+		return 0, fmt.Errorf("invalid receipt type %s", r.Type)
+	}
+	return uint8(typ.Uint64()), nil
+}
+
+// EffectiveGasPriceWei returns the price per gas actually paid, in wei.
+// Receipts from nodes predating London have no effective gas price, which is an error.
+func (r *Receipt) EffectiveGasPriceWei() (*big.Int, error) {
+	if r.EffectiveGasPrice == "" {
+		return nil, fmt.Errorf("receipt of %s has no effective gas price", r.TransactionHash)
+	}
+
+	price, err := decodeQuantity(r.EffectiveGasPrice)
+	if err != nil {
+		return nil, fmt.Errorf("invalid effective gas price: %w", err)
+	}
+	return price, nil
+}
+
+// DecodeLogs decodes the logs of the receipt matching one of events, like DecodeReceiptLogs
+func (r *Receipt) DecodeLogs(events ...abi.ContractABI) ([]DecodedLog, error) {
+	return decodeLogs(r.Logs, events)
+}
+
+// DecodedLog is a receipt log decoded against a known event
+type DecodedLog struct {
+	Log    RawLog
+	Event  string
+	Fields map[string]interface{}
+	// Err is set, and Fields nil, when the log has the topic0 of Event but doesn't decode against it,
+	// e.g. an ERC-721 Transfer, which shares the topic0 of the ERC-20 Transfer with a different indexed layout
+	Err error
+}
+
+// GetTransactionReceipt fetches the receipt of txHash via eth_getTransactionReceipt.
+// It returns ErrReceiptNotFound while the transaction is not yet mined.
+func (c *contractClient) GetTransactionReceipt(ctx context.Context, txHash string) (*Receipt, error) {
+	result, err := c.callJSON(ctx, "eth_getTransactionReceipt", txHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 || string(result) == "null" {
+		return nil, fmt.Errorf("%w: %s", ErrReceiptNotFound, txHash)
+	}
+
+	var receipt Receipt
+	if err := json.Unmarshal(result, &receipt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal receipt %s: %w", snippet(result), err)
+	}
+	return &receipt, nil
+}
+
+// DecodeReceiptLogs decodes the logs of receipt matching one of events, in log order.
+// Logs emitted by events not in events are skipped, and logs which fail to decode keep their error in DecodedLog.Err.
+func DecodeReceiptLogs(receipt *Receipt, events []abi.ContractABI) ([]DecodedLog, error) {
+	return decodeLogs(receipt.Logs, events)
+}
+
+// decodeLogs decodes the logs matching one of events, skipping the others.
+// A log failing to decode is reported in its Err rather than failing the others.
+func decodeLogs(logs []RawLog, events []abi.ContractABI) ([]DecodedLog, error) {
+	logDecoder, err := NewLogDecoder(events...)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := []DecodedLog{}
+	for i, log := range logs {
+		name, fields, err := logDecoder.Decode(log)
+		if err != nil {
+			event := logDecoder.events[strings.ToLower(log.Topics[0])]
+			decoded = append(decoded, DecodedLog{Log: log, Event: event.Name, Err: fmt.Errorf("failed to decode log %d: %w", i, err)})
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		decoded = append(decoded, DecodedLog{Log: log, Event: name, Fields: fields})
+	}
+	return decoded, nil
+}
+
+// maxPollBackoff bounds how far WaitForReceipt stretches its poll interval after node errors
+const maxPollBackoff = 8
+
+// WaitForReceipt polls for the receipt of txHash every pollInterval until it is mined
+// and has the requested confirmations, or ctx is done.
+// Node errors are retried with the interval doubled on each consecutive failure, up to 8 times pollInterval.
+func (c *contractClient) WaitForReceipt(ctx context.Context, txHash string, pollInterval time.Duration, opts ...WaitOption) (*Receipt, error) {
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("invalid poll interval: %s", pollInterval)
+	}
+	o := newWaitOptions(opts)
+
+	delay := pollInterval
+	var lastErr error
+	for {
+		receipt, err := c.confirmedReceipt(ctx, txHash, o.confirmations)
+		switch {
+		case err == nil && receipt != nil:
+			return receipt, nil
+		case err == nil:
+			delay, lastErr = pollInterval, nil
+		case ctx.Err() != nil:
+			return nil, err
+		default:
+			lastErr = err
+			delay = min(delay*2, pollInterval*maxPollBackoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("waiting for receipt %s: %w (last error: %v)", txHash, ctx.Err(), lastErr)
+			}
+			return nil, fmt.Errorf("waiting for receipt %s: %w", txHash, ctx.Err())
+		case <-c.clock.After(delay):
+		}
+	}
+}
+
+// confirmedReceipt returns the receipt of txHash once its block has the given confirmations,
+// or nil while the transaction is pending or not deep enough
+func (c *contractClient) confirmedReceipt(ctx context.Context, txHash string, confirmations uint64) (*Receipt, error) {
+	receipt, err := c.GetTransactionReceipt(ctx, txHash)
+	if errors.Is(err, ErrReceiptNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if confirmations <= 1 {
+		return receipt, nil
+	}
+
+	included, err := decodeQuantity(receipt.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt block number: %w", err)
+	}
+	head, err := c.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := new(big.Int).Sub(head, included)
+	if depth.Cmp(new(big.Int).SetUint64(confirmations-1)) < 0 {
+		return nil, nil
+	}
+	return receipt, nil
+}
@@ -0,0 +1,197 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiptJSON returns a mined receipt of testTxHash containing transferLog and a log of an unknown event
+func receiptJSON(t *testing.T) string {
+	t.Helper()
+
+	unknownLog := RawLog{
+		Address: "0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270",
+		Topics:  []string{"0x7fcf532c15f0a6db0bd6d0e038bea71d30d808c7d98cb3bf7268a95bf5081b65"},
+		Data:    "0x",
+	}
+	receipt := map[string]interface{}{
+		"transactionHash":   testTxHash,
+		"transactionIndex":  "0x1",
+		"blockHash":         "0x3fe0e85c1ab3b57a7a6e8f2b9e4d3f7f3d29a5c9f3d0c3b2d5b5e1a3f4c2b1a0",
+		"blockNumber":       "0x3d0900",
+		"from":              "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		"to":                "0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270",
+		"cumulativeGasUsed": "0x1f4a0",
+		"gasUsed":           "0xb411",
+		"contractAddress":   nil,
+		"logs":              []RawLog{transferLog, unknownLog},
+		"logsBloom":         "0x00",
+		"status":            "0x1",
+	}
+	b, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": receipt})
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestReceipt_GetAndDecode(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, receiptJSON(t)))
+
+	receipt, err := cli.GetTransactionReceipt(ctx, testTxHash)
+	require.NoError(t, err)
+	assert.Equal(t, testTxHash, receipt.TransactionHash)
+	assert.Equal(t, "0x1", receipt.Status)
+	assert.Empty(t, receipt.ContractAddress)
+	require.Len(t, receipt.Logs, 2)
+
+	transferEvent, err := loadFixtureABIs(t).Find("Transfer")
+	require.NoError(t, err)
+
+	logs, err := DecodeReceiptLogs(receipt, []abi.ContractABI{*transferEvent})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "Transfer", logs[0].Event)
+	assert.Equal(t, big.NewInt(1000000000000000000), logs[0].Fields["wad"])
+	assert.Equal(t, transferLog, logs[0].Log)
+}
+
+func TestReceipt_Pending(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":null}`))
+
+	cli := NewClient(testRPCURL)
+	receipt, err := cli.GetTransactionReceipt(context.Background(), testTxHash)
+	require.ErrorIs(t, err, ErrReceiptNotFound)
+	assert.Nil(t, receipt)
+}
+
+func TestReceipt_WaitForReceipt(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var mu sync.Mutex
+	receiptCalls := 0
+	head := "0x3d0900" // the inclusion block
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			var rpcReq rpcRequest
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if rpcReq.Method == "eth_blockNumber" {
+				result := head
+				head = "0x3d0902"
+				return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"`+result+`"}`), nil
+			}
+
+			require.Equal(t, "eth_getTransactionReceipt", rpcReq.Method)
+			receiptCalls++
+			switch receiptCalls {
+			case 1:
+				return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":null}`), nil
+			case 2:
+				// A transient node failure is retried
+				return httpmock.NewStringResponse(http.StatusBadGateway, ""), nil
+			default:
+				return httpmock.NewStringResponse(http.StatusOK, receiptJSON(t)), nil
+			}
+		})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cli := NewClient(testRPCURL)
+	receipt, err := cli.WaitForReceipt(ctx, testTxHash, 10*time.Millisecond, WithConfirmations(3))
+	require.NoError(t, err)
+	assert.Equal(t, testTxHash, receipt.TransactionHash)
+
+	// The first mined poll saw a single confirmation, the next one three
+	mu.Lock()
+	assert.Equal(t, 4, receiptCalls)
+	mu.Unlock()
+}
+
+func TestReceipt_WaitForReceiptTimeout(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":null}`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	cli := NewClient(testRPCURL)
+	_, err := cli.WaitForReceipt(ctx, testTxHash, 10*time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestReceipt_Types(t *testing.T) {
+	transferEvent, err := loadFixtureABIs(t).Find("Transfer")
+	require.NoError(t, err)
+
+	// A failed EIP-1559 transaction
+	var typed Receipt
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"transactionHash": "`+testTxHash+`",
+		"status": "0x0",
+		"type": "0x2",
+		"effectiveGasPrice": "0x6fc23ac00",
+		"logs": []
+	}`), &typed))
+
+	succeeded, err := typed.Succeeded()
+	require.NoError(t, err)
+	assert.False(t, succeeded)
+	txType, err := typed.TransactionType()
+	require.NoError(t, err)
+	assert.Equal(t, TxTypeDynamicFee, txType)
+	price, err := typed.EffectiveGasPriceWei()
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(30000000000), price)
+
+	// A successful legacy transaction from a node predating EIP-2718 and London
+	var legacy Receipt
+	require.NoError(t, json.Unmarshal([]byte(`{"transactionHash": "`+testTxHash+`", "status": "0x1"}`), &legacy))
+	legacy.Logs = []RawLog{transferLog}
+
+	succeeded, err = legacy.Succeeded()
+	require.NoError(t, err)
+	assert.True(t, succeeded)
+	txType, err = legacy.TransactionType()
+	require.NoError(t, err)
+	assert.Equal(t, TxTypeLegacy, txType)
+	_, err = legacy.EffectiveGasPriceWei()
+	assert.ErrorContains(t, err, "no effective gas price")
+
+	logs, err := legacy.DecodeLogs(*transferEvent)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "Transfer", logs[0].Event)
+
+	// Pre-Byzantium receipts have a state root instead of a status
+	preByzantium := Receipt{Root: "0x3fe0e85c1ab3b57a7a6e8f2b9e4d3f7f3d29a5c9f3d0c3b2d5b5e1a3f4c2b1a0"}
+	_, err = preByzantium.Succeeded()
+	assert.ErrorContains(t, err, "no status")
+}
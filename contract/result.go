@@ -0,0 +1,112 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// CallResult is a decoded output of a read along with its ABI parameter.
+// Its accessors return the value as the Go type of the output type, and fail with ErrTypeMismatch
+// for any other type rather than converting the value, e.g. BigInt on a string output.
+type CallResult struct {
+	// Param is the output the value was decoded from
+	Param abi.ABIParameter
+	value interface{}
+}
+
+// ReadContractResults reads the contract like ReadContractValues and returns each output as a CallResult, in order.
+// A function returning a single tuple returns the components of the tuple as its outputs.
+func (c *contractClient) ReadContractResults(ctx context.Context, addr string, abi abi.ContractABI, args map[string]interface{}, opts ...CallOption) ([]CallResult, error) {
+	abi, values, err := c.readValues(ctx, addr, abi, args, opts)
+	if err != nil {
+		return nil, err
+	}
+	return callResults(abi.Outputs, values), nil
+}
+
+// callResults pairs decoded values with the outputs they were decoded from
+func callResults(outputs []abi.ABIParameter, values []interface{}) []CallResult {
+	results := make([]CallResult, len(values))
+	for i, value := range values {
+		results[i] = CallResult{Param: outputs[i], value: value}
+	}
+	return results
+}
+
+// Value returns the decoded value, with the Go type ReadContractValues returns it as
+func (r CallResult) Value() interface{} {
+	return r.value
+}
+
+// BigInt returns the value of an intN or uintN output
+func (r CallResult) BigInt() (*big.Int, error) {
+	if _, _, ok := parseIntType(r.typ()); !ok {
+		return nil, r.mismatch("*big.Int")
+	}
+
+	// Options like WithUint256Outputs and WithJSONNumbers change how integers are decoded
+	switch v := r.value.(type) {
+	case *big.Int:
+		return new(big.Int).Set(v), nil
+	case Uint256:
+		return v.Big(), nil
+	case json.Number:
+		n, ok := new(big.Int).SetString(v.String(), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+		return n, nil
+	}
+	return nil, r.mismatch("*big.Int")
+}
+
+// Bool returns the value of a bool output
+func (r CallResult) Bool() (bool, error) {
+	v, ok := r.value.(bool)
+	if !ok || r.typ() != "bool" {
+		return false, r.mismatch("bool")
+	}
+	return v, nil
+}
+
+// Address returns the value of an address output as a 0x-prefixed hex string
+func (r CallResult) Address() (string, error) {
+	v, ok := r.value.(string)
+	if !ok || r.typ() != "address" {
+		return "", r.mismatch("address")
+	}
+	return v, nil
+}
+
+// String returns the value of a string output.
+// Unlike fmt.Stringer, it fails for outputs of other types instead of formatting them.
+func (r CallResult) String() (string, error) {
+	v, ok := r.value.(string)
+	if !ok || r.typ() != "string" {
+		return "", r.mismatch("string")
+	}
+	return v, nil
+}
+
+// Bytes returns the value of a bytes or bytesN output
+func (r CallResult) Bytes() ([]byte, error) {
+	_, fixed := parseFixedBytesType(r.typ())
+	v, ok := r.value.([]byte)
+	if !ok || (r.typ() != "bytes" && !fixed) {
+		return nil, r.mismatch("[]byte")
+	}
+	return append([]byte(nil), v...), nil
+}
+
+// typ returns the canonical type of the output
+func (r CallResult) typ() string {
+	return canonicalType(r.Param.Type)
+}
+
+func (r CallResult) mismatch(target string) error {
+	return fmt.Errorf("%w: %s output %q can't be read as %s", ErrTypeMismatch, r.Param.Type, r.Param.Name, target)
+}
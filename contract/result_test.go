@@ -0,0 +1,87 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResult_Accessors(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// position() returns (uint256 amount, address owner, bool active, string label, bytes32 id)
+	position := abi.ContractABI{
+		Type: "function",
+		Name: "position",
+		Outputs: []abi.ABIParameter{
+			{Name: "amount", Type: "uint256"},
+			{Name: "owner", Type: "address"},
+			{Name: "active", Type: "bool"},
+			{Name: "label", Type: "string"},
+			{Name: "id", Type: "bytes32"},
+		},
+	}
+	amount, ok := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	require.True(t, ok)
+	returnData, err := EncodeReturn(position.Outputs, []interface{}{
+		amount,
+		"0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		true,
+		"main",
+		[]byte{0xab, 0xcd},
+	})
+	require.NoError(t, err)
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"`+returnData+`"}`))
+
+	cli := NewClient(testRPCURL)
+	results, err := cli.ReadContractResults(context.Background(), testTokenAddr, position, map[string]interface{}{})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	assert.Equal(t, "amount", results[0].Param.Name)
+
+	// uint256 keeps every digit
+	n, err := results[0].BigInt()
+	require.NoError(t, err)
+	assert.Equal(t, amount, n)
+	addr, err := results[1].Address()
+	require.NoError(t, err)
+	assert.Equal(t, "0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214", addr)
+	active, err := results[2].Bool()
+	require.NoError(t, err)
+	assert.True(t, active)
+	label, err := results[3].String()
+	require.NoError(t, err)
+	assert.Equal(t, "main", label)
+	id, err := results[4].Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, append([]byte{0xab, 0xcd}, make([]byte, 30)...), id)
+
+	// The wrong accessor fails rather than converting
+	_, err = results[0].Address()
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	_, err = results[1].BigInt()
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	_, err = results[1].String()
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	_, err = results[3].Bytes()
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	_, err = results[4].Bool()
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+
+	// Integers decoded differently by options are still returned as *big.Int
+	for _, opt := range []CallOption{WithUint256Outputs(), WithJSONNumbers()} {
+		results, err := cli.ReadContractResults(context.Background(), testTokenAddr, position, map[string]interface{}{}, opt)
+		require.NoError(t, err)
+		n, err := results[0].BigInt()
+		require.NoError(t, err)
+		assert.Equal(t, amount, n)
+	}
+}
@@ -0,0 +1,24 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/rootwarp/vinculum/contract/internal/retry"
+)
+
+// retryTransport retries calls of next which fail with a transient error, as reported by retry.IsTransient.
+// Attempts are spaced by an exponential backoff from baseDelay with jitter, until maxAttempts calls
+// were made or ctx is done.
+type retryTransport struct {
+	next        transport
+	maxAttempts int
+	baseDelay   time.Duration
+	clock       Clock
+}
+
+func (t *retryTransport) Call(ctx context.Context, payload []byte) ([]byte, error) {
+	return retry.Do(ctx, t.maxAttempts, t.baseDelay, t.clock.After, func() ([]byte, error) {
+		return t.next.Call(ctx, payload)
+	})
+}
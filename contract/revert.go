@@ -0,0 +1,232 @@
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// CustomError is a revert with a custom error declared in the contract ABI, along with its decoded arguments
+type CustomError struct {
+	Name   string
+	Params []abi.ABIParameter
+	Values []interface{}
+}
+
+// Error formats the custom error like InsufficientAllowance(owner=0x.., spender=0x.., needed=100)
+func (e *CustomError) Error() string {
+	keys := fieldKeys(e.Params)
+	args := make([]string, len(e.Values))
+	for i, value := range e.Values {
+		args[i] = keys[i] + "=" + formatValue(value)
+	}
+	return fmt.Sprintf("%s: %s(%s)", ErrExecutionReverted, e.Name, strings.Join(args, ", "))
+}
+
+// Unwrap makes custom errors match ErrExecutionReverted
+func (e *CustomError) Unwrap() error {
+	return ErrExecutionReverted
+}
+
+// RevertError is a revert with a reason string, e.g. from require(condition, "reason"), encoded as Error(string)
+type RevertError struct {
+	Reason string
+}
+
+// Error formats the revert like the node would, e.g. "execution reverted: ERC20: transfer amount exceeds balance"
+func (e *RevertError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrExecutionReverted, e.Reason)
+}
+
+// Unwrap makes reverts with a reason match ErrExecutionReverted
+func (e *RevertError) Unwrap() error {
+	return ErrExecutionReverted
+}
+
+// PanicError is a revert raised by a failed assert or a runtime error like a division by zero, encoded as Panic(uint256)
+type PanicError struct {
+	Code   *big.Int
+	Reason string
+}
+
+// Error formats the panic with its code and reason, e.g. "execution reverted: panic 0x12: division or modulo by zero"
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: panic 0x%x: %s", ErrExecutionReverted, e.Code, e.Reason)
+}
+
+// Unwrap makes panics match ErrExecutionReverted
+func (e *PanicError) Unwrap() error {
+	return ErrExecutionReverted
+}
+
+const (
+	// errorStringSelector is the selector of Error(string), the revert of require and revert with a reason
+	errorStringSelector = "08c379a0"
+	// panicSelector is the selector of Panic(uint256), the revert of assert and runtime errors
+	panicSelector = "4e487b71"
+)
+
+// panicReasons describes the panic codes of the Solidity compiler
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized internal function",
+}
+
+// decodeBuiltinRevert decodes revert data as an Error(string) or a Panic(uint256), which any contract may revert with.
+// It returns nil for other or malformed data.
+func (d *decoder) decodeBuiltinRevert(data []byte) error {
+	if len(data) < 4 {
+		return nil
+	}
+
+	switch fmt.Sprintf("%x", data[:4]) {
+	case errorStringSelector:
+		values, err := d.decodeValues([]abi.ABIParameter{{Type: "string"}}, data[4:])
+		if err != nil {
+			return nil
+		}
+		return &RevertError{Reason: values[0].(string)}
+	case panicSelector:
+		values, err := d.decodeValues([]abi.ABIParameter{{Type: "uint256"}}, data[4:])
+		if err != nil {
+			return nil
+		}
+		code := values[0].(*big.Int)
+		reason := "unknown panic code"
+		if code.IsUint64() {
+			if known, ok := panicReasons[code.Uint64()]; ok {
+				reason = known
+			}
+		}
+		return &PanicError{Code: code, Reason: reason}
+	default:
+		return nil
+	}
+}
+
+// decodeRevert decodes revert data as an Error(string), a Panic(uint256), or the custom error of errs
+// with a matching selector. Data of unknown errors is reported by its selector.
+func (d *decoder) decodeRevert(data []byte, errs []abi.ContractABI) error {
+	if len(data) < 4 {
+		return fmt.Errorf("%w: 0x%x", ErrExecutionReverted, data)
+	}
+	if revert := d.decodeBuiltinRevert(data); revert != nil {
+		return revert
+	}
+
+	selector := fmt.Sprintf("%x", data[:4])
+	for _, entry := range errs {
+		if id, err := entry.ErrorSelector(); err != nil || id != selector {
+			continue
+		}
+		values, err := d.decodeValues(entry.Inputs, data[4:])
+		if err != nil {
+			return fmt.Errorf("%w: failed to decode custom error %s: %w", ErrExecutionReverted, entry.Name, err)
+		}
+		return &CustomError{Name: entry.Name, Params: entry.Inputs, Values: values}
+	}
+	return fmt.Errorf("%w: unknown custom error 0x%s", ErrExecutionReverted, selector)
+}
+
+// revertPhrasings are fragments of the lowercased messages providers answer reverted calls with.
+// Messages differ between clients, so matching is by substring.
+var revertPhrasings = []string{
+	"execution reverted", // geth, Erigon, Infura, Alchemy, QuickNode, Anvil
+	"vm execution error", // OpenEthereum, Nethermind
+	"vm exception",       // Ganache, Hardhat
+	"revert",             // other phrasings, e.g. "Reverted 0x..." or "transaction reverted"
+}
+
+// revertErrorCode is the JSON-RPC error code geth and its derivatives answer reverts with
+const revertErrorCode = 3
+
+// isRevert reports whether the node answered with a revert, whatever its phrasing
+func isRevert(rpcErr *RPCError) bool {
+	if rpcErr.Code == revertErrorCode {
+		return true
+	}
+	msg := strings.ToLower(rpcErr.Message)
+	for _, phrasing := range revertPhrasings {
+		if strings.Contains(msg, phrasing) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRevert wraps RPC errors reporting a revert with ErrExecutionReverted, keeping the original message
+func normalizeRevert(err error) error {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && isRevert(rpcErr) {
+		return fmt.Errorf("%w: %w", ErrExecutionReverted, err)
+	}
+	return err
+}
+
+// revertError decodes the revert data of an RPC error when it is a reason or a panic, or when errs are declared,
+// keeping the RPC error as a cause, and otherwise normalizes it with normalizeRevert
+func (c *contractClient) revertError(err error, errs []abi.ContractABI) error {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return err
+	}
+	if data, ok := rpcErr.revertData(); ok {
+		if len(errs) > 0 {
+			return &decodedRevert{revert: c.decoder().decodeRevert(data, errs), rpcErr: rpcErr}
+		}
+		if revert := c.decoder().decodeBuiltinRevert(data); revert != nil {
+			return &decodedRevert{revert: revert, rpcErr: rpcErr}
+		}
+	}
+	return normalizeRevert(err)
+}
+
+// decodedRevert is a revert decoded from the data of an RPC error. It reads as the decoded revert,
+// and unwraps to both so that errors.As finds the RevertError, PanicError or CustomError as well as the RPCError.
+type decodedRevert struct {
+	revert error
+	rpcErr *RPCError
+}
+
+func (e *decodedRevert) Error() string {
+	return e.revert.Error()
+}
+
+func (e *decodedRevert) Unwrap() []error {
+	return []error{e.revert, e.rpcErr}
+}
+
+// mutabilityHint points out that a function declared view or pure may not be read-safe when it reverts without data.
+// Intentional reverts usually carry a reason or a custom error, while a function mislabeled in the ABI
+// may revert on what it needs to write or receive.
+func mutabilityHint(err error, fn abi.ContractABI) error {
+	mutability := declaredMutability(fn)
+	if mutability != "view" && mutability != "pure" {
+		return err
+	}
+	return fmt.Errorf("%w (hint: %s is declared %s, but it may not be read-safe despite its declared mutability)", err, fn.Name, mutability)
+}
+
+// declaredMutability returns the state mutability of fn, deriving it from the legacy constant and payable flags of old ABIs
+func declaredMutability(fn abi.ContractABI) string {
+	switch {
+	case fn.StateMutability != "":
+		return fn.StateMutability
+	case fn.Constant:
+		return "view"
+	case fn.Payable:
+		return "payable"
+	}
+	return ""
+}
@@ -0,0 +1,230 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevert_CustomError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	insufficientAllowance := abi.ContractABI{
+		Type: "error",
+		Name: "InsufficientAllowance",
+		Inputs: []abi.ABIParameter{
+			{Name: "owner", Type: "address"},
+			{Name: "needed", Type: "uint256"},
+		},
+	}
+	pull := abi.ContractABI{
+		Type: "function",
+		Name: "pull",
+		Inputs: []abi.ABIParameter{
+			{Name: "amount", Type: "uint256"},
+		},
+		Outputs: []abi.ABIParameter{{Type: "bool"}},
+	}
+
+	selector, err := insufficientAllowance.ErrorSelector()
+	require.NoError(t, err)
+	args, err := EncodeReturn(insufficientAllowance.Inputs, []interface{}{
+		"0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214",
+		big.NewInt(100),
+	})
+	require.NoError(t, err)
+	revertData := "0x" + selector + args[2:]
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(
+			`{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted","data":%q}}`, revertData)))
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	callArgs := map[string]interface{}{"amount": big.NewInt(100)}
+
+	_, err = cli.ReadContractValues(ctx, testTokenAddr, pull, callArgs, WithErrorABIs(insufficientAllowance))
+	require.ErrorIs(t, err, ErrExecutionReverted)
+	var customErr *CustomError
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, "InsufficientAllowance", customErr.Name)
+	assert.Equal(t, []interface{}{"0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214", big.NewInt(100)}, customErr.Values)
+	assert.EqualError(t, err, "execution reverted: InsufficientAllowance(owner=0x17f935d9b5e73c63b1cec73f97dd988c5e2d9214, needed=100)")
+
+	// The decoded revert keeps the RPC error the node answered with
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, 3, rpcErr.Code)
+	assert.Equal(t, "execution reverted", rpcErr.Message)
+
+	// The facade declares the errors of its ABI
+	c := NewContract(testTokenAddr, abi.ContractABIs{pull, insufficientAllowance}, cli)
+	_, err = c.Read(ctx, "pull", big.NewInt(100))
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, "InsufficientAllowance", customErr.Name)
+
+	// Unknown errors fall back to the selector
+	otherError := abi.ContractABI{Type: "error", Name: "Paused"}
+	_, err = cli.ReadContractValues(ctx, testTokenAddr, pull, callArgs, WithErrorABIs(otherError))
+	require.ErrorIs(t, err, ErrExecutionReverted)
+	assert.EqualError(t, err, "execution reverted: unknown custom error 0x"+selector)
+	assert.ErrorAs(t, err, &rpcErr)
+
+	// Without declared errors the RPC error is kept
+	_, err = cli.ReadContractValues(ctx, testTokenAddr, pull, callArgs)
+	assert.ErrorIs(t, err, ErrExecutionReverted)
+	assert.ErrorContains(t, err, "rpc error 3: execution reverted")
+}
+
+func TestRevert_ProviderPhrasings(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	tests := []struct {
+		provider string
+		error    string
+		reverted bool
+	}{
+		{"geth", `{"code":3,"message":"execution reverted: ERC20: transfer amount exceeds balance","data":"0x08c379a0"}`, true},
+		{"infura", `{"code":-32000,"message":"execution reverted"}`, true},
+		{"alchemy", `{"code":3,"message":"execution reverted","data":"0x"}`, true},
+		{"nethermind", `{"code":-32015,"message":"VM execution error.","data":"Reverted 0x"}`, true},
+		{"ganache", `{"code":-32000,"message":"VM Exception while processing transaction: revert"}`, true},
+		{"hardhat", `{"code":-32603,"message":"Error: VM Exception while processing transaction: reverted with reason string 'paused'"}`, true},
+		{"erigon", `{"code":-32000,"message":"Reverted 0x"}`, true},
+		{"no revert", `{"code":-32000,"message":"header not found"}`, false},
+	}
+
+	decimals, err := loadFixtureABIs(t).Find("decimals")
+	require.NoError(t, err)
+	cli := NewClient(testRPCURL)
+
+	for _, tt := range tests {
+		httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+			httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":`+tt.error+`}`))
+
+		_, err := cli.ReadContract(context.Background(), testWMATICAddr, *decimals, map[string]interface{}{})
+		require.Error(t, err, tt.provider)
+		assert.Equal(t, tt.reverted, errors.Is(err, ErrExecutionReverted), tt.provider)
+
+		// The original message is preserved
+		var rpcErr *RPCError
+		require.ErrorAs(t, err, &rpcErr, tt.provider)
+	}
+}
+
+func TestRevert_MutabilityHint(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`))
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	fn := abi.ContractABI{
+		Type:    "function",
+		Name:    "claimable",
+		Outputs: []abi.ABIParameter{{Type: "uint256"}},
+	}
+
+	tests := []struct {
+		mutability string
+		constant   bool
+		hint       string
+	}{
+		{mutability: "view", hint: "claimable is declared view"},
+		{mutability: "pure", hint: "claimable is declared pure"},
+		{constant: true, hint: "claimable is declared view"},
+		{mutability: "nonpayable"},
+	}
+
+	for _, test := range tests {
+		fn.StateMutability = test.mutability
+		fn.Constant = test.constant
+
+		_, err := cli.ReadContractValues(ctx, testTokenAddr, fn, nil)
+		require.ErrorIs(t, err, ErrExecutionReverted)
+		if test.hint == "" {
+			assert.NotContains(t, err.Error(), "hint")
+			continue
+		}
+		assert.ErrorContains(t, err, test.hint)
+		assert.ErrorContains(t, err, "may not be read-safe")
+	}
+
+	// Reverts with a reason are intentional and get no hint
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted: not started","data":"0x08c379a0"}}`))
+	fn.StateMutability = "view"
+	_, err := cli.ReadContractValues(ctx, testTokenAddr, fn, nil)
+	require.ErrorIs(t, err, ErrExecutionReverted)
+	assert.NotContains(t, err.Error(), "hint")
+}
+
+func TestRevert_ReasonAndPanic(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	respond := func(data string) {
+		httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+			httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(
+				`{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted","data":%q}}`, data)))
+	}
+
+	balanceOf, err := loadFixtureABIs(t).Find("balanceOf")
+	require.NoError(t, err)
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	args := map[string]interface{}{"": testTokenAddr}
+
+	// Error("ERC20: transfer amount exceeds balance")
+	respond("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000026" +
+		"45524332303a207472616e7366657220616d6f756e7420657863656564732062" +
+		"616c616e63650000000000000000000000000000000000000000000000000000")
+	_, err = cli.ReadContract(ctx, testTokenAddr, *balanceOf, args)
+	require.ErrorIs(t, err, ErrExecutionReverted)
+	var revertErr *RevertError
+	require.ErrorAs(t, err, &revertErr)
+	assert.Equal(t, "ERC20: transfer amount exceeds balance", revertErr.Reason)
+	assert.EqualError(t, err, "execution reverted: ERC20: transfer amount exceeds balance")
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, 3, rpcErr.Code)
+
+	// Panic(0x11) from a checked subtraction
+	respond("0x4e487b71" + "0000000000000000000000000000000000000000000000000000000000000011")
+	_, err = cli.ReadContract(ctx, testTokenAddr, *balanceOf, args)
+	require.ErrorIs(t, err, ErrExecutionReverted)
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, big.NewInt(0x11), panicErr.Code)
+	assert.EqualError(t, err, "execution reverted: panic 0x11: arithmetic underflow or overflow")
+	assert.ErrorAs(t, err, &rpcErr)
+
+	respond("0x4e487b71" + "00000000000000000000000000000000000000000000000000000000000000ff")
+	_, err = cli.ReadContract(ctx, testTokenAddr, *balanceOf, args)
+	assert.EqualError(t, err, "execution reverted: panic 0xff: unknown panic code")
+
+	// Reasons are decoded along with declared custom errors
+	respond("0x4e487b71" + "0000000000000000000000000000000000000000000000000000000000000012")
+	_, err = cli.ReadContract(ctx, testTokenAddr, *balanceOf, args, WithErrorABIs(abi.ContractABI{Type: "error", Name: "Paused"}))
+	assert.EqualError(t, err, "execution reverted: panic 0x12: division or modulo by zero")
+
+	// Malformed reasons keep the error of the node
+	respond("0x08c379a0")
+	_, err = cli.ReadContract(ctx, testTokenAddr, *balanceOf, args)
+	require.ErrorIs(t, err, ErrExecutionReverted)
+	assert.ErrorAs(t, err, &rpcErr)
+}
@@ -0,0 +1,229 @@
+package contract
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// rpcRequest is a single JSON-RPC 2.0 request object
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+	ID      int           `json:"id"`
+}
+
+// rpcResponse is a single JSON-RPC 2.0 response object
+type rpcResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error,omitempty"`
+}
+
+// hexResult returns the result, which Ethereum methods always encode as a hex string.
+// Anything else usually means the URL doesn't point to an Ethereum JSON-RPC endpoint.
+func (r *rpcResponse) hexResult() (string, error) {
+	if len(r.Result) == 0 || string(r.Result) == "null" {
+		return "", fmt.Errorf("response has no result")
+	}
+
+	var result string
+	if err := json.Unmarshal(r.Result, &result); err != nil {
+		return "", fmt.Errorf("result is not a hex string: %s", snippet(r.Result))
+	}
+	return result, nil
+}
+
+// RPCError is the error object of a JSON-RPC 2.0 response, returned when the node answers a request with an error.
+// Errors of reverted calls are also ErrExecutionReverted, and keep the RPCError even once their revert data is decoded,
+// so it can be inspected with errors.As.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// revertData returns the revert data nodes attach to the error of a reverted eth_call as a hex string
+func (e *RPCError) revertData() ([]byte, bool) {
+	var s string
+	if err := json.Unmarshal(e.Data, &s); err != nil {
+		return nil, false
+	}
+	data, err := decodeData(s)
+	if err != nil || len(data) == 0 {
+		return nil, false
+	}
+	return data, true
+}
+
+// newEthCallRequest validates the contract address, validates and encodes the arguments and builds an eth_call request
+// against the block, with the value and the state overrides set by opts
+func (c *contractClient) newEthCallRequest(id int, addr string, abi abi.ContractABI, args map[string]interface{}, opts *callOptions) (rpcRequest, error) {
+	to, err := normalizeAddress(addr)
+	if err != nil {
+		return rpcRequest{}, fmt.Errorf("invalid contract address: %w", err)
+	}
+	block, err := opts.block()
+	if err != nil {
+		return rpcRequest{}, err
+	}
+	if err := checkValue(abi, opts.value); err != nil {
+		return rpcRequest{}, err
+	}
+	if err := c.validateInputs(abi, args); err != nil {
+		return rpcRequest{}, err
+	}
+
+	data, err := c.encodeData(abi, args)
+	if err != nil {
+		return rpcRequest{}, err
+	}
+
+	tx := map[string]string{
+		"to":   to,
+		"data": data,
+	}
+	if opts.value != nil {
+		tx["value"] = "0x" + opts.value.Text(16)
+	}
+
+	params := []interface{}{tx, block}
+	if len(opts.stateOverrides) > 0 {
+		overrides, err := stateOverrideSet(opts.stateOverrides)
+		if err != nil {
+			return rpcRequest{}, err
+		}
+		params = append(params, overrides)
+	}
+
+	return rpcRequest{
+		JSONRPC: "2.0",
+		Method:  "eth_call",
+		Params:  params,
+		ID:      id,
+	}, nil
+}
+
+// call issues a single JSON-RPC request and returns its result, or the RPC error the node answered with
+func (c *contractClient) call(ctx context.Context, method string, params ...interface{}) (string, error) {
+	return c.send(ctx, rpcRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      1,
+	})
+}
+
+// callJSON issues a single JSON-RPC request and returns its raw result, which may be null
+func (c *contractClient) callJSON(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
+	resp, _, err := c.roundTrip(ctx, rpcRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+// send issues req and returns its hex string result, or the RPC error the node answered with
+func (c *contractClient) send(ctx context.Context, req rpcRequest) (string, error) {
+	resp, body, err := c.roundTrip(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := resp.hexResult()
+	if err != nil {
+		return "", fmt.Errorf("invalid %s response %s: %w", req.Method, snippet(body), err)
+	}
+	return result, nil
+}
+
+// roundTrip issues req and returns the decoded response along with the raw body,
+// or the RPC error the node answered with
+func (c *contractClient) roundTrip(ctx context.Context, req rpcRequest) (*rpcResponse, []byte, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal %s request: %w", req.Method, err)
+	}
+
+	body, err := c.post(ctx, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resp rpcResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal %s response %s: %w", req.Method, snippet(body), err)
+	}
+	if resp.Error != nil {
+		return nil, nil, resp.Error
+	}
+	return &resp, body, nil
+}
+
+// post sends a JSON-RPC payload to the RPC endpoints and returns the raw response body
+func (c *contractClient) post(ctx context.Context, payload []byte) ([]byte, error) {
+	return c.transport.Call(ctx, payload)
+}
+
+// decodeQuantity decodes a QUANTITY returned by the node: 0x-prefixed hex in its shortest form,
+// with no leading zeros except for "0x0"
+func decodeQuantity(s string) (*big.Int, error) {
+	digits, ok := strings.CutPrefix(s, "0x")
+	if !ok || digits == "" {
+		return nil, fmt.Errorf("invalid hex quantity %q", s)
+	}
+	if len(digits) > 1 && digits[0] == '0' {
+		return nil, fmt.Errorf("invalid hex quantity %q: leading zeros", s)
+	}
+	// SetString alone would accept a sign, e.g. "0x-1"
+	if strings.Trim(digits, "0123456789abcdefABCDEF") != "" {
+		return nil, fmt.Errorf("invalid hex quantity %q", s)
+	}
+
+	value, ok := new(big.Int).SetString(digits, 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hex quantity %q", s)
+	}
+	return value, nil
+}
+
+// decodeData decodes DATA returned by the node: 0x-prefixed hex of whole bytes, "0x" being empty data
+func decodeData(s string) ([]byte, error) {
+	digits, ok := strings.CutPrefix(s, "0x")
+	if !ok {
+		return nil, fmt.Errorf("invalid hex data %s: missing 0x prefix", snippet([]byte(s)))
+	}
+	if len(digits)%2 != 0 {
+		return nil, fmt.Errorf("invalid hex data %s: odd length", snippet([]byte(s)))
+	}
+
+	data, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex data %s: %w", snippet([]byte(s)), err)
+	}
+	return data, nil
+}
+
+// snippet shortens raw response data for inclusion in error messages
+func snippet(b []byte) string {
+	const maxLen = 128
+	if len(b) > maxLen {
+		return fmt.Sprintf("%q...", b[:maxLen])
+	}
+	return fmt.Sprintf("%q", b)
+}
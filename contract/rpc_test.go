@@ -0,0 +1,104 @@
+package contract
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPC_NonHexResult(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+
+	tests := []struct {
+		body     string
+		expected []string
+	}{
+		{`{"jsonrpc":"2.0","id":1,"result":{"balance":"100"}}`, []string{"not a hex string", `{\"balance\":\"100\"}`}},
+		{`{"jsonrpc":"2.0","id":1,"result":42}`, []string{"not a hex string", "42"}},
+		// A REST API answering instead of a JSON-RPC node
+		{`{"status":"1","message":"OK","result":null}`, []string{"no result", `\"status\":\"1\"`}},
+		{`{"status":"1","message":"OK"}`, []string{"no result", `\"status\":\"1\"`}},
+		{`<html>Not Found</html>`, []string{"failed to unmarshal", "<html>Not Found</html>"}},
+	}
+
+	for _, tc := range tests {
+		httpmock.RegisterResponder(http.MethodPost, testRPCURL, httpmock.NewStringResponder(http.StatusOK, tc.body))
+
+		_, err := cli.ReadContract(ctx, testTokenAddr, erc20TotalSupplyABI, map[string]interface{}{})
+		require.Error(t, err, tc.body)
+		for _, expected := range tc.expected {
+			assert.Contains(t, err.Error(), expected, tc.body)
+		}
+	}
+}
+
+func TestRPC_ErrorResponse(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL, httpmock.NewStringResponder(http.StatusOK,
+		`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`))
+
+	_, err := cli.ReadContract(ctx, testTokenAddr, erc20TotalSupplyABI, map[string]interface{}{})
+	require.ErrorIs(t, err, ErrExecutionReverted)
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, -32000, rpcErr.Code)
+	assert.Equal(t, "execution reverted", rpcErr.Message)
+	assert.NotContains(t, err.Error(), "result")
+
+	// Other errors carry the code and message the node answered with
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL, httpmock.NewStringResponder(http.StatusOK,
+		`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"the method eth_foo does not exist/is not available"}}`))
+
+	_, err = cli.BlockNumber(ctx)
+	require.ErrorAs(t, err, &rpcErr)
+	assert.NotErrorIs(t, err, ErrExecutionReverted)
+	assert.Equal(t, -32601, rpcErr.Code)
+	assert.EqualError(t, err, "rpc error -32601: the method eth_foo does not exist/is not available")
+}
+
+func TestRPC_DecodeQuantity(t *testing.T) {
+	valid := map[string]int64{
+		"0x0":   0,
+		"0x1":   1,
+		"0x89":  137,
+		"0x400": 1024,
+	}
+	for s, expected := range valid {
+		value, err := decodeQuantity(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, value.Int64(), s)
+	}
+
+	for _, s := range []string{"0x", "0x0400", "0x00", "400", "", "0xzz", "0X1", "0x-1", "0x+1", "0x1_0"} {
+		_, err := decodeQuantity(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRPC_DecodeData(t *testing.T) {
+	data, err := decodeData("0x")
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	data, err = decodeData("0x0041")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x41}, data)
+
+	for _, s := range []string{"0x041", "0041", "0xzz", ""} {
+		_, err := decodeData(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,92 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// TokenStandard is a token interface standard a contract implements
+type TokenStandard int
+
+const (
+	// TokenStandardUnknown means the contract implements none of the detected standards
+	TokenStandardUnknown TokenStandard = iota
+	// TokenStandardERC20 is a fungible token
+	TokenStandardERC20
+	// TokenStandardERC721 is a non-fungible token
+	TokenStandardERC721
+	// TokenStandardERC1155 is a multi token
+	TokenStandardERC1155
+)
+
+func (s TokenStandard) String() string {
+	switch s {
+	case TokenStandardERC20:
+		return "ERC20"
+	case TokenStandardERC721:
+		return "ERC721"
+	case TokenStandardERC1155:
+		return "ERC1155"
+	default:
+		return "unknown"
+	}
+}
+
+// ERC-165 interface IDs
+const (
+	erc165InterfaceID  = "0x01ffc9a7"
+	invalidInterfaceID = "0xffffffff"
+	erc721InterfaceID  = "0x80ac58cd"
+	erc1155InterfaceID = "0xd9b67a26"
+)
+
+var erc165SupportsInterfaceABI = abi.ContractABI{
+	Name:            "supportsInterface",
+	Type:            "function",
+	StateMutability: "view",
+	Inputs:          []abi.ABIParameter{{Name: "interfaceId", Type: "bytes4"}},
+	Outputs:         []abi.ABIParameter{{Type: "bool"}},
+}
+
+// DetectTokenStandard classifies the token contract at addr.
+// ERC-721 and ERC-1155 are detected through ERC-165 supportsInterface. Otherwise the contract is
+// considered ERC-20 when both totalSupply and decimals can be read.
+// All probes are sent in a single batch request. Probes which revert or return malformed data count
+// as unsupported, so only transport failures are returned as errors.
+func (c *contractClient) DetectTokenStandard(ctx context.Context, addr string) (TokenStandard, error) {
+	supportsInterface := func(id string) Call {
+		return Call{ABI: erc165SupportsInterfaceABI, Args: map[string]interface{}{"interfaceId": id}}
+	}
+	calls := []Call{
+		supportsInterface(erc165InterfaceID),
+		supportsInterface(invalidInterfaceID),
+		supportsInterface(erc721InterfaceID),
+		supportsInterface(erc1155InterfaceID),
+		{ABI: erc20TotalSupplyABI},
+		{ABI: erc20DecimalsABI},
+	}
+
+	results, err := c.ReadBatch(ctx, addr, calls)
+	if err != nil {
+		return TokenStandardUnknown, err
+	}
+
+	isTrue := func(r BatchResult) bool { return r.Err == nil && r.Result == "true" }
+	succeeded := func(r BatchResult) bool { return r.Err == nil }
+
+	// ERC-165 compliance requires answering false for the invalid interface ID
+	if isTrue(results[0]) && succeeded(results[1]) && !isTrue(results[1]) {
+		switch {
+		case isTrue(results[2]):
+			return TokenStandardERC721, nil
+		case isTrue(results[3]):
+			return TokenStandardERC1155, nil
+		}
+	}
+
+	if succeeded(results[4]) && succeeded(results[5]) {
+		return TokenStandardERC20, nil
+	}
+	return TokenStandardUnknown, nil
+}
@@ -0,0 +1,98 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	boolTrueWord  = "0x0000000000000000000000000000000000000000000000000000000000000001"
+	boolFalseWord = "0x0000000000000000000000000000000000000000000000000000000000000000"
+)
+
+// registerBatchCallResponder answers each eth_call of a batch with answer(calldata),
+// where an empty answer is returned as a revert.
+func registerBatchCallResponder(t *testing.T, answer func(data string) string) {
+	t.Helper()
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+
+			var batch []struct {
+				ID     int               `json:"id"`
+				Params []json.RawMessage `json:"params"`
+			}
+			require.NoError(t, json.Unmarshal(body, &batch))
+
+			resps := make([]map[string]interface{}, len(batch))
+			for i, r := range batch {
+				var callObj struct {
+					Data string `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(r.Params[0], &callObj))
+
+				resps[i] = map[string]interface{}{"jsonrpc": "2.0", "id": r.ID}
+				if result := answer(callObj.Data); result != "" {
+					resps[i]["result"] = result
+				} else {
+					resps[i]["error"] = map[string]interface{}{"code": -32000, "message": "execution reverted"}
+				}
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, resps)
+		})
+}
+
+// supportsInterfaceAnswer answers supportsInterface calls with true for the given interface IDs
+// and delegates other calls to fallback.
+func supportsInterfaceAnswer(ids []string, fallback map[string]string) func(string) string {
+	return func(data string) string {
+		if data[2:10] != "01ffc9a7" {
+			return fallback[data[2:10]]
+		}
+		for _, id := range ids {
+			if data[10:18] == id {
+				return boolTrueWord
+			}
+		}
+		return boolFalseWord
+	}
+}
+
+func TestContract_DetectTokenStandard(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	tests := []struct {
+		name     string
+		answer   func(string) string
+		expected TokenStandard
+	}{
+		{"erc721", supportsInterfaceAnswer([]string{"01ffc9a7", "80ac58cd"}, nil), TokenStandardERC721},
+		{"erc721 enumerable", supportsInterfaceAnswer([]string{"01ffc9a7", "80ac58cd"}, erc20Results), TokenStandardERC721},
+		{"erc1155", supportsInterfaceAnswer([]string{"01ffc9a7", "d9b67a26"}, nil), TokenStandardERC1155},
+		{"erc20", func(data string) string { return erc20Results[data[2:10]] }, TokenStandardERC20},
+		{"unknown", func(string) string { return "" }, TokenStandardUnknown},
+		// Claims to support every interface, including the invalid one
+		{"non compliant erc165", supportsInterfaceAnswer([]string{"01ffc9a7", "ffffffff", "80ac58cd"}, nil), TokenStandardUnknown},
+	}
+
+	cli := NewClient(testRPCURL)
+	for _, tc := range tests {
+		registerBatchCallResponder(t, tc.answer)
+
+		standard, err := cli.DetectTokenStandard(context.Background(), testTokenAddr)
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expected, standard, tc.name)
+	}
+	assert.Equal(t, len(tests), httpmock.GetTotalCallCount())
+	assert.Equal(t, "ERC1155", TokenStandardERC1155.String())
+}
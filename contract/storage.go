@@ -0,0 +1,111 @@
+package contract
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// GetStorageAt reads the 32-byte storage word at slot of the contract at addr.
+// The word is returned as 0x-prefixed hex padded to 64 characters.
+func (c *contractClient) GetStorageAt(ctx context.Context, addr, slot string) (string, error) {
+	result, err := c.call(ctx, "eth_getStorageAt", addr, slot, "latest")
+	if err != nil {
+		return "", err
+	}
+
+	word, err := decodeData(result)
+	if err != nil {
+		return "", err
+	}
+	if len(word) > wordSize {
+		return "", fmt.Errorf("invalid storage word length: %d", len(word))
+	}
+	return "0x" + hex.EncodeToString(leftPad(word)), nil
+}
+
+// ReadStorageVar reads the state variable varName of the contract at addr using its storage layout.
+// Only value types stored in place are supported: uintN, intN, bool, address, contract, enum and bytesN.
+// Values are decoded the same way as call results.
+func (c *contractClient) ReadStorageVar(ctx context.Context, addr string, layout *abi.StorageLayout, varName string) (interface{}, error) {
+	entry, typ, err := layout.Find(varName)
+	if err != nil {
+		return nil, err
+	}
+	if typ.Encoding != "inplace" {
+		return nil, fmt.Errorf("storage variable %s has unsupported encoding %q", varName, typ.Encoding)
+	}
+
+	size, err := strconv.Atoi(typ.NumberOfBytes)
+	if err != nil || size < 1 || size > wordSize {
+		return nil, fmt.Errorf("invalid size %q for storage variable %s", typ.NumberOfBytes, varName)
+	}
+	if entry.Offset < 0 || entry.Offset+size > wordSize {
+		return nil, fmt.Errorf("invalid offset %d for storage variable %s", entry.Offset, varName)
+	}
+	abiType, err := storageABIType(typ.Label, size)
+	if err != nil {
+		return nil, fmt.Errorf("storage variable %s: %w", varName, err)
+	}
+
+	slot, ok := new(big.Int).SetString(entry.Slot, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid slot %q for storage variable %s", entry.Slot, varName)
+	}
+	word, err := c.GetStorageAt(ctx, addr, "0x"+slot.Text(16))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(strings.TrimPrefix(word, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage word: %w", err)
+	}
+
+	// Packed variables are right aligned at offset bytes from the end of the slot
+	value := raw[wordSize-entry.Offset-size : wordSize-entry.Offset]
+	return c.decoder().decodeStatic(abiType, alignStorageValue(abiType, value))
+}
+
+// storageABIType maps a storage type label to the ABI type used to decode it
+func storageABIType(label string, size int) (string, error) {
+	switch {
+	case label == "address payable", strings.HasPrefix(label, "contract "):
+		return "address", nil
+	case strings.HasPrefix(label, "enum "):
+		return fmt.Sprintf("uint%d", size*8), nil
+	}
+
+	if _, _, ok := parseIntType(label); ok {
+		return label, nil
+	}
+	if _, ok := parseFixedBytesType(label); ok {
+		return label, nil
+	}
+	if label == "address" || label == "bool" {
+		return label, nil
+	}
+	return "", fmt.Errorf("unsupported storage type %q", label)
+}
+
+// alignStorageValue places packed storage bytes into an ABI word as the decoder expects it:
+// bytesN left aligned, signed integers sign extended and everything else right aligned.
+func alignStorageValue(typ string, value []byte) []byte {
+	word := make([]byte, wordSize)
+	if _, ok := parseFixedBytesType(typ); ok {
+		copy(word, value)
+		return word
+	}
+
+	if signed, _, _ := parseIntType(typ); signed && len(value) > 0 && value[0]&0x80 != 0 {
+		for i := range word {
+			word[i] = 0xff
+		}
+	}
+	copy(word[wordSize-len(value):], value)
+	return word
+}
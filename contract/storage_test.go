@@ -0,0 +1,96 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStorageLayout = `{
+  "storage": [
+    {"label": "owner", "contract": "Vault.sol:Vault", "slot": "0", "offset": 0, "type": "t_address"},
+    {"label": "paused", "contract": "Vault.sol:Vault", "slot": "0", "offset": 20, "type": "t_bool"},
+    {"label": "decimals", "contract": "Vault.sol:Vault", "slot": "0", "offset": 21, "type": "t_uint8"},
+    {"label": "totalAssets", "contract": "Vault.sol:Vault", "slot": "1", "offset": 0, "type": "t_uint256"},
+    {"label": "delta", "contract": "Vault.sol:Vault", "slot": "2", "offset": 0, "type": "t_int64"},
+    {"label": "tag", "contract": "Vault.sol:Vault", "slot": "2", "offset": 8, "type": "t_bytes4"},
+    {"label": "balances", "contract": "Vault.sol:Vault", "slot": "3", "offset": 0, "type": "t_mapping(t_address,t_uint256)"}
+  ],
+  "types": {
+    "t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+    "t_bool": {"encoding": "inplace", "label": "bool", "numberOfBytes": "1"},
+    "t_uint8": {"encoding": "inplace", "label": "uint8", "numberOfBytes": "1"},
+    "t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+    "t_int64": {"encoding": "inplace", "label": "int64", "numberOfBytes": "8"},
+    "t_bytes4": {"encoding": "inplace", "label": "bytes4", "numberOfBytes": "4"},
+    "t_mapping(t_address,t_uint256)": {"encoding": "mapping", "label": "mapping(address => uint256)", "numberOfBytes": "32"}
+  }
+}`
+
+func TestContract_ReadStorageVar(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	slots := map[string]string{
+		// decimals=18, paused=true, owner
+		"0x0": "0x000000000000000000001201a2327a938febf5fec13bacfb16ae10ecbc4cbdcf",
+		"0x1": "0x00000000000000000000000000000000000000000000000000000000000f4240",
+		// tag=0xdeadbeef, delta=-2
+		"0x2": "0x0000000000000000000000000000000000000000deadbeeffffffffffffffffe",
+	}
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+
+			var rpcReq rpcRequest
+			require.NoError(t, json.Unmarshal(body, &rpcReq))
+			require.Equal(t, "eth_getStorageAt", rpcReq.Method)
+
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      rpcReq.ID,
+				"result":  slots[rpcReq.Params[1].(string)],
+			})
+		})
+
+	layout, err := abi.ParseStorageLayout([]byte(testStorageLayout))
+	require.NoError(t, err)
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+	addr := "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+
+	tests := []struct {
+		name     string
+		expected interface{}
+	}{
+		{"owner", "0xa2327a938febf5fec13bacfb16ae10ecbc4cbdcf"},
+		{"paused", true},
+		{"decimals", big.NewInt(18)},
+		{"totalAssets", big.NewInt(1000000)},
+		{"delta", big.NewInt(-2)},
+		{"tag", []byte{0xde, 0xad, 0xbe, 0xef}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := cli.ReadStorageVar(ctx, addr, layout, test.name)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+
+	_, err = cli.ReadStorageVar(ctx, addr, layout, "balances")
+	require.ErrorContains(t, err, "unsupported encoding")
+
+	_, err = cli.ReadStorageVar(ctx, addr, layout, "missing")
+	require.ErrorContains(t, err, "not found")
+}
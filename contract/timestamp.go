@@ -0,0 +1,53 @@
+package contract
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// maxTimestamp is the last second of year 9999, beyond which a value is unlikely to be a timestamp
+var maxTimestamp = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC).Unix()
+
+// convertTimestamps replaces the decoded values of the outputs designated by keys with their time.Time.
+// A key matches an output by name, by index or by its fieldKeys key.
+func convertTimestamps(params []abi.ABIParameter, values []interface{}, keys []string) error {
+	fields := fieldKeys(params)
+	for _, key := range keys {
+		found := false
+		for i, param := range params {
+			if key != param.Name && key != fields[i] && key != strconv.Itoa(i) {
+				continue
+			}
+			found = true
+
+			ts, err := toTimestamp(values[i])
+			if err != nil {
+				return fmt.Errorf("failed to decode output %s as timestamp: %w", key, err)
+			}
+			values[i] = ts
+		}
+		if !found {
+			return fmt.Errorf("timestamp output %s not found", key)
+		}
+	}
+	return nil
+}
+
+// toTimestamp converts a decoded integer of Unix seconds to a UTC time
+func toTimestamp(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case *big.Int:
+		if v.Sign() < 0 || !v.IsInt64() || v.Int64() > maxTimestamp {
+			return time.Time{}, fmt.Errorf("%s is out of range for a timestamp", v)
+		}
+		return time.Unix(v.Int64(), 0).UTC(), nil
+	default:
+		return time.Time{}, fmt.Errorf("expected an integer, got %T", value)
+	}
+}
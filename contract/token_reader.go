@@ -0,0 +1,72 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// TokenReader reads ERC-20 amounts in human-readable units.
+// The decimals of each token are fetched once and cached for the lifetime of the reader.
+type TokenReader struct {
+	client ContractClient
+
+	mu       sync.RWMutex
+	decimals map[string]uint8
+}
+
+// Decimals returns the decimals of token, fetching them only on first use
+func (r *TokenReader) Decimals(ctx context.Context, token string) (uint8, error) {
+	key := strings.ToLower(token)
+
+	r.mu.RLock()
+	decimals, ok := r.decimals[key]
+	r.mu.RUnlock()
+	if ok {
+		return decimals, nil
+	}
+
+	// Concurrent first lookups may both fetch, which is harmless as the value never changes
+	decimals, err := NewERC20(r.client, token).Decimals(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	r.decimals[key] = decimals
+	r.mu.Unlock()
+	return decimals, nil
+}
+
+// BalanceOf returns the balance of holder formatted with the decimals of token, e.g. "1.5"
+func (r *TokenReader) BalanceOf(ctx context.Context, token, holder string) (string, error) {
+	decimals, err := r.Decimals(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	balance, err := NewERC20(r.client, token).BalanceOf(ctx, holder)
+	if err != nil {
+		return "", err
+	}
+	return FormatUnits(balance, int(decimals)), nil
+}
+
+// ParseAmount converts a human-readable amount of token into base units suitable for encoding.
+// It fails if the amount has more decimal places than the token supports.
+func (r *TokenReader) ParseAmount(ctx context.Context, token, amount string) (*big.Int, error) {
+	decimals, err := r.Decimals(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUnits(amount, int(decimals))
+}
+
+// NewTokenReader creates a TokenReader using client
+func NewTokenReader(client ContractClient) *TokenReader {
+	return &TokenReader{
+		client:   client,
+		decimals: make(map[string]uint8),
+	}
+}
@@ -0,0 +1,120 @@
+package contract
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+var (
+	// ErrReadOnlyFunction is returned when building a transaction calling a function declared view or pure
+	ErrReadOnlyFunction = errors.New("function is read-only")
+	// ErrNotPayable is returned when sending value to a function declared view, pure or nonpayable
+	ErrNotPayable = errors.New("function is not payable")
+)
+
+// TxParams holds the fields of an EIP-1559 transaction besides its recipient and calldata
+type TxParams struct {
+	ChainID              *big.Int
+	Nonce                uint64
+	GasLimit             uint64
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerGas         *big.Int
+	// Value is the wei sent to the function, which must be payable when it is positive
+	Value *big.Int
+}
+
+// SignTransaction builds an EIP-1559 transaction calling the function of the contract at addr with args,
+// signs it with key and returns the raw signed transaction as 0x-prefixed hex, ready for SendRawTransaction.
+// Functions declared view or pure fail with ErrReadOnlyFunction, and sending value to a function
+// not declared payable fails with ErrNotPayable.
+func (c *contractClient) SignTransaction(key *ecdsa.PrivateKey, addr string, abi abi.ContractABI, args map[string]interface{}, params TxParams) (string, error) {
+	if key == nil {
+		return "", fmt.Errorf("nil private key")
+	}
+	if mutability := declaredMutability(abi); mutability == "view" || mutability == "pure" {
+		return "", fmt.Errorf("%w: %s is declared %s", ErrReadOnlyFunction, abi.Name, mutability)
+	}
+	if err := checkValue(abi, params.Value); err != nil {
+		return "", err
+	}
+	if params.ChainID == nil || params.ChainID.Sign() <= 0 {
+		return "", fmt.Errorf("invalid chain ID: %v", params.ChainID)
+	}
+	if err := ValidateAddress(addr); err != nil {
+		return "", err
+	}
+	if err := c.validateInputs(abi, args); err != nil {
+		return "", err
+	}
+	data, err := c.encodeData(abi, args)
+	if err != nil {
+		return "", err
+	}
+	calldata, err := decodeData(data)
+	if err != nil {
+		return "", err
+	}
+
+	to := common.HexToAddress(addr)
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   params.ChainID,
+		Nonce:     params.Nonce,
+		GasTipCap: orZero(params.MaxPriorityFeePerGas),
+		GasFeeCap: orZero(params.MaxFeePerGas),
+		Gas:       params.GasLimit,
+		To:        &to,
+		Value:     orZero(params.Value),
+		Data:      calldata,
+	})
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(params.ChainID), key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	return "0x" + hex.EncodeToString(raw), nil
+}
+
+// SendTransaction signs the transaction like SignTransaction and broadcasts it with SendRawTransaction,
+// returning its hash
+func (c *contractClient) SendTransaction(ctx context.Context, key *ecdsa.PrivateKey, addr string, abi abi.ContractABI, args map[string]interface{}, params TxParams) (string, error) {
+	signedTx, err := c.SignTransaction(key, addr, abi, args, params)
+	if err != nil {
+		return "", err
+	}
+	return c.SendRawTransaction(ctx, signedTx)
+}
+
+// checkValue rejects a negative value, or a positive one for a function declared view, pure or nonpayable.
+// Functions of ABIs not declaring their mutability accept any value.
+func checkValue(fn abi.ContractABI, value *big.Int) error {
+	if value == nil || value.Sign() == 0 {
+		return nil
+	}
+	if value.Sign() < 0 {
+		return fmt.Errorf("invalid value: %s", value)
+	}
+	switch mutability := declaredMutability(fn); mutability {
+	case "view", "pure", "nonpayable":
+		return fmt.Errorf("%w: %s is declared %s", ErrNotPayable, fn.Name, mutability)
+	}
+	return nil
+}
+
+// orZero returns n, or zero when n is nil
+func orZero(n *big.Int) *big.Int {
+	if n == nil {
+		return new(big.Int)
+	}
+	return n
+}
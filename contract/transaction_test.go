@@ -0,0 +1,196 @@
+package contract
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSignedTransfer is the WMATIC transfer signed by TestTransaction_Sign
+const testSignedTransfer = "0x02f8b28189078506fc23ac0085174876e80082ea60940d500b1d8e8ef31e21c99d1db9a6444d3adf127080b844a9059cbb" +
+	"0000000000000000000000002791bca1f2de4661ed88a30c99a7a9449aa841740000000000000000000000000000000000000000000000000de0b6b3a7640000" +
+	"c080a0e337d8ef92c5d4e75cdb068f3d546b8dc82dd615923cb36257bdff19f7b50aefa00eae04579a6f1858edf9fb3b1d6e5f7242d84ffefba4af015d1115d7643738ef"
+
+func TestTransaction_Sign(t *testing.T) {
+	key, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.NoError(t, err)
+
+	contractABIs := loadFixtureABIs(t)
+	transfer, err := contractABIs.Find("transfer")
+	require.NoError(t, err)
+	args := map[string]interface{}{
+		"dst": "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174",
+		"wad": big.NewInt(1e18),
+	}
+	params := TxParams{
+		ChainID:              big.NewInt(137),
+		Nonce:                7,
+		GasLimit:             60000,
+		MaxPriorityFeePerGas: big.NewInt(30e9),
+		MaxFeePerGas:         big.NewInt(100e9),
+	}
+
+	cli := NewClient(testRPCURL)
+	signedTx, err := cli.SignTransaction(key, testWMATICAddr, *transfer, args, params)
+	require.NoError(t, err)
+	assert.Equal(t, testSignedTransfer, signedTx)
+
+	// Signing is deterministic
+	again, err := cli.SignTransaction(key, testWMATICAddr, *transfer, args, params)
+	require.NoError(t, err)
+	assert.Equal(t, signedTx, again)
+
+	raw, err := decodeData(signedTx)
+	require.NoError(t, err)
+	require.Equal(t, byte(0x02), raw[0])
+
+	var tx struct {
+		ChainID              *big.Int
+		Nonce                uint64
+		MaxPriorityFeePerGas *big.Int
+		MaxFeePerGas         *big.Int
+		GasLimit             uint64
+		To                   []byte
+		Value                *big.Int
+		Data                 []byte
+		AccessList           rlp.RawValue
+		V                    uint64
+		R, S                 *big.Int
+	}
+	require.NoError(t, rlp.DecodeBytes(raw[1:], &tx))
+	assert.Equal(t, params.ChainID, tx.ChainID)
+	assert.Equal(t, params.Nonce, tx.Nonce)
+	assert.Equal(t, params.MaxPriorityFeePerGas, tx.MaxPriorityFeePerGas)
+	assert.Equal(t, params.MaxFeePerGas, tx.MaxFeePerGas)
+	assert.Equal(t, params.GasLimit, tx.GasLimit)
+	assert.Equal(t, testWMATICAddr, "0x"+hex.EncodeToString(tx.To))
+	assert.Zero(t, tx.Value.Sign())
+	assert.Equal(t, "a9059cbb"+
+		"0000000000000000000000002791bca1f2de4661ed88a30c99a7a9449aa84174"+
+		"0000000000000000000000000000000000000000000000000de0b6b3a7640000", hex.EncodeToString(tx.Data))
+
+	// go-ethereum decodes the transaction and recovers the address of the key from its signature
+	var decoded types.Transaction
+	require.NoError(t, decoded.UnmarshalBinary(raw))
+	assert.Equal(t, uint8(types.DynamicFeeTxType), decoded.Type())
+	sender, err := types.Sender(types.LatestSignerForChainID(params.ChainID), &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", sender.Hex())
+
+	// Read-only functions can't be sent
+	balanceOf, err := contractABIs.Find("balanceOf")
+	require.NoError(t, err)
+	_, err = cli.SignTransaction(key, testWMATICAddr, *balanceOf, map[string]interface{}{"": testWMATICAddr}, params)
+	assert.ErrorIs(t, err, ErrReadOnlyFunction)
+
+	_, err = cli.SignTransaction(key, "0x1234", *transfer, args, params)
+	assert.Error(t, err)
+	_, err = cli.SignTransaction(key, testWMATICAddr, *transfer, args, TxParams{Nonce: 7})
+	assert.ErrorContains(t, err, "chain ID")
+	_, err = cli.SignTransaction(nil, testWMATICAddr, *transfer, args, params)
+	assert.Error(t, err)
+}
+
+func TestTransaction_Send(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var rpcReq rpcRequest
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"`+testTxHash+`"}`), nil
+		})
+
+	key, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.NoError(t, err)
+	contractABIs := loadFixtureABIs(t)
+	transfer, err := contractABIs.Find("transfer")
+	require.NoError(t, err)
+
+	txHash, err := NewClient(testRPCURL).SendTransaction(context.Background(), key, testWMATICAddr, *transfer, map[string]interface{}{
+		"dst": "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174",
+		"wad": big.NewInt(1e18),
+	}, TxParams{
+		ChainID:              big.NewInt(137),
+		Nonce:                7,
+		GasLimit:             60000,
+		MaxPriorityFeePerGas: big.NewInt(30e9),
+		MaxFeePerGas:         big.NewInt(100e9),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testTxHash, txHash)
+	assert.Equal(t, "eth_sendRawTransaction", rpcReq.Method)
+	assert.Equal(t, []interface{}{testSignedTransfer}, rpcReq.Params)
+}
+
+func TestTransaction_Value(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var rpcReq rpcRequest
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x"}`), nil
+		})
+
+	contractABIs := loadFixtureABIs(t)
+	deposit, err := contractABIs.Find("deposit")
+	require.NoError(t, err)
+	require.Equal(t, "payable", deposit.StateMutability)
+	withdraw, err := contractABIs.Find("withdraw")
+	require.NoError(t, err)
+	require.Equal(t, "nonpayable", withdraw.StateMutability)
+
+	// The value of a call is sent as a hex quantity
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+	_, err = cli.ReadContractValues(ctx, testWMATICAddr, *deposit, map[string]interface{}{}, WithValue(big.NewInt(1e18)))
+	require.NoError(t, err)
+	assert.Equal(t, "0xde0b6b3a7640000", rpcReq.Params[0].(map[string]interface{})["value"])
+
+	_, err = cli.ReadContractValues(ctx, testWMATICAddr, *deposit, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.NotContains(t, rpcReq.Params[0], "value")
+
+	// Only payable functions accept value
+	httpmock.ZeroCallCounters()
+	withdrawArgs := map[string]interface{}{"wad": big.NewInt(1)}
+	_, err = cli.ReadContractValues(ctx, testWMATICAddr, *withdraw, withdrawArgs, WithValue(big.NewInt(1)))
+	assert.ErrorIs(t, err, ErrNotPayable)
+	assert.Zero(t, httpmock.GetTotalCallCount())
+
+	_, err = cli.ReadContractValues(ctx, testWMATICAddr, *withdraw, withdrawArgs, WithValue(new(big.Int)))
+	assert.NoError(t, err)
+	_, err = cli.ReadContractValues(ctx, testWMATICAddr, *deposit, map[string]interface{}{}, WithValue(big.NewInt(-1)))
+	assert.ErrorContains(t, err, "invalid value")
+
+	// So do transactions
+	key, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.NoError(t, err)
+	params := TxParams{ChainID: big.NewInt(137), GasLimit: 60000, Value: big.NewInt(1e18)}
+
+	signedTx, err := cli.SignTransaction(key, testWMATICAddr, *deposit, map[string]interface{}{}, params)
+	require.NoError(t, err)
+	raw, err := decodeData(signedTx)
+	require.NoError(t, err)
+	var fields []rlp.RawValue
+	require.NoError(t, rlp.DecodeBytes(raw[1:], &fields))
+	var value *big.Int
+	require.NoError(t, rlp.DecodeBytes(fields[6], &value))
+	assert.Equal(t, params.Value, value)
+
+	_, err = cli.SignTransaction(key, testWMATICAddr, *withdraw, withdrawArgs, params)
+	assert.ErrorIs(t, err, ErrNotPayable)
+}
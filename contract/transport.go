@@ -0,0 +1,127 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rootwarp/vinculum/contract/internal/retry"
+)
+
+// ErrNoEndpointAvailable is returned when the circuit breaker of every endpoint is open
+var ErrNoEndpointAvailable = errors.New("no RPC endpoint available")
+
+// transport sends a JSON-RPC payload and returns the raw response body.
+// Wrappers retrying a call pass the same payload again, so implementations must not modify it
+// and must read it through a fresh reader on every call.
+type transport interface {
+	Call(ctx context.Context, payload []byte) ([]byte, error)
+}
+
+// httpTransport sends JSON-RPC payloads over HTTP POST
+type httpTransport struct {
+	url    string
+	client *http.Client
+}
+
+func (t *httpTransport) Call(ctx context.Context, payload []byte) ([]byte, error) {
+	// The body is read from the payload buffer, which also sets GetBody
+	// so round trippers retrying on their own can resend the full payload
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make RPC call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &retry.StatusError{StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
+
+// endpoint is an RPC endpoint with its optional circuit breaker
+type endpoint struct {
+	url       string
+	transport transport
+	breaker   *circuitBreaker
+}
+
+// failoverTransport tries its endpoints in order until one answers.
+// Endpoints whose circuit breaker is open are skipped.
+type failoverTransport struct {
+	endpoints []*endpoint
+}
+
+func (t *failoverTransport) Call(ctx context.Context, payload []byte) ([]byte, error) {
+	var lastErr error
+	for _, ep := range t.endpoints {
+		if ep.breaker != nil && !ep.breaker.allow() {
+			continue
+		}
+
+		body, err := ep.transport.Call(ctx, payload)
+		if err == nil {
+			if ep.breaker != nil {
+				ep.breaker.success()
+			}
+			return body, nil
+		}
+
+		// A cancelled request says nothing about the health of the endpoint
+		if ctx.Err() != nil {
+			if ep.breaker != nil {
+				ep.breaker.release()
+			}
+			return nil, err
+		}
+
+		if ep.breaker != nil {
+			ep.breaker.failure()
+		}
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		return nil, ErrNoEndpointAvailable
+	}
+	return nil, lastErr
+}
+
+// limitedTransport caps the number of requests in flight through next.
+// All operations of a client share it, so concurrent features together stay under the cap.
+type limitedTransport struct {
+	slots chan struct{}
+	next  transport
+}
+
+func newLimitedTransport(n int, next transport) *limitedTransport {
+	return &limitedTransport{
+		slots: make(chan struct{}, n),
+		next:  next,
+	}
+}
+
+func (t *limitedTransport) Call(ctx context.Context, payload []byte) ([]byte, error) {
+	select {
+	case t.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-t.slots }()
+
+	return t.next.Call(ctx, payload)
+}
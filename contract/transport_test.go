@@ -0,0 +1,227 @@
+package contract
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type traceKey struct{}
+
+func TestTransport_RoundTripper(t *testing.T) {
+	var traced []string
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		// The caller's context reaches the transport, e.g. to parent tracing spans
+		span, _ := req.Context().Value(traceKey{}).(string)
+		traced = append(traced, span)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"jsonrpc":"2.0","id":1,"result":"0x89"}`)),
+			Request:    req,
+		}, nil
+	})
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "span-1")
+
+	cli := NewClient(testRPCURL, WithTransport(rt))
+	chainID, err := cli.ChainID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(137), chainID)
+	assert.Equal(t, []string{"span-1"}, traced)
+
+	// The round tripper is combined with the settings of a custom client
+	httpClient := &http.Client{Timeout: time.Second}
+	cli = NewClient(testRPCURL, WithHTTPClient(httpClient), WithTransport(rt))
+	_, err = cli.ChainID(ctx)
+	require.NoError(t, err)
+	assert.Len(t, traced, 2)
+	assert.Nil(t, httpClient.Transport)
+}
+
+func TestTransport_MaxConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	release := make(chan struct{})
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"jsonrpc":"2.0","id":1,"result":"0x89"}`)),
+			Request:    req,
+		}, nil
+	})
+
+	cli := NewClient(testRPCURL, WithTransport(rt), WithMaxConcurrency(2))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 6)
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cli.BlockNumber(ctx)
+			errs <- err
+		}()
+	}
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return inFlight == 2
+	}, time.Second, time.Millisecond)
+
+	// Callers over the cap give up when their context ends
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err := cli.ChainID(waitCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, maxInFlight)
+}
+
+func TestTransport_RetryPayload(t *testing.T) {
+	const fallbackURL = "https://fallback.example.com"
+
+	var bodies []string
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+
+		if req.URL.String() == testRPCURL {
+			// Retry once on its own from a fresh copy of the body, then fail over
+			require.NotNil(t, req.GetBody)
+			retry, err := req.GetBody()
+			require.NoError(t, err)
+			body, err := io.ReadAll(retry)
+			require.NoError(t, err)
+			bodies = append(bodies, string(body))
+
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       io.NopCloser(strings.NewReader("")),
+				Request:    req,
+			}, nil
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"jsonrpc":"2.0","id":1,"result":"0x89"}`)),
+			Request:    req,
+		}, nil
+	})
+
+	cli := NewClient(testRPCURL, WithTransport(rt), WithFallbackURLs(fallbackURL))
+	chainID, err := cli.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(137), chainID)
+
+	// Every attempt carries the full payload, not what is left of a drained reader
+	require.Len(t, bodies, 3)
+	assert.Contains(t, bodies[0], `"method":"eth_chainId"`)
+	assert.Equal(t, bodies[0], bodies[1])
+	assert.Equal(t, bodies[0], bodies[2])
+}
+
+func TestTransport_Timeout(t *testing.T) {
+	// A hung endpoint never answers until the request is abandoned
+	hung := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	httpClient := &http.Client{Transport: hung}
+	cli := NewClient(testRPCURL, WithHTTPClient(httpClient), WithTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	_, err := cli.ChainID(context.Background())
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	// The injected client is left as configured
+	assert.Zero(t, httpClient.Timeout)
+}
+
+func TestTransport_Retry(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// The endpoint is overloaded twice before answering
+	statuses := []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			if len(statuses) > 0 {
+				status := statuses[0]
+				statuses = statuses[1:]
+				return httpmock.NewStringResponse(status, ""), nil
+			}
+			return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x89"}`), nil
+		})
+
+	ctx := context.Background()
+	cli := NewClient(testRPCURL, WithRetry(3, time.Millisecond))
+	chainID, err := cli.ChainID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(137), chainID)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+
+	// Attempts are bounded
+	httpmock.ZeroCallCounters()
+	statuses = []int{http.StatusBadGateway, http.StatusGatewayTimeout, http.StatusBadGateway}
+	_, err = cli.ChainID(ctx)
+	assert.ErrorContains(t, err, "unexpected status code: 502")
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+
+	// Other failures aren't retried
+	httpmock.ZeroCallCounters()
+	statuses = []int{http.StatusUnauthorized}
+	_, err = cli.ChainID(ctx)
+	assert.ErrorContains(t, err, "unexpected status code: 401")
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+
+	// The context deadline cuts the backoff short
+	httpmock.ZeroCallCounters()
+	statuses = []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+	slow := NewClient(testRPCURL, WithRetry(3, time.Hour))
+	deadlineCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = slow.ChainID(deadlineCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
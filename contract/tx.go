@@ -0,0 +1,75 @@
+package contract
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Node rejection reasons for submitted transactions. ErrExecutionReverted also reports reverted reads.
+var (
+	ErrNonceTooLow       = errors.New("nonce too low")
+	ErrUnderpriced       = errors.New("transaction underpriced")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrAlreadyKnown      = errors.New("transaction already known")
+	ErrExecutionReverted = errors.New("execution reverted")
+)
+
+// txRejections maps fragments of node error messages to rejection reasons.
+// Messages differ between clients, so matching is by substring.
+var txRejections = []struct {
+	fragment string
+	reason   error
+}{
+	{"nonce too low", ErrNonceTooLow},
+	{"underpriced", ErrUnderpriced},
+	{"fee cap less than block base fee", ErrUnderpriced},
+	{"max fee per gas less than block base fee", ErrUnderpriced},
+	{"insufficient funds", ErrInsufficientFunds},
+	{"already known", ErrAlreadyKnown},
+	{"known transaction", ErrAlreadyKnown},
+}
+
+// SendRawTransaction broadcasts a signed transaction via eth_sendRawTransaction and returns its hash.
+// Rejections by the node wrap one of the ErrNonceTooLow, ErrUnderpriced, ErrInsufficientFunds,
+// ErrAlreadyKnown or ErrExecutionReverted reasons when recognized, along with the original RPC error.
+func (c *contractClient) SendRawTransaction(ctx context.Context, signedTxHex string) (string, error) {
+	raw, err := toBytes(signedTxHex)
+	if err != nil {
+		return "", fmt.Errorf("invalid signed transaction: %w", err)
+	}
+	if len(raw) == 0 {
+		return "", fmt.Errorf("invalid signed transaction: empty")
+	}
+
+	txHash, err := c.call(ctx, "eth_sendRawTransaction", "0x"+hex.EncodeToString(raw))
+	if err != nil {
+		return "", classifyTxError(err)
+	}
+	if hash, err := decodeData(txHash); err != nil || len(hash) != 32 {
+		return "", fmt.Errorf("invalid transaction hash %q", txHash)
+	}
+	return txHash, nil
+}
+
+// classifyTxError wraps a node rejection with its recognized reason
+func classifyTxError(err error) error {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return err
+	}
+
+	msg := strings.ToLower(rpcErr.Message)
+	for _, r := range txRejections {
+		if strings.Contains(msg, r.fragment) {
+			return fmt.Errorf("transaction rejected: %w: %w", r.reason, err)
+		}
+	}
+	// Reverts are phrased differently by every provider
+	if isRevert(rpcErr) {
+		return fmt.Errorf("transaction rejected: %w: %w", ErrExecutionReverted, err)
+	}
+	return fmt.Errorf("transaction rejected: %w", err)
+}
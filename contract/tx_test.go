@@ -0,0 +1,69 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTxHash = "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"
+
+func TestTx_SendRawTransaction(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	ctx := context.Background()
+	cli := NewClient(testRPCURL)
+	signedTx := "0x02f87301808459682f00850c92a69c0082520894"
+
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"`+testTxHash+`"}`))
+
+	txHash, err := cli.SendRawTransaction(ctx, signedTx)
+	require.NoError(t, err)
+	assert.Equal(t, testTxHash, txHash)
+
+	// The transaction is sent as 0x-prefixed lowercase hex whatever the case of its prefix and digits
+	var rpcReq rpcRequest
+	httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+		func(req *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+			return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"`+testTxHash+`"}`), nil
+		})
+	for _, input := range []string{signedTx, "0X02F87301808459682F00850C92A69C0082520894", "02f87301808459682f00850c92a69c0082520894"} {
+		_, err = cli.SendRawTransaction(ctx, input)
+		require.NoError(t, err, input)
+		assert.Equal(t, []interface{}{signedTx}, rpcReq.Params, input)
+	}
+
+	tests := []struct {
+		message  string
+		expected error
+	}{
+		{"nonce too low: next nonce 5, tx nonce 4", ErrNonceTooLow},
+		{"replacement transaction underpriced", ErrUnderpriced},
+		{"transaction underpriced: tip needed 1, tip permitted 0", ErrUnderpriced},
+		{"insufficient funds for gas * price + value", ErrInsufficientFunds},
+		{"already known", ErrAlreadyKnown},
+		{"execution reverted: Ownable: caller is not the owner", ErrExecutionReverted},
+	}
+	for _, tc := range tests {
+		httpmock.RegisterResponder(http.MethodPost, testRPCURL,
+			httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"`+tc.message+`"}}`))
+
+		_, err := cli.SendRawTransaction(ctx, signedTx)
+		require.ErrorIs(t, err, tc.expected, tc.message)
+		assert.Contains(t, err.Error(), tc.message)
+
+		var rpcErr *RPCError
+		require.ErrorAs(t, err, &rpcErr)
+	}
+
+	_, err = cli.SendRawTransaction(ctx, "0xzz")
+	require.ErrorContains(t, err, "invalid signed transaction")
+}
@@ -0,0 +1,139 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// ErrTypeMismatch is returned by Read and the accessors of CallResult when an output can't be returned as the requested Go type
+var ErrTypeMismatch = errors.New("output type mismatch")
+
+var (
+	bigIntType  = reflect.TypeOf((*big.Int)(nil))
+	uint256Type = reflect.TypeOf(Uint256{})
+	bytesType   = reflect.TypeOf([]byte(nil))
+	arrayType   = reflect.TypeOf([]interface{}(nil))
+	tupleType   = reflect.TypeOf(map[string]interface{}(nil))
+)
+
+// Read reads a function with a single output and returns it as T, saving type assertions on the decoded value.
+// T must be able to hold every value of the output type, otherwise Read fails with ErrTypeMismatch before calling the node:
+//   - uintN and intN as *big.Int, Uint256 for uintN, or a Go integer type at least as wide, e.g. uint8 for uint8 but not uint256
+//   - bool as bool, address and string as string, bytes as []byte, bytesN as []byte or [N]byte
+//   - arrays as []interface{} and tuples as map[string]interface{}, keyed like ReadContractMap
+//   - any output as interface{}
+func Read[T any](ctx context.Context, client ContractClient, addr string, fn abi.ContractABI, args map[string]interface{}) (T, error) {
+	var zero T
+	if len(fn.Outputs) != 1 {
+		return zero, fmt.Errorf("%w: %s has %d outputs, Read needs exactly one", ErrTypeMismatch, fn.Name, len(fn.Outputs))
+	}
+	output := fn.Outputs[0]
+	target := reflect.TypeOf(&zero).Elem()
+	if !readCompatible(canonicalType(output.Type), target) {
+		return zero, fmt.Errorf("%w: %s output of %s can't be read as %s", ErrTypeMismatch, output.Type, fn.Name, target)
+	}
+
+	var value interface{}
+	if output.Type == "tuple" {
+		// Single tuple outputs are flattened into their components, which the map keys
+		fields, err := client.ReadContractMap(ctx, addr, fn, args)
+		if err != nil {
+			return zero, err
+		}
+		value = fields
+	} else {
+		values, err := client.ReadContractValues(ctx, addr, fn, args)
+		if err != nil {
+			return zero, err
+		}
+		value = values[0]
+	}
+
+	converted, err := convertRead(value, target)
+	if err != nil {
+		return zero, fmt.Errorf("failed to read %s as %s: %w", fn.Name, target, err)
+	}
+	return converted.Interface().(T), nil
+}
+
+// readCompatible reports whether every value of the ABI type typ can be returned as target
+func readCompatible(typ string, target reflect.Type) bool {
+	if target.Kind() == reflect.Interface && target.NumMethod() == 0 {
+		return true
+	}
+
+	switch typ {
+	case "bool":
+		return target.Kind() == reflect.Bool
+	case "address", "string":
+		return target.Kind() == reflect.String
+	case "bytes":
+		return target == bytesType
+	case "tuple":
+		return target == tupleType
+	}
+
+	if _, _, ok := parseArrayType(typ); ok {
+		return target == arrayType
+	}
+	if size, ok := parseFixedBytesType(typ); ok {
+		return target == bytesType || (target.Kind() == reflect.Array && target.Elem().Kind() == reflect.Uint8 && target.Len() == size)
+	}
+	if signed, bits, ok := parseIntType(typ); ok {
+		switch target.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			// An intN fits in the same width, a uintN needs one more bit
+			return (signed && bits <= target.Bits()) || (!signed && bits < target.Bits())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return !signed && bits <= target.Bits()
+		}
+		return target == bigIntType || (!signed && target == uint256Type)
+	}
+	return false
+}
+
+// convertRead converts a decoded value to target, which readCompatible accepted for its type
+func convertRead(value interface{}, target reflect.Type) (reflect.Value, error) {
+	if target.Kind() == reflect.Interface {
+		if value == nil {
+			return reflect.Zero(target), nil
+		}
+		return reflect.ValueOf(value), nil
+	}
+
+	switch v := value.(type) {
+	case *big.Int:
+		switch {
+		case target == bigIntType:
+			return reflect.ValueOf(v), nil
+		case target == uint256Type:
+			u, err := NewUint256(v)
+			return reflect.ValueOf(u), err
+		case target.Kind() >= reflect.Int && target.Kind() <= reflect.Int64:
+			converted := reflect.New(target).Elem()
+			converted.SetInt(v.Int64())
+			return converted, nil
+		default:
+			converted := reflect.New(target).Elem()
+			converted.SetUint(v.Uint64())
+			return converted, nil
+		}
+	case []byte:
+		if target.Kind() == reflect.Array {
+			converted := reflect.New(target).Elem()
+			reflect.Copy(converted, reflect.ValueOf(v))
+			return converted, nil
+		}
+	}
+
+	converted := reflect.ValueOf(value)
+	if !converted.IsValid() || !converted.Type().ConvertibleTo(target) {
+		return reflect.Value{}, fmt.Errorf("%w: decoded %T", ErrTypeMismatch, value)
+	}
+	return converted.Convert(target), nil
+}
@@ -0,0 +1,86 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTyped_Read(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	domainSeparator := abi.ContractABI{
+		Type:    "function",
+		Name:    "DOMAIN_SEPARATOR",
+		Outputs: []abi.ABIParameter{{Type: "bytes32"}},
+	}
+	results := map[string]string{
+		"3644e515": "0x8f1b0b3d7fdbd4c9c9b4e1d6d5a2dd3b0a6d4e3c2b1a09f8e7d6c5b4a3928170",
+	}
+	for methodID, result := range erc20Results {
+		results[methodID] = result
+	}
+	calls := registerEthCallResponder(t, results)
+
+	contractABIs := loadFixtureABIs(t)
+	find := func(name string) abi.ContractABI {
+		fn, err := contractABIs.Find(name)
+		require.NoError(t, err)
+		return *fn
+	}
+
+	cli := NewClient(testRPCURL)
+	ctx := context.Background()
+
+	decimals, err := Read[uint8](ctx, cli, testTokenAddr, find("decimals"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(6), decimals)
+
+	wideDecimals, err := Read[int](ctx, cli, testTokenAddr, find("decimals"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 6, wideDecimals)
+
+	supply, err := Read[*big.Int](ctx, cli, testTokenAddr, find("totalSupply"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, new(big.Int).Lsh(big.NewInt(1), 70), supply)
+
+	supply256, err := Read[Uint256](ctx, cli, testTokenAddr, find("totalSupply"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "1180591620717411303424", supply256.String())
+
+	name, err := Read[string](ctx, cli, testTokenAddr, find("name"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "USD Coin", name)
+
+	separator, err := Read[[32]byte](ctx, cli, testTokenAddr, domainSeparator, nil)
+	require.NoError(t, err)
+	assert.Equal(t, byte(0x8f), separator[0])
+	assert.Equal(t, byte(0x70), separator[31])
+
+	anything, err := Read[interface{}](ctx, cli, testTokenAddr, find("symbol"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "USDC", anything)
+
+	// Mismatches fail before calling the node
+	before := calls["313ce567"] + calls["18160ddd"] + calls["06fdde03"]
+	_, err = Read[bool](ctx, cli, testTokenAddr, find("decimals"), nil)
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	_, err = Read[uint64](ctx, cli, testTokenAddr, find("totalSupply"), nil)
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	_, err = Read[int8](ctx, cli, testTokenAddr, find("decimals"), nil)
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	_, err = Read[*big.Int](ctx, cli, testTokenAddr, find("name"), nil)
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	_, err = Read[[20]byte](ctx, cli, testTokenAddr, domainSeparator, nil)
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	assert.Equal(t, before, calls["313ce567"]+calls["18160ddd"]+calls["06fdde03"])
+
+	_, err = Read[string](ctx, cli, testTokenAddr, find("transfer"), map[string]interface{}{})
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+}
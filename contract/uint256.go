@@ -0,0 +1,118 @@
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/rootwarp/vinculum/contract/abi"
+)
+
+// ErrUint256Range is returned for values which don't fit in a Uint256
+var ErrUint256Range = errors.New("value out of range for uint256")
+
+// maxUint256 is 2^256 - 1
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// Uint256 is an exact unsigned 256-bit integer, as returned for uintN outputs read WithUint256Outputs.
+// It is immutable, the zero value is 0, and it marshals to JSON as a decimal string.
+type Uint256 struct {
+	v *big.Int
+}
+
+// NewUint256 returns x as a Uint256, or ErrUint256Range when it is negative or exceeds 2^256 - 1
+func NewUint256(x *big.Int) (Uint256, error) {
+	if x.Sign() < 0 || x.Cmp(maxUint256) > 0 {
+		return Uint256{}, fmt.Errorf("%w: %s", ErrUint256Range, x)
+	}
+	return Uint256{v: new(big.Int).Set(x)}, nil
+}
+
+// Big returns the value as a new *big.Int
+func (u Uint256) Big() *big.Int {
+	if u.v == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(u.v)
+}
+
+// Add returns u + y, or ErrUint256Range when the sum overflows
+func (u Uint256) Add(y Uint256) (Uint256, error) {
+	return NewUint256(new(big.Int).Add(u.Big(), y.Big()))
+}
+
+// Cmp compares u and y and returns -1, 0 or +1 like big.Int.Cmp
+func (u Uint256) Cmp(y Uint256) int {
+	return u.Big().Cmp(y.Big())
+}
+
+// Float64 returns the nearest float64 value, which is inexact beyond 2^53
+func (u Uint256) Float64() float64 {
+	f, _ := new(big.Float).SetInt(u.Big()).Float64()
+	return f
+}
+
+// String returns the value in decimal
+func (u Uint256) String() string {
+	return u.Big().String()
+}
+
+// Hex returns the value as 0x-prefixed hex without leading zeros, e.g. "0x0"
+func (u Uint256) Hex() string {
+	return "0x" + u.Big().Text(16)
+}
+
+// MarshalJSON encodes the value as a decimal string, which keeps every digit in any JSON consumer
+func (u Uint256) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON decodes a decimal or 0x-prefixed hex string, or a JSON number
+func (u *Uint256) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	x, ok := new(big.Int), false
+	if digits, isHex := strings.CutPrefix(s, "0x"); isHex {
+		x, ok = x.SetString(digits, 16)
+	} else {
+		x, ok = x.SetString(s, 10)
+	}
+	if !ok {
+		return fmt.Errorf("invalid uint256 %s", data)
+	}
+	value, err := NewUint256(x)
+	if err != nil {
+		return err
+	}
+	*u = value
+	return nil
+}
+
+// toUint256s replaces the *big.Int of uintN values of param, including within arrays and tuples, with Uint256
+func toUint256s(param abi.ABIParameter, value interface{}) interface{} {
+	if elem, _, ok := arrayElem(param); ok {
+		if elements, ok := value.([]interface{}); ok {
+			for i, element := range elements {
+				elements[i] = toUint256s(elem, element)
+			}
+		}
+		return value
+	}
+	if param.Type == "tuple" {
+		if fields, ok := value.(map[string]interface{}); ok {
+			for i, key := range fieldKeys(param.Components) {
+				fields[key] = toUint256s(param.Components[i], fields[key])
+			}
+		}
+		return value
+	}
+
+	if signed, _, ok := parseIntType(canonicalType(param.Type)); ok && !signed {
+		if x, ok := value.(*big.Int); ok {
+			// Decoded uintN values always fit
+			return Uint256{v: x}
+		}
+	}
+	return value
+}
@@ -0,0 +1,117 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/rootwarp/vinculum/contract/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUint256_Arithmetic(t *testing.T) {
+	a, err := NewUint256(big.NewInt(40))
+	require.NoError(t, err)
+	b, err := NewUint256(big.NewInt(2))
+	require.NoError(t, err)
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, "42", sum.String())
+	assert.Equal(t, "0x2a", sum.Hex())
+	assert.Equal(t, 42.0, sum.Float64())
+	assert.Equal(t, 1, sum.Cmp(a))
+	assert.Equal(t, 0, sum.Cmp(sum))
+	assert.Equal(t, big.NewInt(42), sum.Big())
+
+	// The zero value is 0
+	var zero Uint256
+	assert.Equal(t, "0", zero.String())
+	assert.Equal(t, "0x0", zero.Hex())
+
+	maxValue, err := NewUint256(maxUint256)
+	require.NoError(t, err)
+	_, err = maxValue.Add(b)
+	assert.ErrorIs(t, err, ErrUint256Range)
+	_, err = NewUint256(big.NewInt(-1))
+	assert.ErrorIs(t, err, ErrUint256Range)
+
+	// Values are immutable
+	x := big.NewInt(7)
+	u, err := NewUint256(x)
+	require.NoError(t, err)
+	x.SetInt64(8)
+	u.Big().SetInt64(9)
+	assert.Equal(t, "7", u.String())
+}
+
+func TestUint256_JSON(t *testing.T) {
+	maxValue, err := NewUint256(maxUint256)
+	require.NoError(t, err)
+
+	out, err := json.Marshal(map[string]Uint256{"supply": maxValue})
+	require.NoError(t, err)
+	assert.Equal(t, `{"supply":"115792089237316195423570985008687907853269984665640564039457584007913129639935"}`, string(out))
+
+	var decoded map[string]Uint256
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Equal(t, 0, maxValue.Cmp(decoded["supply"]))
+
+	var u Uint256
+	require.NoError(t, json.Unmarshal([]byte(`"0xff"`), &u))
+	assert.Equal(t, "255", u.String())
+	require.NoError(t, json.Unmarshal([]byte(`12`), &u))
+	assert.Equal(t, "12", u.String())
+	assert.Error(t, json.Unmarshal([]byte(`"-1"`), &u))
+	assert.Error(t, json.Unmarshal([]byte(`"1e3"`), &u))
+}
+
+func TestUint256_ReadOutputs(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// stats() returns (uint256 supply, int256 delta, uint64[] ids)
+	statsABI := abi.ContractABI{
+		Name: "stats",
+		Type: "function",
+		Outputs: []abi.ABIParameter{
+			{Name: "supply", Type: "uint256"},
+			{Name: "delta", Type: "int256"},
+			{Name: "ids", Type: "uint64[]"},
+		},
+	}
+	ret, err := EncodeReturn(statsABI.Outputs, []interface{}{
+		maxUint256,
+		big.NewInt(-1),
+		[]interface{}{big.NewInt(1), big.NewInt(2)},
+	})
+	require.NoError(t, err)
+	methodID, err := statsABI.MethodID()
+	require.NoError(t, err)
+	registerEthCallResponder(t, map[string]string{methodID: ret})
+
+	cli := NewClient(testRPCURL)
+	values, err := cli.ReadContractValues(context.Background(), testTokenAddr, statsABI, map[string]interface{}{}, WithUint256Outputs())
+	require.NoError(t, err)
+
+	supply, ok := values[0].(Uint256)
+	require.True(t, ok)
+	assert.Equal(t, maxUint256.String(), supply.String())
+	// Signed integers keep their sign
+	assert.Equal(t, big.NewInt(-1), values[1])
+	ids := values[2].([]interface{})
+	require.IsType(t, Uint256{}, ids[0])
+	assert.Equal(t, "2", ids[1].(Uint256).String())
+
+	// Uint256 is accepted back as an input
+	data, err := (&contractClient{}).encodeData(abi.ContractABI{
+		Type:   "function",
+		Name:   "burn",
+		Inputs: []abi.ABIParameter{{Name: "amount", Type: "uint256"}},
+	}, map[string]interface{}{"amount": supply})
+	require.NoError(t, err)
+	assert.Equal(t, "0x42966c68"+"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", data)
+}
@@ -0,0 +1,112 @@
+package contract
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// PrecisionMode controls how ParseUnitsWithPrecision treats inputs with more fractional digits than decimals
+type PrecisionMode int
+
+const (
+	// PrecisionError rejects inputs which cannot be represented exactly
+	PrecisionError PrecisionMode = iota
+	// PrecisionRound rounds the excess digits half away from zero
+	PrecisionRound
+	// PrecisionTruncate drops the excess digits
+	PrecisionTruncate
+)
+
+// FormatUnits converts an amount in base units into a decimal string with the given number of decimals.
+// Trailing fractional zeros are trimmed, e.g. 1500000 with 6 decimals formats as "1.5".
+func FormatUnits(raw *big.Int, decimals int) string {
+	if raw == nil {
+		return "0"
+	}
+
+	digits := new(big.Int).Abs(raw).String()
+	sign := ""
+	if raw.Sign() < 0 {
+		sign = "-"
+	}
+	if decimals <= 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole := digits[:len(digits)-decimals]
+	frac := strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
+
+// ParseUnits converts a decimal string into base units with the given number of decimals.
+// It returns an error when s has more fractional digits than decimals rather than losing precision.
+func ParseUnits(s string, decimals int) (*big.Int, error) {
+	return ParseUnitsWithPrecision(s, decimals, PrecisionError)
+}
+
+// ParseUnitsWithPrecision converts a decimal string into base units with the given number of decimals,
+// handling fractional digits beyond decimals according to mode.
+func ParseUnitsWithPrecision(s string, decimals int, mode PrecisionMode) (*big.Int, error) {
+	if decimals < 0 {
+		return nil, fmt.Errorf("invalid decimals: %d", decimals)
+	}
+
+	str := strings.TrimSpace(s)
+	negative := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(str, "-")
+
+	whole, frac, _ := strings.Cut(str, ".")
+	if whole == "" && frac == "" {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+
+	roundUp := false
+	if len(frac) > decimals {
+		excess := frac[decimals:]
+		frac = frac[:decimals]
+
+		switch mode {
+		case PrecisionError:
+			if strings.TrimRight(excess, "0") != "" {
+				return nil, fmt.Errorf("amount %q has more than %d decimal places", s, decimals)
+			}
+		case PrecisionRound:
+			roundUp = excess[0] >= '5'
+		case PrecisionTruncate:
+		default:
+			return nil, fmt.Errorf("unknown precision mode: %d", mode)
+		}
+	}
+	frac += strings.Repeat("0", decimals-len(frac))
+
+	value, ok := new(big.Int).SetString("0"+whole+frac, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	if roundUp {
+		value.Add(value, big.NewInt(1))
+	}
+	if negative {
+		value.Neg(value)
+	}
+	return value, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnits_Format(t *testing.T) {
+	oneEther, _ := new(big.Int).SetString("1000000000000000000", 10)
+	assert.Equal(t, "1", FormatUnits(oneEther, 18))
+
+	large, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	assert.Equal(t, "123456789012.34567890123456789", FormatUnits(large, 18))
+
+	assert.Equal(t, "1.5", FormatUnits(big.NewInt(1500000), 6))
+	assert.Equal(t, "0.000001", FormatUnits(big.NewInt(1), 6))
+	assert.Equal(t, "-2.25", FormatUnits(big.NewInt(-2250000), 6))
+	assert.Equal(t, "0", FormatUnits(big.NewInt(0), 6))
+}
+
+func TestUnits_Parse(t *testing.T) {
+	tests := []struct {
+		input    string
+		decimals int
+		expected string
+	}{
+		// Exact number of decimal places
+		{"1.123456", 6, "1123456"},
+		{"1.000000000000000001", 18, "1000000000000000001"},
+		// Fewer decimal places
+		{"1.5", 6, "1500000"},
+		{"1", 18, "1000000000000000000"},
+		{".5", 6, "500000"},
+		{"-2.25", 6, "-2250000"},
+		// Larger than int64
+		{"123456789012.34567890123456789", 18, "123456789012345678901234567890"},
+		// Excess digits which are zero lose nothing
+		{"1.1000000", 6, "1100000"},
+	}
+
+	for _, tc := range tests {
+		value, err := ParseUnits(tc.input, tc.decimals)
+		require.NoError(t, err, tc.input)
+		assert.Equal(t, tc.expected, value.String(), tc.input)
+	}
+
+	// More decimal places than decimals
+	_, err := ParseUnits("1.123456789", 6)
+	require.Error(t, err)
+
+	for _, invalid := range []string{"", ".", "1.2.3", "abc", "1e18", "--1"} {
+		_, err := ParseUnits(invalid, 18)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestUnits_ParseWithPrecision(t *testing.T) {
+	value, err := ParseUnitsWithPrecision("1.1234565", 6, PrecisionRound)
+	require.NoError(t, err)
+	assert.Equal(t, "1123457", value.String())
+
+	value, err = ParseUnitsWithPrecision("1.1234564", 6, PrecisionRound)
+	require.NoError(t, err)
+	assert.Equal(t, "1123456", value.String())
+
+	value, err = ParseUnitsWithPrecision("-1.1234565", 6, PrecisionRound)
+	require.NoError(t, err)
+	assert.Equal(t, "-1123457", value.String())
+
+	value, err = ParseUnitsWithPrecision("1.123456789", 6, PrecisionTruncate)
+	require.NoError(t, err)
+	assert.Equal(t, "1123456", value.String())
+
+	_, err = ParseUnitsWithPrecision("1.123456789", 6, PrecisionError)
+	require.Error(t, err)
+}
+
+func TestUnits_RoundTrip(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals int
+		raw      string
+	}{
+		// WMATIC and other 18 decimal tokens
+		{"0.000000000000000001", 18, "1"},
+		{"1234.5678", 18, "1234567800000000000000"},
+		{"115792089237316195423570985008687907853269984665640564039457.584007913129639935", 18,
+			"115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		// USDC
+		{"0.01", 6, "10000"},
+		{"2500.75", 6, "2500750000"},
+		{"9223372036854.775808", 6, "9223372036854775808"},
+	}
+
+	for _, tc := range tests {
+		raw, err := ParseUnits(tc.amount, tc.decimals)
+		require.NoError(t, err, tc.amount)
+		assert.Equal(t, tc.raw, raw.String(), tc.amount)
+		assert.Equal(t, tc.amount, FormatUnits(raw, tc.decimals), tc.amount)
+	}
+
+	// A USDC amount can't be more precise than a micro-dollar
+	_, err := ParseUnits("0.0000001", 6)
+	assert.ErrorContains(t, err, "more than 6 decimal places")
+}
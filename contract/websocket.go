@@ -0,0 +1,121 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsTransport sends JSON-RPC payloads over a persistent WebSocket connection.
+// The connection is dialed on the first call and dialed again after it fails.
+// Calls take turns on the connection, each reading the next message as its response.
+type wsTransport struct {
+	url     string
+	dialer  *websocket.Dialer
+	timeout time.Duration
+
+	// turn is held by the call using the connection, and waiting for it respects the context of the call
+	turn chan struct{}
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func newWSTransport(url string, timeout time.Duration) *wsTransport {
+	return &wsTransport{
+		url:     url,
+		dialer:  websocket.DefaultDialer,
+		timeout: timeout,
+		turn:    make(chan struct{}, 1),
+	}
+}
+
+// isWebSocketURL reports whether rawURL has a ws or wss scheme
+func isWebSocketURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return scheme == "ws" || scheme == "wss"
+}
+
+func (t *wsTransport) Call(ctx context.Context, payload []byte) ([]byte, error) {
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
+	select {
+	case t.turn <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-t.turn }()
+
+	conn, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ending the context interrupts the pending write or read by expiring the connection deadline
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetReadDeadline(time.Unix(1, 0))
+		conn.SetWriteDeadline(time.Unix(1, 0))
+	})
+
+	body, err := t.roundTrip(conn, payload)
+	interrupted := !stop()
+	if err != nil || interrupted {
+		// The connection is dropped after a failure or an interruption, which expired its deadline
+		// and may leave a late response for the next call to read
+		t.close(conn)
+	}
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return body, err
+}
+
+func (t *wsTransport) roundTrip(conn *websocket.Conn, payload []byte) ([]byte, error) {
+	if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+		return nil, fmt.Errorf("failed to make RPC call: %w", err)
+	}
+	_, body, err := conn.ReadMessage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// connect returns the open connection, dialing it if needed
+func (t *wsTransport) connect(ctx context.Context) (*websocket.Conn, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn != nil {
+		return t.conn, nil
+	}
+	conn, _, err := t.dialer.DialContext(ctx, t.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", t.url, err)
+	}
+	t.conn = conn
+	return conn, nil
+}
+
+// close closes conn and forgets it, so the next call dials again
+func (t *wsTransport) close(conn *websocket.Conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	conn.Close()
+	if t.conn == conn {
+		t.conn = nil
+	}
+}
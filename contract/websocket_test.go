@@ -0,0 +1,100 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWSServer starts a WebSocket JSON-RPC server answering the nth request it receives, counting from 0,
+// with the result returned by answer after its delay.
+// It returns the ws:// URL of the server and the number of connections it accepted.
+func newWSServer(t *testing.T, answer func(n int32) (string, time.Duration)) (string, *int32) {
+	var connections, requests int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		atomic.AddInt32(&connections, 1)
+
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req rpcRequest
+			require.NoError(t, json.Unmarshal(msg, &req))
+			result, delay := answer(atomic.AddInt32(&requests, 1) - 1)
+			time.Sleep(delay)
+
+			resp, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+			require.NoError(t, err)
+			if conn.WriteMessage(websocket.TextMessage, resp) != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http"), &connections
+}
+
+func TestWebSocket_ReadContract(t *testing.T) {
+	url, connections := newWSServer(t, func(int32) (string, time.Duration) {
+		return "0x0000000000000000000000000000000000000000000000000000000000000012", 0
+	})
+	assert.True(t, isWebSocketURL(url))
+	assert.True(t, isWebSocketURL("WSS://polygon.example.com"))
+	assert.False(t, isWebSocketURL(testRPCURL))
+
+	contractABIs := loadFixtureABIs(t)
+	decimals, err := contractABIs.Find("decimals")
+	require.NoError(t, err)
+
+	// Reads go over a single connection
+	ctx := context.Background()
+	cli := NewClient(url)
+	for i := 0; i < 3; i++ {
+		ret, err := cli.ReadContract(ctx, testWMATICAddr, *decimals, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, "18", ret)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(connections))
+}
+
+func TestWebSocket_Timeout(t *testing.T) {
+	// Only the first request is answered late
+	url, connections := newWSServer(t, func(n int32) (string, time.Duration) {
+		if n == 0 {
+			return "0x0000000000000000000000000000000000000000000000000000000000000012", 200 * time.Millisecond
+		}
+		return "0x0000000000000000000000000000000000000000000000000000000000000006", 0
+	})
+
+	contractABIs := loadFixtureABIs(t)
+	decimals, err := contractABIs.Find("decimals")
+	require.NoError(t, err)
+
+	// An interrupted call drops its connection so its late response can't be read by the next call
+	cli := NewClient(url, WithTimeout(50*time.Millisecond))
+	_, err = cli.ReadContract(context.Background(), testWMATICAddr, *decimals, map[string]interface{}{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ret, err := cli.ReadContract(context.Background(), testWMATICAddr, *decimals, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "6", ret)
+	assert.Equal(t, int32(2), atomic.LoadInt32(connections))
+
+	_, err = NewClient("ws://127.0.0.1:1").ReadContract(context.Background(), testWMATICAddr, *decimals, map[string]interface{}{})
+	assert.ErrorContains(t, err, "failed to connect")
+}